@@ -0,0 +1,98 @@
+package rendezvous
+
+import (
+	"math"
+)
+
+// A BalanceReport describes how keys are spread over a ring's active nodes
+// compared with their weights, see AnalyzeBalance.
+type BalanceReport struct {
+	// Keys is the number of analyzed keys.
+	Keys int
+	// Nodes holds the balance of every active node, sorted by name.
+	Nodes []NodeBalance
+	// StdDev is the standard deviation of the nodes' ratios; 0 means every
+	// node received exactly its expected share.
+	StdDev float64
+	// MinRatio and MaxRatio are the lowest and highest ratios of any node.
+	MinRatio, MaxRatio float64
+}
+
+// A NodeBalance describes the keys assigned to a single node.
+type NodeBalance struct {
+	Name string
+	// Keys is the number of keys assigned to the node.
+	Keys int
+	// Share is the fraction of keys assigned to the node.
+	Share float64
+	// ExpectedShare is the node's fraction of the total active weight.
+	ExpectedShare float64
+	// Ratio is Share divided by ExpectedShare.
+	Ratio float64
+}
+
+// AnalyzeBalance looks up every key against the same membership snapshot
+// and reports how evenly the keys are spread relative to the node weights.
+func (r *Ring) AnalyzeBalance(keys []string) BalanceReport {
+	return r.analyzeBalance(len(keys), func(i int) uint64 {
+		return r.computeHash(keys[i])
+	})
+}
+
+// AnalyzeBalanceN is like AnalyzeBalance for n synthetic, uniformly
+// distributed key hashes, which is enough to validate weights when no
+// representative keys are at hand.
+func (r *Ring) AnalyzeBalanceN(n int) BalanceReport {
+	return r.analyzeBalance(n, func(i int) uint64 {
+		return mix64(uint64(i))
+	})
+}
+
+func (r *Ring) analyzeBalance(n int, keyHash func(i int) uint64) BalanceReport {
+	nodes := r.load()
+
+	report := BalanceReport{Keys: n, Nodes: make([]NodeBalance, 0, len(nodes))}
+	index := make(map[*Node]int, len(nodes))
+	var totalWeight float64
+	for _, node := range nodes {
+		if active(node) {
+			index[node] = len(report.Nodes)
+			report.Nodes = append(report.Nodes, NodeBalance{
+				Name:          node.name,
+				ExpectedShare: node.weight,
+			})
+			totalWeight += node.weight
+		}
+	}
+	for i := range report.Nodes {
+		report.Nodes[i].ExpectedShare /= totalWeight
+	}
+
+	for i := 0; i < n; i++ {
+		if ix, ok := index[r.owner(nodes, keyHash(i))]; ok {
+			report.Nodes[ix].Keys++
+		}
+	}
+
+	if n == 0 || len(report.Nodes) == 0 {
+		return report
+	}
+
+	var sum, sumSquares float64
+	report.MinRatio = math.Inf(1)
+	for i := range report.Nodes {
+		b := &report.Nodes[i]
+		b.Share = float64(b.Keys) / float64(n)
+		if b.ExpectedShare > 0 {
+			b.Ratio = b.Share / b.ExpectedShare
+		}
+		sum += b.Ratio
+		sumSquares += b.Ratio * b.Ratio
+		report.MinRatio = math.Min(report.MinRatio, b.Ratio)
+		report.MaxRatio = math.Max(report.MaxRatio, b.Ratio)
+	}
+	mean := sum / float64(len(report.Nodes))
+	report.StdDev = math.Sqrt(math.Max(sumSquares/float64(len(report.Nodes))-mean*mean, 0))
+
+	return report
+}
@@ -0,0 +1,64 @@
+package rendezvous
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestRing_AnalyzeBalance(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.AddWithWeight("d", 3)
+	rv.Drain("c")
+
+	keys := make([]string, 60000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	for name, report := range map[string]BalanceReport{
+		"Keys":      rv.AnalyzeBalance(keys),
+		"Synthetic": rv.AnalyzeBalanceN(len(keys)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if report.Keys != len(keys) || len(report.Nodes) != 3 {
+				t.Fatalf("Expected %d keys on 3 nodes but got %+v", len(keys), report)
+			}
+
+			expected := map[string]float64{"a": 0.2, "b": 0.2, "d": 0.6}
+			total := 0
+			for _, b := range report.Nodes {
+				total += b.Keys
+				if b.ExpectedShare != expected[b.Name] {
+					t.Errorf("Expected share %v for %s but got %v", expected[b.Name], b.Name, b.ExpectedShare)
+				}
+				if math.Abs(b.Ratio-1) > 0.05 {
+					t.Errorf("Expected ratio near 1 for %s but got %v", b.Name, b.Ratio)
+				}
+			}
+			if total != len(keys) {
+				t.Errorf("Expected %d keys but got %d", len(keys), total)
+			}
+			if report.MinRatio > 1 || report.MaxRatio < 1 || report.StdDev > 0.05 {
+				t.Errorf("Expected a balanced ring but got %+v", report)
+			}
+		})
+	}
+
+	if report := New().AnalyzeBalanceN(10); len(report.Nodes) != 0 || report.StdDev != 0 {
+		t.Errorf("Expected an empty report but got %+v", report)
+	}
+}
+
+func TestRing_AnalyzeBalance_NoKeys(t *testing.T) {
+	rv := New(WithNodes("a"))
+	rv.AddWithWeight("b", 3)
+
+	report := rv.AnalyzeBalance(nil)
+	if report.Keys != 0 || len(report.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes without keys but got %+v", report)
+	}
+	if report.Nodes[0].ExpectedShare != 0.25 || report.Nodes[1].ExpectedShare != 0.75 {
+		t.Errorf("Expected shares of the total weight but got %+v", report.Nodes)
+	}
+}
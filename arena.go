@@ -0,0 +1,96 @@
+package rendezvous
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// A nodeArena allocates the nodes created or changed by a single write
+// together: their structs, load counters and the names of new nodes each
+// live in one backing array instead of an allocation per node. An array
+// stays alive as long as any node allocated from it, which costs little
+// since writes replacing most of a large ring are what fill arenas.
+type nodeArena struct {
+	nodes []Node
+	loads []atomic.Int64
+	names []byte
+}
+
+// newNodeArena returns an arena for up to count nodes whose new names take up
+// to nameBytes bytes.
+func newNodeArena(count, nameBytes int) *nodeArena {
+	return &nodeArena{
+		nodes: make([]Node, 0, count),
+		loads: make([]atomic.Int64, 0, count),
+		names: make([]byte, 0, nameBytes),
+	}
+}
+
+// arena returns the arena a write creating or changing up to count nodes
+// whose names take nameBytes bytes allocates from: the arena reserved by
+// WithCapacity while it has room, or else a new one. The caller must hold
+// the mutex.
+func (r *Ring) arena(count, nameBytes int) *nodeArena {
+	if reserve := r.reserved(count); reserve != nil {
+		return reserve
+	}
+	return newNodeArena(count, nameBytes)
+}
+
+// reserved returns the arena reserved by WithCapacity if it has room for
+// count more nodes, and nil otherwise. The caller must hold the mutex.
+func (r *Ring) reserved(count int) *nodeArena {
+	if r.reserve != nil && cap(r.reserve.nodes)-len(r.reserve.nodes) < count {
+		r.reserve = nil
+	}
+	return r.reserve
+}
+
+// newNode is Ring.newNode allocating from the arena. The name is copied into
+// the arena, so the node does not retain the caller's string, which may be
+// part of a larger buffer.
+func (a *nodeArena) newNode(r *Ring, name string) *Node {
+	n := a.copyNode(&Node{name: a.intern(name), hash: r.computeHash(name), weight: defaultWeight})
+	a.loads = append(a.loads, atomic.Int64{})
+	n.load = &a.loads[len(a.loads)-1]
+	return n
+}
+
+// copyNode returns a copy of n allocated from the arena.
+func (a *nodeArena) copyNode(n *Node) *Node {
+	a.nodes = append(a.nodes, *n)
+	return &a.nodes[len(a.nodes)-1]
+}
+
+// intern copies name into the arena. Bytes written to the arena are never
+// changed, so the returned string stays valid.
+func (a *nodeArena) intern(name string) string {
+	if len(name) == 0 || len(a.names)+len(name) > cap(a.names) {
+		return name
+	}
+	start := len(a.names)
+	a.names = append(a.names, name...)
+	return unsafe.String(&a.names[start], len(name))
+}
+
+// nameArena returns the names of nodes concatenated into a single string and
+// the offsets of each name in it, name i spanning offsets[i] to
+// offsets[i+1].
+func nameArena(nodes []*Node) (string, []uint32) {
+	size := 0
+	for _, n := range nodes {
+		size += len(n.name)
+	}
+	arena := make([]byte, 0, size)
+	offsets := make([]uint32, len(nodes)+1)
+	for i, n := range nodes {
+		arena = append(arena, n.name...)
+		offsets[i+1] = uint32(len(arena))
+	}
+	return unsafe.String(unsafe.SliceData(arena), len(arena)), offsets
+}
+
+// name returns the name of node i.
+func (c columns) name(i int) string {
+	return c.names[c.offsets[i]:c.offsets[i+1]]
+}
@@ -0,0 +1,32 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+func TestRing_AddAll_Arena(t *testing.T) {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = "node-" + strconv.Itoa(i)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		New().AddAll(names)
+	})
+	if allocs > 50 {
+		t.Errorf("Expected a few allocations for 1000 nodes but got %.0f", allocs)
+	}
+
+	buf := []byte("a,b,c")
+	rv := New()
+	rv.Reconcile([]Member{{Name: unsafe.String(&buf[0], 1)}, {Name: unsafe.String(&buf[2], 1)}})
+	buf[0], buf[2] = 'x', 'y'
+	if names := rv.List(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Expected node names to be copied but got %v", names)
+	}
+	if _, ok := rv.get("b"); !ok {
+		t.Errorf("Expected to find b")
+	}
+}
@@ -0,0 +1,83 @@
+package rendezvous
+
+// A Backend is an alternative algorithm for mapping keys to nodes, such as
+// jump or Maglev hashing, see WithBackend. On every membership change, the
+// ring builds a BackendTable of the nodes lookups may return, or of every
+// member for a MemberBackend, and routes owner lookups through it. The backends subpackages provide
+// implementations.
+type Backend interface {
+	// Build returns the table routing keys to nodes, which are sorted by
+	// name. The nodes are immutable snapshots and may be retained.
+	Build(nodes []*Node) BackendTable
+}
+
+// A BackendTable maps key hashes to the nodes it was built from. It must be
+// safe for concurrent use.
+type BackendTable interface {
+	// Lookup returns the node owning keyHash, or nil if there are no nodes.
+	Lookup(keyHash uint64) *Node
+}
+
+// A BackendCloner is a Backend keeping state across builds, such as the
+// buckets of AnchorHash. Rings never share one: Clone and PlanRemoval build
+// with a copy of it, so that their builds do not change the placements of
+// the original ring.
+type BackendCloner interface {
+	Backend
+
+	// Clone returns a Backend in the same state whose builds do not affect
+	// the original.
+	Clone() Backend
+}
+
+// A MemberBackend is a Backend building its tables from every member,
+// including the nodes lookups may not return, see Node.Active. Backends
+// numbering their buckets, such as jump hashing, implement it so that
+// draining a node does not renumber the buckets of the others. Its tables
+// must never return inactive nodes.
+type MemberBackend interface {
+	Backend
+
+	// BuildMembers returns the table routing keys to the active nodes of
+	// nodes, which are sorted by name, see Build.
+	BuildMembers(nodes []*Node) BackendTable
+}
+
+type backendTable struct {
+	BackendTable
+}
+
+// buildBackend publishes the backend table of nodes. The caller must hold
+// the mutex.
+func (r *Ring) buildBackend(nodes []*Node) {
+	if b, ok := r.opts.backend.(MemberBackend); ok {
+		r.backend.Store(&backendTable{b.BuildMembers(nodes)})
+		return
+	}
+	serving := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if active(n) {
+			serving = append(serving, n)
+		}
+	}
+	r.backend.Store(&backendTable{r.opts.backend.Build(serving)})
+}
+
+// backendOwner returns the node owning keyHash in the backend table, and
+// false if the ring has none.
+func (r *Ring) backendOwner(keyHash uint64) (*Node, bool) {
+	t := r.backend.Load()
+	if t == nil {
+		return nil, false
+	}
+	return t.Lookup(keyHash), true
+}
+
+// copyBackend returns backend, or a copy of it if it keeps state across
+// builds, see BackendCloner.
+func copyBackend(backend Backend) Backend {
+	if c, ok := backend.(BackendCloner); ok {
+		return c.Clone()
+	}
+	return backend
+}
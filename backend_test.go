@@ -0,0 +1,123 @@
+package rendezvous
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// lastBackend routes every key to the last node by name.
+type lastBackend struct{}
+
+func (lastBackend) Build(nodes []*Node) BackendTable {
+	return lastTable(nodes)
+}
+
+type lastTable []*Node
+
+func (t lastTable) Lookup(keyHash uint64) *Node {
+	if len(t) == 0 {
+		return nil
+	}
+	return t[len(t)-1]
+}
+
+// modBackend routes keys to nodes by keyHash modulo the number of nodes.
+type modBackend struct{}
+
+func (modBackend) Build(nodes []*Node) BackendTable {
+	return modTable(nodes)
+}
+
+type modTable []*Node
+
+func (t modTable) Lookup(keyHash uint64) *Node {
+	if len(t) == 0 {
+		return nil
+	}
+	return t[keyHash%uint64(len(t))]
+}
+
+// orderBackend routes keys by keyHash modulo the number of nodes, over the
+// nodes in the order it first saw them. Like the buckets of AnchorHash, a
+// departed node's position is taken over by the last node.
+type orderBackend struct {
+	mutex sync.Mutex
+	order []string
+}
+
+func (b *orderBackend) Build(nodes []*Node) BackendTable {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	byName := make(map[string]*Node, len(nodes))
+	for _, n := range nodes {
+		byName[n.Name()] = n
+	}
+	seen := make(map[string]bool, len(b.order))
+	for i := 0; i < len(b.order); {
+		if byName[b.order[i]] == nil {
+			b.order[i] = b.order[len(b.order)-1]
+			b.order = b.order[:len(b.order)-1]
+			continue
+		}
+		seen[b.order[i]] = true
+		i++
+	}
+	for _, n := range nodes {
+		if !seen[n.Name()] {
+			b.order = append(b.order, n.Name())
+		}
+	}
+
+	table := make(modTable, len(b.order))
+	for i, name := range b.order {
+		table[i] = byName[name]
+	}
+	return table
+}
+
+func (b *orderBackend) Clone() Backend {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return &orderBackend{order: append([]string(nil), b.order...)}
+}
+
+func TestWithBackend(t *testing.T) {
+	rv := New(WithBackend(lastBackend{}), WithNodes("a", "b", "c"))
+
+	if node := rv.Lookup("foo"); node != "c" {
+		t.Errorf("Expected c but got %s", node)
+	}
+	if node := rv.LookupNode("foo"); node.Name() != "c" {
+		t.Errorf("Expected c but got %s", node.Name())
+	}
+
+	// Backends only see the nodes lookups may return.
+	rv.Drain("c")
+	if node := rv.LookupBytes([]byte("foo")); node != "b" {
+		t.Errorf("Expected b but got %s", node)
+	}
+
+	// Pins take precedence over the backend.
+	rv.Pin("foo", "a")
+	if node := rv.Lookup("foo"); node != "a" {
+		t.Errorf("Expected a but got %s", node)
+	}
+
+	rv.RemoveAll([]string{"a", "b", "c"})
+	if node := rv.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+}
+
+func TestWithBackend_Owners(t *testing.T) {
+	rv := New(WithBackend(modBackend{}))
+	for i := 0; i < 20; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), 1+float64(i%3))
+	}
+	rv.Drain("n3")
+	rv.Pin("k7", "n5")
+	testOwnersAgree(t, rv)
+}
@@ -0,0 +1,225 @@
+// Package anchor provides an AnchorHash backend for rendezvous rings, as
+// described by Mendelson et al. in "AnchorHash: A Scalable Consistent Hash".
+//
+// AnchorHash maps keys to a fixed set of buckets, the anchor, of which the
+// nodes occupy a working subset. Lookups take expected constant time for
+// moderate removals and memory is a few integers per bucket, while adding or
+// removing a node moves only the keys of that node. Node weights are
+// ignored.
+//
+// The backend remembers which bucket every node occupies across membership
+// changes, which is what keeps placements consistent, so use a Backend with
+// a single ring. Ring.Clone and Ring.PlanRemoval build with a copy of it,
+// see Backend.Clone.
+package anchor
+
+import (
+	"sync"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// DefaultCapacity is the default number of buckets.
+const DefaultCapacity = 1024
+
+// An Option configures the backend.
+type Option func(*Backend)
+
+// WithCapacity sets the number of buckets, the most nodes the ring can hold
+// without rebuilding the anchor. Rings outgrowing it double the capacity,
+// which moves keys as if the ring were new.
+func WithCapacity(capacity int) Option {
+	return func(b *Backend) {
+		if capacity > 0 {
+			b.capacity = capacity
+		}
+	}
+}
+
+// WithBackend returns an option routing owner lookups with AnchorHash.
+func WithBackend(opts ...Option) rendezvous.Option {
+	return rendezvous.WithBackend(New(opts...))
+}
+
+// Backend maintains an anchor and builds snapshots of it for lookups.
+type Backend struct {
+	capacity int
+
+	mutex   sync.Mutex
+	anchor  *anchor
+	buckets map[string]int
+}
+
+// New returns a Backend configured by opts.
+func New(opts ...Option) *Backend {
+	b := &Backend{capacity: DefaultCapacity}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Build removes the buckets of departed nodes from the anchor and assigns
+// free buckets to new nodes, then snapshots the anchor.
+func (b *Backend) Build(nodes []*rendezvous.Node) rendezvous.BackendTable {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.anchor == nil || len(nodes) > b.anchor.size() {
+		capacity := b.capacity
+		for capacity < len(nodes) {
+			capacity *= 2
+		}
+		b.capacity = capacity
+		b.anchor = newAnchor(capacity)
+		b.buckets = make(map[string]int)
+	}
+
+	present := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		present[n.Name()] = true
+	}
+	for name, bucket := range b.buckets {
+		if !present[name] {
+			b.anchor.remove(bucket)
+			delete(b.buckets, name)
+		}
+	}
+
+	owners := make([]*rendezvous.Node, b.anchor.size())
+	for _, n := range nodes {
+		bucket, ok := b.buckets[n.Name()]
+		if !ok {
+			bucket = b.anchor.add()
+			b.buckets[n.Name()] = bucket
+		}
+		owners[bucket] = n
+	}
+
+	t := &table{
+		removed:   make([]int, len(b.anchor.removed)),
+		successor: make([]int, len(b.anchor.successor)),
+		owners:    owners,
+		empty:     len(nodes) == 0,
+	}
+	copy(t.removed, b.anchor.removed)
+	copy(t.successor, b.anchor.successor)
+	return t
+}
+
+// Clone returns a Backend with a copy of the anchor and the buckets of the
+// nodes, see rendezvous.BackendCloner.
+func (b *Backend) Clone() rendezvous.Backend {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c := &Backend{capacity: b.capacity}
+	if b.anchor != nil {
+		c.anchor = b.anchor.clone()
+		c.buckets = make(map[string]int, len(b.buckets))
+		for name, bucket := range b.buckets {
+			c.buckets[name] = bucket
+		}
+	}
+	return c
+}
+
+// An anchor is the mutable state of AnchorHash, named as in the paper: A is
+// removed, K successor, L location, W working and R the stack of removed
+// buckets.
+type anchor struct {
+	removed   []int
+	successor []int
+	location  []int
+	working   []int
+	stack     []int
+	n         int
+}
+
+// newAnchor returns an anchor of capacity buckets, all of them removed.
+func newAnchor(capacity int) *anchor {
+	a := &anchor{
+		removed:   make([]int, capacity),
+		successor: make([]int, capacity),
+		location:  make([]int, capacity),
+		working:   make([]int, capacity),
+	}
+	for b := 0; b < capacity; b++ {
+		a.successor[b], a.location[b], a.working[b] = b, b, b
+	}
+	for b := capacity - 1; b >= 0; b-- {
+		a.stack = append(a.stack, b)
+		a.removed[b] = b
+	}
+	return a
+}
+
+func (a *anchor) clone() *anchor {
+	return &anchor{
+		removed:   append([]int(nil), a.removed...),
+		successor: append([]int(nil), a.successor...),
+		location:  append([]int(nil), a.location...),
+		working:   append([]int(nil), a.working...),
+		stack:     append([]int(nil), a.stack...),
+		n:         a.n,
+	}
+}
+
+func (a *anchor) size() int {
+	return len(a.removed)
+}
+
+func (a *anchor) add() int {
+	b := a.stack[len(a.stack)-1]
+	a.stack = a.stack[:len(a.stack)-1]
+	a.removed[b] = 0
+	a.location[a.working[a.n]] = a.n
+	a.working[a.location[b]] = b
+	a.successor[b] = b
+	a.n++
+	return b
+}
+
+func (a *anchor) remove(b int) {
+	a.stack = append(a.stack, b)
+	a.n--
+	a.removed[b] = a.n
+	a.working[a.location[b]] = a.working[a.n]
+	a.location[a.working[a.n]] = a.location[b]
+	a.successor[b] = a.working[a.n]
+}
+
+// A table is an immutable snapshot of an anchor.
+type table struct {
+	removed   []int
+	successor []int
+	owners    []*rendezvous.Node
+	empty     bool
+}
+
+func (t *table) Lookup(keyHash uint64) *rendezvous.Node {
+	if t.empty {
+		return nil
+	}
+
+	b := int(keyHash % uint64(len(t.removed)))
+	for t.removed[b] > 0 {
+		h := int(mix(keyHash^uint64(b)*0x9e3779b97f4a7c15) % uint64(t.removed[b]))
+		for t.removed[h] >= t.removed[b] {
+			h = t.successor[h]
+		}
+		b = h
+	}
+	return t.owners[b]
+}
+
+// mix is the splitmix64 finalizer, deriving the hash of a key in the
+// working set at the time a bucket was removed.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
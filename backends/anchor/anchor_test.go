@@ -0,0 +1,122 @@
+package anchor
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func lookupAll(ring *rendezvous.Ring) map[string]string {
+	owners := make(map[string]string)
+	for i := 0; i < 10000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = ring.Lookup(key)
+	}
+	return owners
+}
+
+func TestWithBackend(t *testing.T) {
+	ring := rendezvous.New(WithBackend(WithCapacity(64)))
+	if node := ring.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+	for i := 0; i < 10; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+
+	before := lookupAll(ring)
+	counts := make(map[string]int)
+	for _, owner := range before {
+		counts[owner]++
+	}
+	for name, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Errorf("Expected about 1000 keys on %s but got %d", name, count)
+		}
+	}
+
+	// Removing a node only moves its keys.
+	ring.Remove("n3")
+	afterRemove := lookupAll(ring)
+	for key, owner := range before {
+		if owner != "n3" && afterRemove[key] != owner {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, owner, afterRemove[key])
+		}
+		if afterRemove[key] == "n3" {
+			t.Fatalf("Expected no key on the removed node")
+		}
+	}
+
+	// Adding a node only moves keys to it.
+	ring.Add("n10")
+	for key, owner := range lookupAll(ring) {
+		if owner != afterRemove[key] && owner != "n10" {
+			t.Fatalf("Expected %s to stay on %s or move to n10 but got %s", key, afterRemove[key], owner)
+		}
+	}
+}
+
+func TestWithCapacity(t *testing.T) {
+	ring := rendezvous.New(WithBackend(WithCapacity(2)))
+	for i := 0; i < 5; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+
+	counts := make(map[string]int)
+	for _, owner := range lookupAll(ring) {
+		counts[owner]++
+	}
+	if len(counts) != 5 {
+		t.Errorf("Expected the anchor to grow to all nodes but got %v", counts)
+	}
+}
+
+func TestBackend_PlanRemoval(t *testing.T) {
+	ring := rendezvous.New(WithBackend(WithCapacity(64)))
+	for i := 0; i < 10; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+	before := lookupAll(ring)
+
+	keys := make([]string, 0, len(before))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	if movements := ring.PlanRemoval("n3", keys); len(movements) == 0 {
+		t.Fatalf("Expected n3 to own some of the keys")
+	}
+
+	// Rebuild the anchor without a membership change.
+	ring.AddWithWeight("n0", 1.0)
+	for key, owner := range lookupAll(ring) {
+		if owner != before[key] {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, before[key], owner)
+		}
+	}
+}
+
+func TestBackend_Clone(t *testing.T) {
+	ring := rendezvous.New(WithBackend(WithCapacity(64)))
+	for i := 0; i < 10; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+	before := lookupAll(ring)
+
+	clone := ring.Clone()
+	clone.Remove("n3")
+	clone.Add("n10")
+	for key, owner := range lookupAll(clone) {
+		if owner != before[key] && owner != "n10" && before[key] != "n3" {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, before[key], owner)
+		}
+	}
+
+	// The clone's builds leave the original's anchor alone.
+	ring.AddWithWeight("n0", 1.0)
+	for key, owner := range lookupAll(ring) {
+		if owner != before[key] {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, before[key], owner)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package jump provides a jump consistent hash backend for rendezvous rings,
+// for numbered buckets such as the shards of a database.
+//
+// Jump hashing needs no memory beyond the node list and moves the minimum of
+// keys when nodes are added or removed at the end of the bucket order, which
+// is the order of node names. Name nodes so that they sort in the order they
+// join, for example shard-0000, shard-0001 and so on; removing any other node
+// renumbers the buckets after it. Drained, unhealthy and zero weighted nodes
+// keep their buckets, and only their keys move, spread over the active
+// nodes. Node weights are otherwise ignored.
+package jump
+
+import "github.com/mosuka/rendezvous"
+
+// WithBackend returns an option routing owner lookups with jump hashing.
+func WithBackend() rendezvous.Option {
+	return rendezvous.WithBackend(Backend{})
+}
+
+// Backend builds jump hash tables.
+type Backend struct{}
+
+func (b Backend) Build(nodes []*rendezvous.Node) rendezvous.BackendTable {
+	return b.BuildMembers(nodes)
+}
+
+// BuildMembers returns the table of every member, so that inactive nodes
+// keep their buckets.
+func (Backend) BuildMembers(nodes []*rendezvous.Node) rendezvous.BackendTable {
+	t := &table{buckets: nodes}
+	for _, n := range nodes {
+		if n.Active() {
+			t.active = append(t.active, n)
+		}
+	}
+	return t
+}
+
+type table struct {
+	buckets []*rendezvous.Node
+	active  []*rendezvous.Node
+}
+
+// Lookup returns the node of keyHash's bucket, or else, if that node is
+// inactive, an active node chosen by jumping again over the active nodes
+// with a remixed key.
+func (t *table) Lookup(keyHash uint64) *rendezvous.Node {
+	if len(t.active) == 0 {
+		return nil
+	}
+	if n := t.buckets[Hash(keyHash, len(t.buckets))]; n.Active() {
+		return n
+	}
+	return t.active[Hash(remix(keyHash), len(t.active))]
+}
+
+// remix returns another hash of keyHash, so that the keys of an inactive
+// bucket spread over the active ones independently of their bucket.
+func remix(keyHash uint64) uint64 {
+	keyHash ^= keyHash >> 31
+	keyHash *= 0x9e3779b97f4a7c15
+	return keyHash ^ keyHash>>29
+}
+
+// Hash returns the bucket of key among buckets, as described by Lamping and
+// Veach in "A Fast, Minimal Memory, Consistent Hash Algorithm".
+func Hash(key uint64, buckets int) int {
+	b, j := int64(-1), int64(0)
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
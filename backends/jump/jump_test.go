@@ -0,0 +1,119 @@
+package jump
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestHash(t *testing.T) {
+	for key := uint64(0); key < 10000; key++ {
+		before := Hash(key*0x9e3779b97f4a7c15, 10)
+		after := Hash(key*0x9e3779b97f4a7c15, 11)
+		if before < 0 || before >= 10 {
+			t.Fatalf("Expected a bucket below 10 but got %d", before)
+		}
+		if after != before && after != 10 {
+			t.Fatalf("Expected key %d to stay in %d or move to 10 but got %d", key, before, after)
+		}
+	}
+	if Hash(42, 1) != 0 || Hash(42, 0) != -1 {
+		t.Errorf("Expected bucket 0 of 1 and none of 0")
+	}
+}
+
+func TestWithBackend(t *testing.T) {
+	ring := rendezvous.New(WithBackend())
+	if node := ring.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+	for i := 0; i < 4; i++ {
+		ring.Add("shard-" + strconv.Itoa(i))
+	}
+
+	counts := make(map[string]int)
+	owners := make(map[string]string)
+	for i := 0; i < 4000; i++ {
+		key := strconv.Itoa(i)
+		owner := ring.Lookup(key)
+		if expected := "shard-" + strconv.Itoa(Hash(ring.Hash(key), 4)); owner != expected {
+			t.Fatalf("Expected %s but got %s", expected, owner)
+		}
+		counts[owner]++
+		owners[key] = owner
+	}
+	for name, count := range counts {
+		if count < 850 || count > 1150 {
+			t.Errorf("Expected about 1000 keys on %s but got %d", name, count)
+		}
+	}
+
+	ring.Add("shard-4")
+	for key, owner := range owners {
+		if moved := ring.Lookup(key); moved != owner && moved != "shard-4" {
+			t.Fatalf("Expected %s to stay on %s or move to shard-4 but got %s", key, owner, moved)
+		}
+	}
+}
+
+func TestWithBackend_Owners(t *testing.T) {
+	ring := rendezvous.New(WithBackend())
+	for i := 0; i < 20; i++ {
+		ring.Add("shard-" + strconv.Itoa(i))
+	}
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	batch := ring.LookupBatch(keys)
+	for i, key := range keys {
+		owner := ring.Lookup(key)
+		if !ring.Owns(owner, key) {
+			t.Fatalf("Expected %s to own %s", owner, key)
+		}
+		if batch[i] != owner || ring.LookupExcluding(key) != owner {
+			t.Fatalf("Expected every owner lookup of %s to return %s", key, owner)
+		}
+	}
+}
+
+func TestWithBackend_Drain(t *testing.T) {
+	ring := rendezvous.New(WithBackend())
+	for i := 0; i < 8; i++ {
+		ring.Add("shard-" + strconv.Itoa(i))
+	}
+	owners := make(map[string]string)
+	for i := 0; i < 4000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = ring.Lookup(key)
+	}
+
+	ring.Drain("shard-2")
+	ring.SetHealthy("shard-5", false)
+	moved := 0
+	for key, owner := range owners {
+		now := ring.Lookup(key)
+		if owner != "shard-2" && owner != "shard-5" && now != owner {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, owner, now)
+		}
+		if now == "shard-2" || now == "shard-5" {
+			t.Fatalf("Expected %s to leave the inactive shards", key)
+		}
+		if now != owner {
+			moved++
+		}
+	}
+	if moved < 800 || moved > 1200 {
+		t.Errorf("Expected about 1000 keys to move but got %d", moved)
+	}
+
+	ring.Activate("shard-2")
+	ring.SetHealthy("shard-5", true)
+	for key, owner := range owners {
+		if now := ring.Lookup(key); now != owner {
+			t.Fatalf("Expected %s to return to %s but got %s", key, owner, now)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package maglev provides a Maglev hashing backend for rendezvous rings, as
+// described by Eisenbud et al. in "Maglev: A Fast and Reliable Software
+// Network Load Balancer".
+//
+// Maglev lookups index a precomputed table, so they take constant time
+// regardless of the number of nodes, at the price of the table's memory and
+// of rebuilding it on every membership change. Membership changes move
+// slightly more keys than the minimum. Weights are honored approximately by
+// giving heavier nodes more turns while the table is filled.
+package maglev
+
+import (
+	"github.com/cespare/xxhash/v2"
+	"github.com/mosuka/rendezvous"
+)
+
+// DefaultSize is the default table size. It must be prime and should be at
+// least 100 times the number of nodes for an even distribution.
+const DefaultSize = 65537
+
+// An Option configures the backend.
+type Option func(*Backend)
+
+// WithSize sets the table size, which must be prime. It panics otherwise.
+func WithSize(size int) Option {
+	if !prime(size) {
+		panic("maglev: table size must be prime")
+	}
+	return func(b *Backend) {
+		b.size = size
+	}
+}
+
+// WithBackend returns an option routing owner lookups with Maglev hashing.
+func WithBackend(opts ...Option) rendezvous.Option {
+	return rendezvous.WithBackend(New(opts...))
+}
+
+// Backend builds Maglev lookup tables.
+type Backend struct {
+	size int
+}
+
+// New returns a Backend configured by opts.
+func New(opts ...Option) *Backend {
+	b := &Backend{size: DefaultSize}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+type table struct {
+	entries []*rendezvous.Node
+}
+
+func (t *table) Lookup(keyHash uint64) *rendezvous.Node {
+	if t.entries == nil {
+		return nil
+	}
+	return t.entries[keyHash%uint64(len(t.entries))]
+}
+
+// Build fills the table by letting every node claim the next free entry of
+// its permutation of the table in turn.
+func (b *Backend) Build(nodes []*rendezvous.Node) rendezvous.BackendTable {
+	if len(nodes) == 0 {
+		return &table{}
+	}
+
+	size := uint64(b.size)
+	offsets := make([]uint64, len(nodes))
+	skips := make([]uint64, len(nodes))
+	next := make([]uint64, len(nodes))
+	claimed := make([]float64, len(nodes))
+	maxWeight := 0.0
+	for i, n := range nodes {
+		h := xxhash.Sum64String(n.Name())
+		offsets[i] = h % size
+		skips[i] = mix(h)%(size-1) + 1
+		if n.Weight() > maxWeight {
+			maxWeight = n.Weight()
+		}
+	}
+
+	entries := make([]*rendezvous.Node, size)
+	filled := uint64(0)
+	for round := 1.0; filled < size; round++ {
+		for i, n := range nodes {
+			// A node of the maximum weight claims an entry every round, a
+			// lighter node only as often as its weight allows.
+			if maxWeight > 0 && claimed[i] >= round*n.Weight()/maxWeight {
+				continue
+			}
+			entry := (offsets[i] + next[i]*skips[i]) % size
+			for entries[entry] != nil {
+				next[i]++
+				entry = (offsets[i] + next[i]*skips[i]) % size
+			}
+			entries[entry] = n
+			next[i]++
+			claimed[i]++
+			filled++
+			if filled == size {
+				break
+			}
+		}
+	}
+	return &table{entries: entries}
+}
+
+// mix is the splitmix64 finalizer, deriving the skip from the offset hash.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func prime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
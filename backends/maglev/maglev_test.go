@@ -0,0 +1,71 @@
+package maglev
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestWithBackend(t *testing.T) {
+	ring := rendezvous.New(WithBackend(WithSize(10007)))
+	if node := ring.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+	for i := 0; i < 10; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+	ring.AddWithWeight("heavy", 2)
+
+	counts := make(map[string]int)
+	owners := make(map[string]string)
+	for i := 0; i < 12000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = ring.Lookup(key)
+		counts[owners[key]]++
+	}
+	for name, count := range counts {
+		expected := 1000
+		if name == "heavy" {
+			expected = 2000
+		}
+		if count < expected*8/10 || count > expected*12/10 {
+			t.Errorf("Expected about %d keys on %s but got %d", expected, name, count)
+		}
+	}
+
+	// Removing a node moves its keys and only few others.
+	ring.Remove("n3")
+	moved := 0
+	for key, owner := range owners {
+		if owner != "n3" && ring.Lookup(key) != owner {
+			moved++
+		}
+	}
+	if moved > 600 {
+		t.Errorf("Expected few other keys to move but got %d", moved)
+	}
+}
+
+func TestWithSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a size that is not prime")
+		}
+	}()
+	WithSize(65536)
+}
+
+func BenchmarkLookup(b *testing.B) {
+	ring := rendezvous.New(WithBackend())
+	names := make([]string, 500)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	ring.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ring.LookupHash(uint64(i))
+	}
+}
@@ -0,0 +1,108 @@
+// Package multiprobe provides a multi-probe consistent hashing backend for
+// rendezvous rings, as described by Appleton and O'Reilly in "Multi-probe
+// consistent hashing".
+//
+// Every node is a single point on a hash ring, and every key is hashed to
+// several probe points; the key belongs to the node following the closest of
+// its probes. With 21 probes, the peak-to-average load stays around 1.05
+// while the memory is a single point per node. Lookups take
+// O(probes * log(nodes)). Node weights are ignored.
+package multiprobe
+
+import (
+	"sort"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/mosuka/rendezvous"
+)
+
+// DefaultProbes is the default number of probes per key.
+const DefaultProbes = 21
+
+// An Option configures the backend.
+type Option func(*Backend)
+
+// WithProbes sets the number of probes per key. More probes balance better
+// but make lookups slower.
+func WithProbes(probes int) Option {
+	return func(b *Backend) {
+		if probes > 0 {
+			b.probes = probes
+		}
+	}
+}
+
+// WithBackend returns an option routing owner lookups with multi-probe
+// consistent hashing.
+func WithBackend(opts ...Option) rendezvous.Option {
+	return rendezvous.WithBackend(New(opts...))
+}
+
+// Backend builds multi-probe hash rings.
+type Backend struct {
+	probes int
+}
+
+// New returns a Backend configured by opts.
+func New(opts ...Option) *Backend {
+	b := &Backend{probes: DefaultProbes}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+type point struct {
+	hash uint64
+	node *rendezvous.Node
+}
+
+type table struct {
+	points []point
+	probes int
+}
+
+// Build places every node on the ring at the hash of its name.
+func (b *Backend) Build(nodes []*rendezvous.Node) rendezvous.BackendTable {
+	points := make([]point, len(nodes))
+	for i, n := range nodes {
+		points[i] = point{hash: xxhash.Sum64String(n.Name()), node: n}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].hash < points[j].hash
+	})
+	return &table{points: points, probes: b.probes}
+}
+
+func (t *table) Lookup(keyHash uint64) *rendezvous.Node {
+	if len(t.points) == 0 {
+		return nil
+	}
+
+	var owner *rendezvous.Node
+	closest := ^uint64(0)
+	for i := 0; i < t.probes; i++ {
+		probe := mix(keyHash + uint64(i)*0x9e3779b97f4a7c15)
+		ix := sort.Search(len(t.points), func(j int) bool {
+			return t.points[j].hash >= probe
+		})
+		if ix == len(t.points) {
+			ix = 0
+		}
+		// The distance wraps around the ring.
+		if distance := t.points[ix].hash - probe; distance < closest || owner == nil {
+			owner, closest = t.points[ix].node, distance
+		}
+	}
+	return owner
+}
+
+// mix is the splitmix64 finalizer, deriving independent probe points.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,57 @@
+package multiprobe
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestWithBackend(t *testing.T) {
+	ring := rendezvous.New(WithBackend())
+	if node := ring.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+	for i := 0; i < 10; i++ {
+		ring.Add("n" + strconv.Itoa(i))
+	}
+
+	counts := make(map[string]int)
+	owners := make(map[string]string)
+	for i := 0; i < 20000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = ring.Lookup(key)
+		counts[owners[key]]++
+	}
+	peak := 0
+	for _, count := range counts {
+		if count > peak {
+			peak = count
+		}
+	}
+	if len(counts) != 10 || peak > 2300 {
+		t.Errorf("Expected a peak-to-average load below 1.15 but got %v", counts)
+	}
+
+	// Adding a node only moves keys to it.
+	ring.Add("n10")
+	for key, owner := range owners {
+		if moved := ring.Lookup(key); moved != owner && moved != "n10" {
+			t.Fatalf("Expected %s to stay on %s or move to n10 but got %s", key, owner, moved)
+		}
+	}
+}
+
+func TestWithProbes(t *testing.T) {
+	one := rendezvous.New(WithBackend(WithProbes(1)), rendezvous.WithNodes("a", "b", "c"))
+	many := rendezvous.New(WithBackend(WithProbes(64)), rendezvous.WithNodes("a", "b", "c"))
+	differ := 0
+	for i := 0; i < 100; i++ {
+		if one.Lookup(strconv.Itoa(i)) != many.Lookup(strconv.Itoa(i)) {
+			differ++
+		}
+	}
+	if differ == 0 {
+		t.Errorf("Expected the number of probes to change placements")
+	}
+}
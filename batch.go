@@ -0,0 +1,167 @@
+package rendezvous
+
+import (
+	"math"
+	"slices"
+)
+
+// batchSize is the number of nodes whose hashes are mixed together before
+// any of them is scored, and batchMinNodes the smallest ranking that is
+// scored in batches and sorted instead of selected with a heap.
+const (
+	batchSize     = 8
+	batchMinNodes = 256
+)
+
+// A batchEntry is a node's score and index in the snapshot. Entries hold no
+// pointers, so sorting them moves no pointers past the garbage collector's
+// write barrier.
+type batchEntry struct {
+	score float64
+	index int32
+	// key orders entries by descending score, see sortEntries.
+	key uint64
+}
+
+// rankBatch is like topNInto for rankings of a large part of the snapshot:
+// it scores the accepted nodes in batches and sorts them once, which costs
+// far less than maintaining a heap of most of the nodes.
+func (r *Ring) rankBatch(buf []ScoredNode, nodes []*Node, cols columns, keyHash uint64, n int, accept func(*Node) bool) []ScoredNode {
+	if cols.hashes == nil {
+		cols = newSnapshot(nodes).columns
+	}
+
+	p, scratch := getBatchEntries(len(nodes)), getBatchEntries(len(nodes))
+	entries := *p
+	if r.opts.defaultScore {
+		entries = scoreBatches(entries, nodes, cols, keyHash, accept)
+	} else {
+		for i, node := range nodes {
+			if accept == nil || accept(node) {
+				entries = append(entries, batchEntry{score: r.scoreHash(keyHash, cols.hashes[i], cols.weights[i]), index: int32(i)})
+			}
+		}
+	}
+
+	sorted := sortEntries(entries, (*scratch)[:len(entries)])
+	breakTies(sorted, nodes)
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+
+	h := buf[:0]
+	for _, e := range sorted[:n] {
+		h = append(h, ScoredNode{node: nodes[e.index], score: e.score})
+	}
+
+	putBatchEntries(p, entries)
+	putBatchEntries(scratch, *scratch)
+	return h
+}
+
+// scoreBatches appends the computeScore entries of the accepted nodes. The
+// hashes of a batch are mixed in independent lanes before their logarithms
+// are taken, so the multiplications of different nodes overlap. There is no
+// assembly kernel: scores must equal those of computeScore bit for bit on
+// every platform, and a vectorized logarithm would round differently, while
+// the mixing alone is not where the time goes, see BenchmarkRankBatch.
+func scoreBatches(entries []batchEntry, nodes []*Node, cols columns, keyHash uint64, accept func(*Node) bool) []batchEntry {
+	var (
+		hashes  [batchSize]uint64
+		weights [batchSize]float64
+		indexes [batchSize]int32
+	)
+	for start := 0; start < len(nodes); start += batchSize {
+		end := start + batchSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+
+		lanes := 0
+		for i := start; i < end; i++ {
+			if accept == nil || accept(nodes[i]) {
+				hashes[lanes], weights[lanes], indexes[lanes] = cols.hashes[i], cols.weights[i], int32(i)
+				lanes++
+			}
+		}
+		for i := range hashes[:lanes] {
+			x := keyHash ^ hashes[i]
+			x ^= x >> 12
+			x ^= x << 25
+			x ^= x >> 27
+			hashes[i] = x * 0x2545F4914F6CDD1D
+		}
+		for i := range hashes[:lanes] {
+			score := -weights[i] / math.Log(float64(hashes[i])/float64(math.MaxUint64))
+			entries = append(entries, batchEntry{score: score, index: indexes[i]})
+		}
+	}
+	return entries
+}
+
+// breakTies orders the runs of sorted entries with equal scores by rank, see
+// ranksAbove. Equal scores are rare, so this is usually a single pass.
+func breakTies(sorted []batchEntry, nodes []*Node) {
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end].score == sorted[start].score {
+			end++
+		}
+		if end-start > 1 {
+			slices.SortFunc(sorted[start:end], func(a, b batchEntry) int {
+				x, y := ScoredNode{node: nodes[a.index], score: a.score}, ScoredNode{node: nodes[b.index], score: b.score}
+				switch {
+				case ranksAbove(x, y):
+					return -1
+				case ranksAbove(y, x):
+					return 1
+				}
+				return 0
+			})
+		}
+		start = end
+	}
+}
+
+// sortEntries sorts entries by descending score and then by index with a
+// least significant digit radix sort, using scratch of the same length, and
+// returns the sorted entries in either entries or scratch. Scores are turned
+// into unsigned keys ordered like the floats; digits shared by every key,
+// such as most of the exponent bits, take no pass.
+func sortEntries(entries, scratch []batchEntry) []batchEntry {
+	if len(entries) == 0 {
+		return entries
+	}
+
+	var counts [8][256]int
+	for i := range entries {
+		bits := math.Float64bits(entries[i].score)
+		if bits>>63 != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		entries[i].key = ^bits
+		for d := range counts {
+			counts[d][byte(entries[i].key>>(8*d))]++
+		}
+	}
+
+	for d := range counts {
+		if counts[d][byte(entries[0].key>>(8*d))] == len(entries) {
+			continue
+		}
+		offset := 0
+		for b, count := range counts[d] {
+			counts[d][b] = offset
+			offset += count
+		}
+		for _, e := range entries {
+			digit := byte(e.key >> (8 * d))
+			scratch[counts[d][digit]] = e
+			counts[d][digit]++
+		}
+		entries, scratch = scratch, entries
+	}
+	return entries
+}
@@ -0,0 +1,95 @@
+package rendezvous
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestRing_RankBatch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, opts := range [][]Option{nil, {WithWeighting(LogWeighting)}, {WithWeighting(IntervalWeighting)}} {
+		rv := New(opts...)
+		for i := 0; i < 1000; i++ {
+			rv.AddWithWeight("n"+strconv.Itoa(i), 0.5+random.Float64())
+		}
+		rv.Drain("n7")
+
+		nodes := rv.load()
+		for i := 0; i < 20; i++ {
+			keyHash := mix64(uint64(i))
+			expected := make([]ScoredNode, 0, len(nodes))
+			for _, n := range nodes {
+				if active(n) {
+					expected = append(expected, ScoredNode{node: n, score: rv.score(keyHash, n)})
+				}
+			}
+			sort.SliceStable(expected, func(i, j int) bool {
+				return expected[i].score > expected[j].score
+			})
+
+			batch := rv.rankBatch(nil, nodes, rv.columnsOf(nodes), keyHash, 300, active)
+			if len(batch) != 300 {
+				t.Fatalf("Expected 300 nodes but got %d", len(batch))
+			}
+			for j := range batch {
+				if batch[j] != expected[j] {
+					t.Fatalf("Expected %v at %d but got %v", expected[j], j, batch[j])
+				}
+			}
+		}
+	}
+
+	if ranked := New().rankBatch(nil, nil, columns{}, 0, 10, nil); len(ranked) != 0 {
+		t.Errorf("Expected no nodes but got %v", ranked)
+	}
+}
+
+// BenchmarkRankBatch ranks every node of a large ring with the heap and in
+// batches, and breaks the batched path down into mixing and scoring.
+func BenchmarkRankBatch(b *testing.B) {
+	rv := New()
+	names := make([]string, 20000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+	nodes := rv.load()
+	cols := rv.columnsOf(nodes)
+	buf := make([]ScoredNode, 0, len(nodes))
+	entries := make([]batchEntry, 0, len(nodes))
+
+	b.Run("Heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rv.topNHeap(buf, nodes, cols, uint64(i), len(nodes), active)
+		}
+	})
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rv.rankBatch(buf, nodes, cols, uint64(i), len(nodes), active)
+		}
+	})
+	b.Run("Mix", func(b *testing.B) {
+		var sink uint64
+		for i := 0; i < b.N; i++ {
+			for _, hash := range cols.hashes {
+				sink += combineHashes(uint64(i), hash)
+			}
+		}
+		_ = sink
+	})
+	b.Run("Score", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries = entries[:0]
+			for j, hash := range cols.hashes {
+				entries = append(entries, batchEntry{score: rv.scoreHash(uint64(i), hash, cols.weights[j]), index: int32(j)})
+			}
+		}
+	})
+	b.Run("ScoreBatches", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries = scoreBatches(entries[:0], nodes, cols, uint64(i), active)
+		}
+	})
+}
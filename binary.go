@@ -0,0 +1,233 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// binaryFormat is the version of the binary encoding, written as its first
+// byte.
+const binaryFormat = 1
+
+const (
+	binarySeeded = 1 << iota
+)
+
+const (
+	binaryDrained = 1 << iota
+	binaryCapacity
+	binaryTags
+)
+
+var errShortBuffer = errors.New("rendezvous: truncated binary ring")
+
+// MarshalBinary encodes the same state as MarshalJSON together with the node
+// hashes in a compact binary form, so that UnmarshalBinary restores large
+// rings without hashing every node name again.
+func (r *Ring) MarshalBinary() ([]byte, error) {
+	version := r.Version()
+	nodes := r.load()
+
+	size := 16 + len(r.opts.hasherName)
+	for _, n := range nodes {
+		size += 24 + len(n.name)
+	}
+	buf := make([]byte, 0, size)
+
+	buf = append(buf, binaryFormat)
+	buf = appendString(buf, r.opts.hasherName)
+	if r.opts.seeded {
+		buf = append(buf, binarySeeded)
+		buf = binary.LittleEndian.AppendUint64(buf, r.opts.seed)
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = binary.AppendUvarint(buf, version)
+
+	buf = binary.AppendUvarint(buf, uint64(len(nodes)))
+	for _, n := range nodes {
+		buf = appendString(buf, n.name)
+		buf = binary.LittleEndian.AppendUint64(buf, n.hash)
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(n.weight))
+
+		var flags byte
+		if n.drained {
+			flags |= binaryDrained
+		}
+		if n.capacity != 0 {
+			flags |= binaryCapacity
+		}
+		if len(n.tags) > 0 {
+			flags |= binaryTags
+		}
+		buf = append(buf, flags)
+		if n.capacity != 0 {
+			buf = binary.AppendVarint(buf, n.capacity)
+		}
+		if len(n.tags) > 0 {
+			buf = binary.AppendUvarint(buf, uint64(len(n.tags)))
+			for _, k := range sortedKeys(n.tags) {
+				buf = appendString(buf, k)
+				buf = appendString(buf, n.tags[k])
+			}
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the ring's membership and hashing with a ring
+// encoded by MarshalBinary, trusting the encoded node hashes. A ring hashed
+// with an unregistered hash function, see WithHasher, encodes no hasher name:
+// its nodes are hashed by name with the ring's own hash function instead.
+// Like UnmarshalJSON, it may be called on a zero Ring, and returns
+// ErrHashingChanged for a ring hashed differently than one with nodes.
+func (r *Ring) UnmarshalBinary(data []byte) error {
+	d := decoder{buf: data}
+	if format := d.byte(); d.err == nil && format != binaryFormat {
+		return fmt.Errorf("rendezvous: unsupported binary format %d", format)
+	}
+	hasherName := d.string()
+	var seed *uint64
+	if d.byte()&binarySeeded != 0 {
+		s := d.uint64()
+		seed = &s
+	}
+	version := d.uvarint()
+
+	count := d.uvarint()
+	if d.err == nil && count > uint64(len(d.buf)) {
+		return errShortBuffer
+	}
+	nodes := make([]*Node, 0, count)
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		n := &Node{
+			name:   d.string(),
+			hash:   d.uint64(),
+			weight: math.Float64frombits(d.uint64()),
+			load:   new(atomic.Int64),
+		}
+		flags := d.byte()
+		n.drained = flags&binaryDrained != 0
+		if flags&binaryCapacity != 0 {
+			n.capacity = d.varint()
+		}
+		if flags&binaryTags != 0 {
+			tags := d.uvarint()
+			if d.err == nil && tags > uint64(len(d.buf)) {
+				return errShortBuffer
+			}
+			n.tags = make(map[string]string, tags)
+			for j := uint64(0); j < tags; j++ {
+				k := d.string()
+				n.tags[k] = d.string()
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if err := checkSorted(nodes); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	opts, err := r.decodedOptions(hasherName, seed)
+	if err != nil {
+		return err
+	}
+	if hasherName == "" {
+		hasher := opts.seededHasher()
+		for _, n := range nodes {
+			n.hash = hasher(n.name)
+		}
+	}
+
+	if err := r.setHashing(opts); err != nil {
+		return err
+	}
+	r.store(nodes)
+	r.restoreVersion(version)
+
+	return nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// decoder reads binary fields, recording the first error and returning zero
+// values after it.
+type decoder struct {
+	buf  []byte
+	err  error
+	wire int // wire type of the current protobuf field
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.buf) {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) byte() byte {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.buf)) {
+		d.err = errShortBuffer
+		return ""
+	}
+	return string(d.next(int(n)))
+}
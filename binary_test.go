@@ -0,0 +1,131 @@
+package rendezvous
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_MarshalBinary(t *testing.T) {
+	rv := New(WithSeed(42), WithNodes("a", "b", "d"))
+	rv.AddWithTags("c", 2.5, map[string]string{"zone": "z1", "rack": "r1"})
+	rv.Drain("b")
+	rv.SetCapacity("a", 10)
+
+	data, err := rv.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	var decoded Ring
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if expected, actual := rv.LookupAll(key), decoded.LookupAll(key); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+	if tags := decoded.Tags("c"); !reflect.DeepEqual(tags, rv.Tags("c")) {
+		t.Errorf("Expected %v but got %v", rv.Tags("c"), tags)
+	}
+	if !decoded.Drained("b") || decoded.Weight("c") != 2.5 {
+		t.Errorf("Expected states and weights to be decoded")
+	}
+	if n, _ := decoded.get("a"); n.capacity != 10 {
+		t.Errorf("Expected capacity 10 but got %d", n.capacity)
+	}
+
+	json, _ := rv.MarshalJSON()
+	decodedJSON, _ := decoded.MarshalJSON()
+	if string(json) != string(decodedJSON) {
+		t.Errorf("Expected %s but got %s", json, decodedJSON)
+	}
+}
+
+func TestRing_MarshalBinary_Deterministic(t *testing.T) {
+	tags := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		tags["k"+strconv.Itoa(i)] = "v"
+	}
+	rv := New()
+	rv.AddWithTags("a", 1, tags)
+
+	data, _ := rv.MarshalBinary()
+	for i := 0; i < 20; i++ {
+		if again, _ := rv.MarshalBinary(); !bytes.Equal(again, data) {
+			t.Fatalf("Expected the same encoding every time")
+		}
+	}
+}
+
+func TestRing_UnmarshalBinary(t *testing.T) {
+	data, _ := New(WithNodes("a", "b")).MarshalBinary()
+
+	rv := New(WithNodes("x"))
+	for i := range data {
+		if err := rv.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("Expected an error for %d bytes", i)
+		}
+	}
+	if err := rv.UnmarshalBinary(append([]byte{2}, data[1:]...)); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+	if names := rv.List(); !reflect.DeepEqual(names, []string{"x"}) {
+		t.Errorf("Expected a failed decode to keep the ring but got %v", names)
+	}
+
+	if err := rv.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected [a b] but got %v", names)
+	}
+
+	seeded, _ := New(WithSeed(42), WithNodes("c")).MarshalBinary()
+	if err := rv.UnmarshalBinary(seeded); !errors.Is(err, ErrHashingChanged) {
+		t.Errorf("Expected ErrHashingChanged but got %v", err)
+	}
+	if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected a failed decode to keep the ring but got %v", names)
+	}
+}
+
+func BenchmarkRing_UnmarshalBinary(b *testing.B) {
+	rv := New()
+	names := make([]string, 100000)
+	for i := range names {
+		names[i] = "node-" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+	data, _ := rv.MarshalBinary()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded Ring
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRing_UnmarshalBinary_UnnamedHasher(t *testing.T) {
+	data, _ := New(WithHasher(otherHasher), WithNodes("a", "b", "c")).MarshalBinary()
+
+	rv := New()
+	if err := rv.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	fresh := New(WithNodes("a", "b", "c"))
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		if owner, expected := rv.Lookup(key), fresh.Lookup(key); owner != expected {
+			t.Fatalf("Expected %s for %s but got %s", expected, key, owner)
+		}
+	}
+}
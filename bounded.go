@@ -0,0 +1,107 @@
+package rendezvous
+
+import "math"
+
+// SetCapacity limits the load Acquire assigns to the named node; zero removes
+// the limit. It reports whether the node exists.
+func (r *Ring) SetCapacity(name string, capacity int64) bool {
+	return r.update(name, func(n *Node) {
+		n.capacity = capacity
+	})
+}
+
+// Acquire assigns key to its owner, as Lookup would choose it, if that node
+// is below its load bound, and else to the highest ranked node that is,
+// increments that node's load and returns its name. The bound is the node's
+// capacity, if set, and its share of the load under WithLoadFactor, if
+// configured; without either, Acquire returns the same node as Lookup. It
+// returns "" if every node is at its bound or none may be selected; unlike
+// Lookup, it never returns the fallback of WithFallback, which bears no load.
+//
+// Every successful Acquire must be paired with a Release once the key no
+// longer uses the node.
+func (r *Ring) Acquire(key string) string {
+	nodes := r.load()
+	keyHash := r.computeHash(key)
+
+	// Only nodes Acquire may choose share the load, so inactive nodes count
+	// toward neither total.
+	var totalLoad int64
+	var totalWeight float64
+	for _, n := range nodes {
+		if active(n) {
+			totalLoad += n.load.Load()
+			totalWeight += n.weight
+		}
+	}
+
+	owner := r.owner(nodes, keyHash)
+	if owner != nil && r.acquire(owner, totalLoad, totalWeight) {
+		return owner.name
+	}
+
+	// Candidates are only ranked as far as the owner's bound requires.
+	var acquired string
+	r.stream(nodes, keyHash, active, func(s ScoredNode) bool {
+		if s.node == owner || !r.acquire(s.node, totalLoad, totalWeight) {
+			return true
+		}
+		acquired = s.node.name
+		return false
+	})
+	return acquired
+}
+
+// acquire increments the load of n and reports whether it was below its
+// bound, see loadBound.
+func (r *Ring) acquire(n *Node, totalLoad int64, totalWeight float64) bool {
+	bound := r.loadBound(n, totalLoad, totalWeight)
+	for {
+		load := n.load.Load()
+		if load >= bound {
+			return false
+		}
+		if n.load.CompareAndSwap(load, load+1) {
+			return true
+		}
+	}
+}
+
+// Release decrements the load of the named node after a key acquired with
+// Acquire no longer uses it.
+func (r *Ring) Release(name string) {
+	n, ok := r.get(name)
+	if !ok {
+		return
+	}
+	for {
+		load := n.load.Load()
+		if load <= 0 || n.load.CompareAndSwap(load, load-1) {
+			return
+		}
+	}
+}
+
+// Load returns the number of keys currently acquired on the named node.
+func (r *Ring) Load(name string) int64 {
+	n, ok := r.get(name)
+	if !ok {
+		return 0
+	}
+	return n.load.Load()
+}
+
+// loadBound returns the maximum load of n when totalLoad keys are assigned
+// across nodes with a combined weight of totalWeight, counting the key being
+// placed.
+func (r *Ring) loadBound(n *Node, totalLoad int64, totalWeight float64) int64 {
+	bound := int64(math.MaxInt64)
+	if r.opts.loadFactor > 0 && totalWeight > 0 {
+		share := float64(totalLoad+1) * n.weight / totalWeight
+		bound = int64(math.Ceil(r.opts.loadFactor * share))
+	}
+	if n.capacity > 0 && n.capacity < bound {
+		bound = n.capacity
+	}
+	return bound
+}
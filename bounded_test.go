@@ -0,0 +1,124 @@
+package rendezvous
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Acquire(t *testing.T) {
+	t.Run("MatchesLookupWithoutBounds", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c", "d", "e"))
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := rv.Lookup(key), rv.Acquire(key); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+		if load := rv.Load("a") + rv.Load("b") + rv.Load("c") + rv.Load("d") + rv.Load("e"); load != 100 {
+			t.Errorf("Expected a total load of 100 but got %d", load)
+		}
+	})
+
+	t.Run("MatchesRoutedLookup", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c", "d", "e"), WithSlotTable(64))
+		rv.Pin("k0", "e")
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := rv.Lookup(key), rv.Acquire(key); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+
+		// A pinned key overflows to the highest ranked other node.
+		rv.SetCapacity("e", rv.Load("e"))
+		if node := rv.Acquire("k0"); node == "e" || node == "" {
+			t.Errorf("Expected k0 to overflow but got %q", node)
+		}
+
+		fallback := New(WithFallback("default"))
+		if node := fallback.Acquire("k0"); node != "" {
+			t.Errorf("Expected no node but got %s", node)
+		}
+	})
+
+	t.Run("RespectsLoadFactor", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c", "d"), WithLoadFactor(1.25))
+		rv.AddWithWeight("e", 2.0)
+
+		// A single hot key would otherwise land on one node every time.
+		for i := 0; i < 600; i++ {
+			if node := rv.Acquire("hot"); node == "" {
+				t.Fatalf("Expected a node")
+			}
+		}
+
+		for _, name := range rv.List() {
+			share := 600 * rv.Weight(name) / 6
+			if load := rv.Load(name); float64(load) > math.Ceil(1.25*share) {
+				t.Errorf("Expected %s to stay within its bound but got %d", name, load)
+			}
+		}
+	})
+
+	t.Run("IgnoresInactiveNodes", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c"), WithLoadFactor(1.25))
+		rv.Drain("b")
+		rv.AddWithWeight("c", 0)
+
+		// a is the only node left, so it takes every key.
+		for i := 0; i < 10; i++ {
+			if node := rv.Acquire("k" + strconv.Itoa(i)); node != "a" {
+				t.Fatalf("Expected a but got %q after %d keys", node, i)
+			}
+		}
+	})
+
+	t.Run("RespectsCapacity", func(t *testing.T) {
+		rv := New(WithNodes("a", "b"))
+		rv.SetCapacity("a", 2)
+		rv.SetCapacity("b", 1)
+
+		for i := 0; i < 3; i++ {
+			if node := rv.Acquire("k"); node == "" {
+				t.Fatalf("Expected a node")
+			}
+		}
+		if node := rv.Acquire("k"); node != "" {
+			t.Errorf("Expected no node with all capacity used but got %s", node)
+		}
+
+		rv.Release("a")
+		if node := rv.Acquire("k"); node != "a" {
+			t.Errorf("Expected a after releasing it but got %s", node)
+		}
+	})
+
+	t.Run("LoadSurvivesWeightChange", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		rv.Acquire("k")
+		rv.AddWithWeight("a", 3.0)
+
+		if load := rv.Load("a"); load != 1 {
+			t.Errorf("Expected 1 but got %d", load)
+		}
+		rv.Release("a")
+		rv.Release("a")
+		if load := rv.Load("a"); load != 0 {
+			t.Errorf("Expected 0 but got %d", load)
+		}
+	})
+}
+
+func TestRing_SetCapacity(t *testing.T) {
+	rv := New(WithNodes("a"))
+	if !rv.SetCapacity("a", 10) {
+		t.Errorf("Expected SetCapacity to find a")
+	}
+	if rv.SetCapacity("z", 10) {
+		t.Errorf("Expected SetCapacity to report a non-existent node")
+	}
+	if rv.Contains("z") {
+		t.Errorf("Expected SetCapacity not to add nodes")
+	}
+}
@@ -0,0 +1,23 @@
+package rendezvous
+
+// A LookupBuffer holds scratch space that is reused across lookups, so that
+// repeated LookupAllWithBuffer calls on a large ring do not allocate once the
+// buffer has grown to the ring size. A LookupBuffer must not be used by
+// multiple goroutines at the same time.
+type LookupBuffer struct {
+	scoredNodes []ScoredNode
+	names       []string
+}
+
+// LookupAllWithBuffer is like LookupAll but builds the result in buf. The
+// returned slice aliases buf and is only valid until buf is used again.
+func (r *Ring) LookupAllWithBuffer(key string, buf *LookupBuffer) []string {
+	buf.scoredNodes = r.rankInto(buf.scoredNodes, r.load(), r.computeHash(key))
+
+	buf.names = buf.names[:0]
+	for _, scoredNode := range buf.scoredNodes {
+		buf.names = append(buf.names, scoredNode.node.name)
+	}
+
+	return buf.names
+}
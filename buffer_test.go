@@ -0,0 +1,38 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_LookupAllWithBuffer(t *testing.T) {
+	rv := New()
+	for i := 0; i < 100; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), float64(i%4+1))
+	}
+
+	var buf LookupBuffer
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if expected, actual := rv.LookupAll(key), rv.LookupAllWithBuffer(key, &buf); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+
+	t.Run("DoesNotAllocate", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			rv.LookupAllWithBuffer("foo", &buf)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations but got %v", allocs)
+		}
+	})
+
+	t.Run("ShrinkingRing", func(t *testing.T) {
+		rv.RemoveAll([]string{"n1", "n2", "n3"})
+		if expected, actual := rv.LookupAll("foo"), rv.LookupAllWithBuffer("foo", &buf); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	})
+}
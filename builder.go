@@ -0,0 +1,148 @@
+package rendezvous
+
+// A Builder accumulates nodes for an ImmutableRing, for services whose
+// membership is fixed at startup. A Builder must not be used by multiple
+// goroutines at the same time.
+type Builder struct {
+	opts    []Option
+	members map[string]Member
+}
+
+// NewBuilder returns a Builder of rings configured by opts.
+func NewBuilder(opts ...Option) *Builder {
+	return &Builder{opts: opts, members: make(map[string]Member)}
+}
+
+// Add adds the named node with the default weight.
+func (b *Builder) Add(name string) *Builder {
+	return b.AddWithTags(name, defaultWeight, nil)
+}
+
+// AddWithWeight adds the named node with the given weight, or replaces the
+// weight of a node added before. A weight of zero makes the node a member
+// that is never selected, see Ring.AddWithWeight.
+func (b *Builder) AddWithWeight(name string, weight float64) *Builder {
+	return b.AddWithTags(name, weight, nil)
+}
+
+// AddWithTags adds the named node with the given weight and tags, or
+// replaces the weight and tags of a node added before.
+func (b *Builder) AddWithTags(name string, weight float64, tags map[string]string) *Builder {
+	b.members[name] = Member{Name: name, Weight: weight, HasWeight: true, Tags: copyTags(tags)}
+	return b
+}
+
+// Remove removes a node added before.
+func (b *Builder) Remove(name string) *Builder {
+	delete(b.members, name)
+	return b
+}
+
+// Build returns an ImmutableRing of the nodes added so far, and of the nodes
+// of WithNodes options. If a node's weight is invalid, see CheckWeight, it
+// returns ErrInvalidWeight. The Builder may be used to build further rings.
+func (b *Builder) Build() (*ImmutableRing, error) {
+	r := New(b.opts...)
+
+	nodes := r.load()
+	members := make([]Member, 0, len(nodes)+len(b.members))
+	for _, n := range nodes {
+		members = append(members, Member{Name: n.name, Weight: n.weight, HasWeight: true, Tags: n.tags})
+	}
+	for _, m := range b.members {
+		members = append(members, m)
+	}
+	if err := r.Reconcile(members); err != nil {
+		return nil, err
+	}
+
+	return &ImmutableRing{ring: r}, nil
+}
+
+// An ImmutableRing is a Ring whose membership never changes after Build. It
+// has no methods to change it, so lookups never contend with writers and may
+// be shared freely between goroutines.
+type ImmutableRing struct {
+	ring *Ring
+}
+
+// Lookup returns the highest ranked node for key, or "" if the ring is
+// empty.
+func (r *ImmutableRing) Lookup(key string) string {
+	return r.ring.Lookup(key)
+}
+
+// LookupBytes is like Lookup but takes a []byte key.
+func (r *ImmutableRing) LookupBytes(key []byte) string {
+	return r.ring.LookupBytes(key)
+}
+
+// LookupHash is like Lookup but takes a key hashed by Hash.
+func (r *ImmutableRing) LookupHash(keyHash uint64) string {
+	return r.ring.LookupHash(keyHash)
+}
+
+// LookupNode returns the node owning key, or nil if the ring is empty.
+func (r *ImmutableRing) LookupNode(key string) *Node {
+	return r.ring.LookupNode(key)
+}
+
+// LookupTopN returns the n highest ranked nodes for key.
+func (r *ImmutableRing) LookupTopN(key string, n int) []string {
+	return r.ring.LookupTopN(key, n)
+}
+
+// LookupTopNNodes is like LookupTopN but returns the nodes.
+func (r *ImmutableRing) LookupTopNNodes(key string, n int) []*Node {
+	return r.ring.LookupTopNNodes(key, n)
+}
+
+// LookupAll returns every node ranked for key.
+func (r *ImmutableRing) LookupAll(key string) []string {
+	return r.ring.LookupAll(key)
+}
+
+// Score returns the rendezvous score of the named node for key, and false
+// if the node does not exist.
+func (r *ImmutableRing) Score(key, name string) (float64, bool) {
+	return r.ring.Score(key, name)
+}
+
+// Hash returns the hash of key as the ring computes it.
+func (r *ImmutableRing) Hash(key string) uint64 {
+	return r.ring.Hash(key)
+}
+
+// Contains reports whether the ring has the named node.
+func (r *ImmutableRing) Contains(name string) bool {
+	_, ok := r.ring.get(name)
+	return ok
+}
+
+// Weight returns the named node's weight, or 0 if it does not exist.
+func (r *ImmutableRing) Weight(name string) float64 {
+	if n, ok := r.ring.get(name); ok {
+		return n.weight
+	}
+	return 0
+}
+
+// Tags returns a copy of the named node's tags.
+func (r *ImmutableRing) Tags(name string) map[string]string {
+	return r.ring.Tags(name)
+}
+
+// List returns the names of the nodes in ascending order.
+func (r *ImmutableRing) List() []string {
+	return r.ring.List()
+}
+
+// Len returns the number of nodes.
+func (r *ImmutableRing) Len() int {
+	return r.ring.Len()
+}
+
+// Ring returns a mutable copy of the ring.
+func (r *ImmutableRing) Ring() *Ring {
+	return r.ring.Clone()
+}
@@ -0,0 +1,68 @@
+package rendezvous
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(WithSeed(3), WithNodes("a")).
+		Add("b").
+		AddWithWeight("c", 2).
+		AddWithTags("d", 1, map[string]string{ZoneTag: "z1"}).
+		Add("e").
+		Remove("e")
+	ir, err := b.Build()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	expected := New(WithSeed(3), WithNodes("a", "b", "d"))
+	expected.AddWithWeight("c", 2)
+	if names := ir.List(); !reflect.DeepEqual(names, expected.List()) || ir.Len() != 4 {
+		t.Errorf("Expected %v but got %v", expected.List(), names)
+	}
+	if ir.Weight("c") != 2 || !ir.Contains("d") || ir.Contains("e") || ir.Tags("d")[ZoneTag] != "z1" {
+		t.Errorf("Expected the built weights and tags")
+	}
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		if node, want := ir.Lookup(key), expected.Lookup(key); node != want {
+			t.Fatalf("Expected %s but got %s", want, node)
+		}
+		if node := ir.LookupHash(ir.Hash(key)); node != expected.Lookup(key) {
+			t.Fatalf("Expected %s but got %s", expected.Lookup(key), node)
+		}
+	}
+
+	// Later changes to the builder or the thawed ring do not affect it.
+	b.Add("f")
+	ir.Ring().Add("g")
+	if again, _ := b.Build(); ir.Len() != 4 || again.Len() != 5 {
+		t.Errorf("Expected built rings to be immutable")
+	}
+}
+
+func TestBuilder_Weights(t *testing.T) {
+	ir, err := NewBuilder().Add("a").AddWithWeight("z", 0).Build()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if !ir.Contains("z") || ir.Weight("z") != 0 {
+		t.Errorf("Expected z to be weighted zero but got %v", ir.Weight("z"))
+	}
+	for i := 0; i < 1000; i++ {
+		if node := ir.Lookup(strconv.Itoa(i)); node != "a" {
+			t.Fatalf("Expected a but got %s", node)
+		}
+	}
+
+	for _, weight := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if _, err := NewBuilder().Add("a").AddWithWeight("f", weight).Build(); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("Expected %v for %v but got %v", ErrInvalidWeight, weight, err)
+		}
+	}
+}
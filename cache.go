@@ -0,0 +1,137 @@
+package rendezvous
+
+import (
+	"container/list"
+	"sync"
+)
+
+// A lookupCache is an LRU cache of the nodes Lookup returned, see
+// WithLookupCache. It is valid for a single ring version and set of pins and
+// is emptied as soon as either changes.
+type lookupCache struct {
+	mutex   sync.Mutex
+	size    int
+	version uint64
+	pins    *map[uint64]pin
+	entries map[string]*list.Element
+	order   list.List // of *cacheEntry, most recently used first
+
+	hits, misses, invalidations uint64
+}
+
+type cacheEntry struct {
+	key  string
+	node string
+}
+
+// LookupCacheStats describes the use of a ring's lookup cache.
+type LookupCacheStats struct {
+	Hits, Misses uint64
+	// Invalidations counts how often the cache was emptied because the
+	// ring or its pins changed.
+	Invalidations uint64
+	// Len is the number of cached keys.
+	Len int
+}
+
+func newLookupCache(size int) *lookupCache {
+	return &lookupCache{size: size, entries: make(map[string]*list.Element, size)}
+}
+
+// get returns the cached node of key. The caller passes the ring version and
+// pins it observed.
+func (c *lookupCache) get(key string, version uint64, pins *map[uint64]pin) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.validate(version, pins)
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return "", false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).node, true
+}
+
+// put caches the node of key looked up at the given version and pins,
+// unless the ring has changed since.
+func (c *lookupCache) put(key, node string, version uint64, pins *map[uint64]pin) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if version != c.version || pins != c.pins {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cacheEntry).node = node
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.order.Remove(oldest)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, node: node})
+}
+
+// validate empties the cache if it was filled at another version or with
+// other pins. The caller must hold the mutex.
+func (c *lookupCache) validate(version uint64, pins *map[uint64]pin) {
+	if version == c.version && pins == c.pins {
+		return
+	}
+	if c.order.Len() > 0 {
+		clear(c.entries)
+		c.order.Init()
+		c.invalidations++
+	}
+	c.version, c.pins = version, pins
+}
+
+// lookupCached is Lookup through the cache.
+func (r *Ring) lookupCached(key string) string {
+	node, _ := r.cachedOwner(key)
+	if node == "" {
+		node = r.opts.fallback
+	}
+	r.observeLookup(node)
+	return node
+}
+
+// cachedOwner returns the name of the node owning key through the cache, or
+// "" if there is none, together with the snapshot it was looked up in. The
+// version is loaded before the snapshot, so a result is never cached under a
+// version newer than its snapshot.
+func (r *Ring) cachedOwner(key string) (string, []*Node) {
+	version, pins := r.version.Load(), r.pins.Load()
+	nodes := r.load()
+	node, ok := r.cache.get(key, version, pins)
+	if !ok {
+		if n := r.owner(nodes, r.computeHash(key)); n != nil {
+			node = n.name
+		}
+		r.cache.put(key, node, version, pins)
+	}
+	return node, nodes
+}
+
+// LookupCacheStats returns the statistics of the cache enabled with
+// WithLookupCache, or zero statistics if there is none.
+func (r *Ring) LookupCacheStats() LookupCacheStats {
+	if r.cache == nil {
+		return LookupCacheStats{}
+	}
+
+	r.cache.mutex.Lock()
+	defer r.cache.mutex.Unlock()
+
+	return LookupCacheStats{
+		Hits:          r.cache.hits,
+		Misses:        r.cache.misses,
+		Invalidations: r.cache.invalidations,
+		Len:           r.cache.order.Len(),
+	}
+}
@@ -0,0 +1,77 @@
+package rendezvous
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestWithLookupCache(t *testing.T) {
+	rv := New(WithLookupCache(2), WithNodes("a", "b", "c"))
+	plain := New(WithNodes("a", "b", "c"))
+
+	for i := 0; i < 3; i++ {
+		if node, expected := rv.Lookup("foo"), plain.Lookup("foo"); node != expected {
+			t.Fatalf("Expected %s but got %s", expected, node)
+		}
+	}
+	if stats := rv.LookupCacheStats(); stats.Hits != 2 || stats.Misses != 1 || stats.Len != 1 {
+		t.Errorf("Expected 2 hits and 1 miss but got %+v", stats)
+	}
+
+	// The least recently used key is evicted.
+	rv.Lookup("bar")
+	rv.Lookup("foo")
+	rv.Lookup("baz")
+	rv.Lookup("foo")
+	if stats := rv.LookupCacheStats(); stats.Hits != 4 || stats.Len != 2 {
+		t.Errorf("Expected 4 hits and 2 keys but got %+v", stats)
+	}
+
+	// Membership changes and pins invalidate the cache.
+	owner := rv.Lookup("foo")
+	rv.Drain(owner)
+	if node := rv.Lookup("foo"); node == owner {
+		t.Errorf("Expected a node other than the drained %s", owner)
+	}
+	rv.Pin("foo", owner)
+	rv.Activate(owner)
+	rv.Pin("foo", "c")
+	if node := rv.Lookup("foo"); node != "c" {
+		t.Errorf("Expected the pinned node c but got %s", node)
+	}
+	if stats := rv.LookupCacheStats(); stats.Invalidations != 2 {
+		t.Errorf("Expected 2 invalidations but got %+v", stats)
+	}
+
+	if stats := plain.LookupCacheStats(); stats != (LookupCacheStats{}) {
+		t.Errorf("Expected no statistics without a cache but got %+v", stats)
+	}
+}
+
+func TestWithLookupCache_Concurrent(t *testing.T) {
+	rv := New(WithLookupCache(16), WithNodes("a", "b", "c"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				rv.Lookup(strconv.Itoa(j % 32))
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		rv.AddWithWeight("d", float64(1+i%3))
+	}
+	wg.Wait()
+
+	plain := New(WithNodes("a", "b", "c"))
+	plain.AddWithWeight("d", rv.Weight("d"))
+	for j := 0; j < 32; j++ {
+		if node, expected := rv.Lookup(strconv.Itoa(j)), plain.Lookup(strconv.Itoa(j)); node != expected {
+			t.Fatalf("Expected %s but got %s", expected, node)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package rendezvous
+
+// OnAdd registers fn to be called synchronously with every node added to the
+// ring, before the write that added it returns. Like all callbacks, fn must
+// not modify the ring. The returned function unregisters fn.
+func (r *Ring) OnAdd(fn func(n *Node)) (unregister func()) {
+	return r.on(func(e ChangeEvent) {
+		if e.Type == NodeAdded {
+			fn(e.New)
+		}
+	})
+}
+
+// OnRemove registers fn to be called synchronously with every node removed
+// from the ring. The returned function unregisters fn.
+func (r *Ring) OnRemove(fn func(n *Node)) (unregister func()) {
+	return r.on(func(e ChangeEvent) {
+		if e.Type == NodeRemoved {
+			fn(e.Old)
+		}
+	})
+}
+
+// OnWeightChange registers fn to be called synchronously with the state
+// before and after every change to a node's weight. The returned function
+// unregisters fn.
+func (r *Ring) OnWeightChange(fn func(before, after *Node)) (unregister func()) {
+	return r.on(func(e ChangeEvent) {
+		if e.Type == NodeChanged && e.Old.weight != e.New.weight {
+			fn(e.Old, e.New)
+		}
+	})
+}
+
+// OnChange registers fn to be called synchronously with every change to the
+// ring, see Watch. The returned function unregisters fn.
+func (r *Ring) OnChange(fn func(e ChangeEvent)) (unregister func()) {
+	return r.on(fn)
+}
+
+func (r *Ring) on(fn func(ChangeEvent)) func() {
+	l := r.listen(fn)
+	return func() { r.unlisten(l) }
+}
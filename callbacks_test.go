@@ -0,0 +1,55 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_Callbacks(t *testing.T) {
+	rv := New(WithNodes("a"))
+
+	var calls []string
+	unregisterAdd := rv.OnAdd(func(n *Node) {
+		calls = append(calls, "add "+n.Name())
+	})
+	rv.OnRemove(func(n *Node) {
+		calls = append(calls, "remove "+n.Name())
+	})
+	rv.OnWeightChange(func(before, after *Node) {
+		if before.Weight() != 1 || after.Weight() != 3 {
+			t.Errorf("Expected weight 1 to 3 but got %v to %v", before.Weight(), after.Weight())
+		}
+		if !rv.Contains(after.Name()) {
+			t.Errorf("Expected callbacks to see the change")
+		}
+		calls = append(calls, "weight "+after.Name())
+	})
+
+	rv.AddAll([]string{"b", "c"})
+	rv.AddWithWeight("b", 3)
+	rv.Drain("c")
+	rv.Remove("a")
+	unregisterAdd()
+	rv.Add("d")
+
+	expected := []string{"add b", "add c", "weight b", "remove a"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Errorf("Expected %v but got %v", expected, calls)
+	}
+}
+
+func TestRing_OnChange(t *testing.T) {
+	rv := New()
+
+	var types []ChangeType
+	rv.OnChange(func(e ChangeEvent) {
+		types = append(types, e.Type)
+	})
+	rv.Add("a")
+	rv.Drain("a")
+	rv.Remove("a")
+
+	if expected := []ChangeType{NodeAdded, NodeChanged, NodeRemoved}; !reflect.DeepEqual(expected, types) {
+		t.Errorf("Expected %v but got %v", expected, types)
+	}
+}
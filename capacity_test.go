@@ -0,0 +1,50 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestWithCapacity(t *testing.T) {
+	rv := New(WithCapacity(3))
+	rv.Add("a")
+	rv.AddAll([]string{"b", "c"})
+	if rv.reserve == nil || len(rv.reserve.nodes) != 3 {
+		t.Fatalf("Expected the nodes to be allocated from the reserve")
+	}
+
+	// Writes beyond the capacity fall back to allocating.
+	rv.AddAll([]string{"d", "e"})
+	if rv.reserve != nil {
+		t.Errorf("Expected the reserve to be dropped")
+	}
+	if names := rv.List(); len(names) != 5 {
+		t.Errorf("Expected 5 nodes but got %v", names)
+	}
+
+	plain := New(WithNodes("a", "b", "c", "d", "e"))
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		if node, expected := rv.Lookup(key), plain.Lookup(key); node != expected {
+			t.Fatalf("Expected %s but got %s", expected, node)
+		}
+	}
+}
+
+func BenchmarkRing_Add_Capacity(b *testing.B) {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	for _, capacity := range []int{0, len(names)} {
+		b.Run(strconv.Itoa(capacity), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rv := New(WithCapacity(capacity))
+				for _, name := range names {
+					rv.Add(name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AddChecked is like Add but validates the node, see AddWithWeightChecked.
+func (r *Ring) AddChecked(name string) error {
+	return r.AddWithWeightChecked(name, defaultWeight)
+}
+
+// AddWithWeightChecked is like AddWithWeight but returns ErrEmptyName or
+// ErrInvalidWeight instead of adding an invalid node, and ErrNodeExists
+// instead of reweighting an existing one. Callers that accept duplicate adds
+// may ignore ErrNodeExists, in which case the node keeps its weight.
+func (r *Ring) AddWithWeightChecked(name string, weight float64) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if err := CheckWeight(weight); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nodes := r.load()
+	if ix := sort.Search(len(nodes), cmp(nodes, name)); ix < len(nodes) && nodes[ix].name == name {
+		return fmt.Errorf("%w: %q", ErrNodeExists, name)
+	}
+	r.upsertLocked(name, func(n *Node) {
+		n.weight = weight
+	})
+	return nil
+}
+
+// SetWeight changes the weight of the named node. Unlike AddWithWeight, it
+// never adds the node: it returns ErrNodeNotFound if the node is not a
+// member, so a controller reweighting nodes cannot resurrect a removed one.
+// It returns ErrInvalidWeight for weights that are negative, infinite or NaN.
+func (r *Ring) SetWeight(name string, weight float64) error {
+	if err := CheckWeight(weight); err != nil {
+		return err
+	}
+	if !r.update(name, func(n *Node) {
+		n.weight = weight
+	}) {
+		return fmt.Errorf("%w: %q", ErrNodeNotFound, name)
+	}
+	return nil
+}
+
+// CheckWeight returns ErrInvalidWeight for weights that are negative,
+// infinite or NaN. Weights of zero are valid, see AddWithWeight.
+func CheckWeight(weight float64) error {
+	if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("%w: %v", ErrInvalidWeight, weight)
+	}
+	return nil
+}
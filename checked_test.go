@@ -0,0 +1,117 @@
+package rendezvous
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRing_AddWithWeightChecked(t *testing.T) {
+	rv := New()
+	if err := rv.AddChecked("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rv.AddWithWeightChecked("b", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		weight float64
+		err    error
+	}{
+		{"", 1, ErrEmptyName},
+		{"c", -1, ErrInvalidWeight},
+		{"c", math.Inf(1), ErrInvalidWeight},
+		{"c", math.NaN(), ErrInvalidWeight},
+		{"b", 3, ErrNodeExists},
+	} {
+		if err := rv.AddWithWeightChecked(test.name, test.weight); !errors.Is(err, test.err) {
+			t.Errorf("Expected %v for %q with weight %v but got %v", test.err, test.name, test.weight, err)
+		}
+	}
+
+	if rv.Len() != 2 || rv.Weight("b") != 2 {
+		t.Errorf("Expected rejected adds to leave the ring unchanged")
+	}
+}
+
+func TestRing_SetWeight(t *testing.T) {
+	rv := New(WithNodes("a"))
+	if err := rv.SetWeight("a", 3); err != nil || rv.Weight("a") != 3 {
+		t.Errorf("Expected a to be reweighted but got %v", err)
+	}
+
+	rv.Remove("a")
+	version := rv.Version()
+	if err := rv.SetWeight("a", 2); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected %v but got %v", ErrNodeNotFound, err)
+	}
+	if rv.Contains("a") || rv.Version() != version {
+		t.Errorf("Expected a removed node not to be resurrected")
+	}
+
+	rv.Add("b")
+	if err := rv.SetWeight("b", math.NaN()); !errors.Is(err, ErrInvalidWeight) || rv.Weight("b") != 1 {
+		t.Errorf("Expected %v but got %v", ErrInvalidWeight, err)
+	}
+}
+
+func TestRing_LookupErr(t *testing.T) {
+	rv := New()
+	if _, err := rv.LookupErr("k"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected %v but got %v", ErrEmptyRing, err)
+	}
+
+	rv.Add("a")
+	if node, err := rv.LookupErr("k"); node != "a" || err != nil {
+		t.Errorf("Expected a but got %q, %v", node, err)
+	}
+
+	rv.Drain("a")
+	if _, err := rv.LookupErr("k"); !errors.Is(err, ErrNoActiveNodes) {
+		t.Errorf("Expected %v but got %v", ErrNoActiveNodes, err)
+	}
+}
+
+func TestRing_LookupErr_Cached(t *testing.T) {
+	rv := New(WithLookupCache(8), WithNodes("a"))
+	for i := 0; i < 3; i++ {
+		if node, err := rv.LookupErr("k"); node != "a" || err != nil {
+			t.Fatalf("Expected a but got %q, %v", node, err)
+		}
+	}
+	if hits := rv.LookupCacheStats().Hits; hits != 2 {
+		t.Errorf("Expected 2 cache hits but got %d", hits)
+	}
+
+	rv.Remove("a")
+	if _, err := rv.LookupErr("k"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected %v but got %v", ErrEmptyRing, err)
+	}
+}
+
+func TestRing_LookupErr_Concurrent(t *testing.T) {
+	rv := New()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			rv.Add("a")
+			rv.Remove("a")
+		}
+	}()
+
+	// The ring is either empty or has an active node, never only inactive
+	// ones.
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if node, err := rv.LookupErr("k"); errors.Is(err, ErrNoActiveNodes) || (err == nil) != (node == "a") {
+			t.Fatalf("Expected a or %v but got %q, %v", ErrEmptyRing, node, err)
+		}
+	}
+}
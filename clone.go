@@ -0,0 +1,55 @@
+package rendezvous
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clone returns an independent copy of the ring with the same nodes, weights,
+// tags, states and configuration. Changes to either ring, including loads
+// acquired with Acquire and the state of a BackendCloner, do not affect the
+// other. Node payloads are shared; TTLs are not, so the nodes of the copy
+// never expire.
+func (r *Ring) Clone() *Ring {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := &Ring{
+		opts:   r.opts,
+		hasher: r.hasher,
+		mutex:  sync.Mutex{},
+	}
+	c.opts.backend = copyBackend(r.opts.backend)
+	if r.opts.cacheSize > 0 {
+		c.cache = newLookupCache(r.opts.cacheSize)
+	}
+
+	nodes := r.load()
+	cloned := make([]*Node, len(nodes))
+	for i, n := range nodes {
+		cn := *n
+		cn.tags = copyTags(n.tags)
+		cn.load = new(atomic.Int64)
+		cn.load.Store(n.load.Load())
+		cloned[i] = &cn
+	}
+	c.store(cloned)
+	if pins := r.pins.Load(); pins != nil {
+		c.pins.Store(pins)
+	}
+	c.version.Store(r.version.Load())
+	if len(r.ramps) > 0 {
+		// The timer may fire before scheduleRamps returns.
+		c.mutex.Lock()
+		c.ramps = make(map[string]time.Time, len(r.ramps))
+		for name, start := range r.ramps {
+			c.ramps[name] = start
+		}
+		c.scheduleRamps()
+		c.mutex.Unlock()
+	}
+	c.listenOptions()
+
+	return c
+}
@@ -0,0 +1,58 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Clone(t *testing.T) {
+	rv := New(WithSeed(7), WithNodes("a", "b", "c"))
+	rv.AddWithTags("d", 2.0, map[string]string{"zone": "z1"})
+	rv.Drain("b")
+	rv.Acquire("foo")
+
+	c := rv.Clone()
+
+	t.Run("CopiesState", func(t *testing.T) {
+		if !reflect.DeepEqual(c.List(), rv.List()) {
+			t.Errorf("Expected %v but got %v", rv.List(), c.List())
+		}
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := rv.LookupAll(key), c.LookupAll(key); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("Expected %v but got %v", expected, actual)
+			}
+			if expected, actual := rv.Lookup(key), c.Lookup(key); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+		if w := c.Weight("d"); w != 2.0 {
+			t.Errorf("Expected 2.0 but got %v", w)
+		}
+		if zone := c.Tags("d")["zone"]; zone != "z1" {
+			t.Errorf("Expected z1 but got %s", zone)
+		}
+		if !c.Drained("b") {
+			t.Errorf("Expected b to stay drained")
+		}
+		owner := rv.Lookup("foo")
+		if c.Load(owner) != 1 {
+			t.Errorf("Expected the load of %s to be copied", owner)
+		}
+	})
+
+	t.Run("IsIndependent", func(t *testing.T) {
+		c.Remove("a")
+		c.AddWithWeight("d", 5.0)
+		c.Activate("b")
+		c.Release(rv.Lookup("foo"))
+
+		if !rv.Contains("a") || rv.Weight("d") != 2.0 || !rv.Drained("b") {
+			t.Errorf("Expected changes to the clone not to affect the ring")
+		}
+		if rv.Load(rv.Lookup("foo")) != 1 {
+			t.Errorf("Expected loads of the clone not to affect the ring")
+		}
+	})
+}
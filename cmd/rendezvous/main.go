@@ -0,0 +1,231 @@
+// Command rendezvous answers questions about a rendezvous ring from a
+// terminal, such as which node owns a key.
+//
+// Usage:
+//
+//	rendezvous <command> [flags] [keys...]
+//
+// The commands are:
+//
+//	lookup    print the node owning each key
+//	topn      print the n highest ranked nodes for each key
+//	list      print the nodes of the ring and their weights
+//	simulate  report the share of keys moved by adding, removing or
+//	          reweighting nodes
+//	analyze   report how evenly keys are spread over the nodes
+//
+// The ring is read from a JSON file written by Ring.MarshalJSON with -ring,
+// built from -nodes, a comma separated list of name[=weight], or both.
+// simulate and analyze use the given keys or, without keys, -keys synthetic
+// ones.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mosuka/rendezvous"
+	_ "github.com/mosuka/rendezvous/hashers/fnv"
+	_ "github.com/mosuka/rendezvous/hashers/murmur3"
+	"github.com/mosuka/rendezvous/ringflag"
+)
+
+const usage = "usage: rendezvous <lookup|topn|list|simulate|analyze> [flags] [keys...]"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "rendezvous:", err)
+		os.Exit(2)
+	}
+}
+
+// A config holds the parsed flags and arguments of a command.
+type config struct {
+	ring   *rendezvous.Ring
+	keys   []string
+	n      int
+	add    string
+	remove string
+}
+
+var commands = map[string]func(c *config, w io.Writer) error{
+	"lookup":   lookup,
+	"topn":     topN,
+	"list":     list,
+	"simulate": simulate,
+	"analyze":  analyze,
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	ringFile := flags.String("ring", "", "read the ring from a JSON `file`")
+	nodes := flags.String("nodes", "", "comma separated `name[=weight]` nodes")
+	hasher := flags.String("hasher", "", "`name` of a registered hash function")
+	seed := flags.Uint64("seed", 0, "hash `seed`")
+	c := &config{}
+	flags.IntVar(&c.n, "n", 3, "number of nodes printed by topn")
+	keys := flags.Int("keys", 100000, "number of synthetic keys for simulate and analyze")
+	flags.StringVar(&c.add, "add", "", "comma separated `name[=weight]` nodes added or reweighted by simulate")
+	flags.StringVar(&c.remove, "remove", "", "comma separated `names` of nodes removed by simulate")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var opts []rendezvous.Option
+	if *hasher != "" {
+		opt, err := namedHasher(*hasher)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, opt)
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts = append(opts, rendezvous.WithSeed(*seed))
+		}
+	})
+	c.ring = rendezvous.New(opts...)
+
+	if *ringFile != "" {
+		data, err := os.ReadFile(*ringFile)
+		if err != nil {
+			return err
+		}
+		if err := c.ring.UnmarshalJSON(data); err != nil {
+			return err
+		}
+	}
+	if err := parseNodes(*nodes, c.ring.AddWithWeight); err != nil {
+		return err
+	}
+
+	c.keys = flags.Args()
+	if len(c.keys) == 0 && (args[0] == "simulate" || args[0] == "analyze") {
+		c.keys = make([]string, *keys)
+		for i := range c.keys {
+			c.keys[i] = "key-" + strconv.Itoa(i)
+		}
+	}
+
+	return command(c, stdout)
+}
+
+func lookup(c *config, w io.Writer) error {
+	for _, key := range c.keys {
+		fmt.Fprintf(w, "%s\t%s\n", key, c.ring.Lookup(key))
+	}
+	return nil
+}
+
+func topN(c *config, w io.Writer) error {
+	for _, key := range c.keys {
+		fmt.Fprintf(w, "%s\t%s\n", key, strings.Join(c.ring.LookupTopN(key, c.n), ","))
+	}
+	return nil
+}
+
+func list(c *config, w io.Writer) error {
+	for _, name := range c.ring.List() {
+		state := ""
+		if c.ring.Drained(name) {
+			state = "\tdrained"
+		}
+		fmt.Fprintf(w, "%s\t%g%s\n", name, c.ring.Weight(name), state)
+	}
+	return nil
+}
+
+func simulate(c *config, w io.Writer) error {
+	p := c.ring.Preview()
+	if err := parseNodes(c.add, func(name string, weight float64) {
+		p.AddWithWeight(name, weight)
+	}); err != nil {
+		return err
+	}
+	for _, name := range ringflag.Split(c.remove) {
+		p.Remove(name)
+	}
+
+	moved := make(map[[2]string]int)
+	movements := p.Movements(c.keys)
+	for _, m := range movements {
+		moved[[2]string{m.From, m.To}]++
+	}
+	routes := make([][2]string, 0, len(moved))
+	for route := range moved {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if moved[routes[i]] != moved[routes[j]] {
+			return moved[routes[i]] > moved[routes[j]]
+		}
+		return routes[i][0]+"\x00"+routes[i][1] < routes[j][0]+"\x00"+routes[j][1]
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "moved\t%d of %d keys\t%.2f%%\n", len(movements), len(c.keys), percent(len(movements), len(c.keys)))
+	for _, route := range routes {
+		fmt.Fprintf(tw, "%s -> %s\t%d keys\t%.2f%%\n", route[0], route[1], moved[route], percent(moved[route], len(c.keys)))
+	}
+	return tw.Flush()
+}
+
+func analyze(c *config, w io.Writer) error {
+	report := c.ring.AnalyzeBalance(c.keys)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "node\tkeys\tshare\texpected\tratio\t")
+	for _, b := range report.Nodes {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%.2f%%\t%.3f\t\n", b.Name, b.Keys, 100*b.Share, 100*b.ExpectedShare, b.Ratio)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "stddev %.4f, min ratio %.3f, max ratio %.3f\n", report.StdDev, report.MinRatio, report.MaxRatio)
+	return err
+}
+
+// parseNodes calls add with every name[=weight] of a comma separated list,
+// see ringflag.ParseWeights. Names may contain colons, as in host:port.
+func parseNodes(s string, add func(name string, weight float64)) error {
+	weights, err := ringflag.ParseWeights(s)
+	if err != nil {
+		return err
+	}
+	for name, weight := range weights {
+		add(name, weight)
+	}
+	return nil
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// namedHasher returns rendezvous.WithNamedHasher for a registered name, and
+// an error for an unknown one.
+func namedHasher(name string) (rendezvous.Option, error) {
+	if !slices.Contains(rendezvous.Hashers(), name) {
+		return nil, fmt.Errorf("unknown hasher %q, expected one of %s", name, strings.Join(rendezvous.Hashers(), ", "))
+	}
+	return rendezvous.WithNamedHasher(name), nil
+}
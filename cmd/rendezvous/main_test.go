@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestRun(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b"))
+	ring.AddWithWeight("c", 2)
+	ring.Drain("b")
+	data, _ := ring.MarshalJSON()
+	ringFile := filepath.Join(t.TempDir(), "ring.json")
+	if err := os.WriteFile(ringFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Lookup", []string{"lookup", "-ring", ringFile, "k1", "k2"},
+			"k1\t" + ring.Lookup("k1") + "\nk2\t" + ring.Lookup("k2") + "\n"},
+		{"TopN", []string{"topn", "-ring", ringFile, "-n", "2", "k1"},
+			"k1\t" + strings.Join(ring.LookupTopN("k1", 2), ",") + "\n"},
+		{"List", []string{"list", "-ring", ringFile, "-nodes", "d=0.5"},
+			"a\t1\nb\t1\tdrained\nc\t2\nd\t0.5\n"},
+		{"HostPort", []string{"list", "-nodes", "10.0.0.1:8080,10.0.0.2:8080=2"},
+			"10.0.0.1:8080\t1\n10.0.0.2:8080\t2\n"},
+		{"Simulate", []string{"simulate", "-nodes", "a,b", "-remove", "b", "-keys", "1000"},
+			"moved  "},
+		{"SimulateList", []string{"simulate", "-nodes", "a,b,c", "-remove", "b, c,", "-keys", "1000"},
+			"moved   655 of 1000 keys"},
+		{"Analyze", []string{"analyze", "-nodes", "a", "k1", "k2"},
+			"  node  keys    share  expected  ratio\n     a     2  100.00%   100.00%  1.000\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tt.args, &out); err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			if !strings.HasPrefix(out.String(), tt.expected) {
+				t.Errorf("Expected %q but got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"unknown"},
+		{"lookup", "-hasher", "unknown"},
+		{"lookup", "-nodes", "a=x"},
+		{"lookup", "-nodes", "a=-1"},
+		{"lookup", "-ring", filepath.Join(t.TempDir(), "missing.json")},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package rendezvous
+
+// A RingDiff lists the changes turning the membership of one ring into that
+// of another, see Compare.
+type RingDiff struct {
+	// Added are the nodes only the second ring has, in ascending order.
+	Added []string
+	// Removed are the nodes only the first ring has, in ascending order.
+	Removed []string
+	// Reweighted are the nodes of both rings whose weights differ, in
+	// ascending order of their names, From being the first ring's weight.
+	Reweighted []WeightChange
+}
+
+// Empty reports whether both rings have the same nodes and weights.
+func (d RingDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Reweighted) == 0
+}
+
+// Compare returns the nodes added, removed and reweighted from a to b, for
+// example to reconcile a live ring a with a desired ring b by applying only
+// the necessary mutations. Tags and node states are not compared.
+func Compare(a, b *Ring) RingDiff {
+	var diff RingDiff
+	if a == b {
+		return diff
+	}
+	from, to := a.load(), b.load()
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case j == len(to) || (i < len(from) && from[i].name < to[j].name):
+			diff.Removed = append(diff.Removed, from[i].name)
+			i++
+		case i == len(from) || to[j].name < from[i].name:
+			diff.Added = append(diff.Added, to[j].name)
+			j++
+		default:
+			if from[i].weight != to[j].weight {
+				diff.Reweighted = append(diff.Reweighted, WeightChange{Name: from[i].name, From: from[i].weight, To: to[j].weight})
+			}
+			i++
+			j++
+		}
+	}
+	return diff
+}
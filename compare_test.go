@@ -0,0 +1,39 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	live := New(WithNodes("a", "b", "c"))
+	live.Drain("c")
+	desired := New(WithNodes("c", "d"))
+	desired.AddWithWeight("b", 2)
+
+	diff := Compare(live, desired)
+	expected := RingDiff{
+		Added:      []string{"d"},
+		Removed:    []string{"a"},
+		Reweighted: []WeightChange{{Name: "b", From: 1, To: 2}},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected %+v but got %+v", expected, diff)
+	}
+
+	for _, name := range diff.Added {
+		live.AddWithWeight(name, desired.Weight(name))
+	}
+	for _, name := range diff.Removed {
+		live.Remove(name)
+	}
+	for _, c := range diff.Reweighted {
+		live.AddWithWeight(c.Name, c.To)
+	}
+	if diff := Compare(live, desired); !diff.Empty() {
+		t.Errorf("Expected the applied diff to reconcile the rings but got %+v", diff)
+	}
+	if diff := Compare(live, live); !diff.Empty() {
+		t.Errorf("Expected no differences to the ring itself but got %+v", diff)
+	}
+}
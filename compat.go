@@ -0,0 +1,71 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// A Compatibility is another rendezvous hashing implementation whose key to
+// node mapping a ring reproduces, see WithCompatibility.
+type Compatibility int
+
+const (
+	// GoRendezvous is github.com/dgryski/go-rendezvous hashing with
+	// xxhash.Sum64String, the node picker of the go-redis Ring. A key maps
+	// to the node maximizing
+	//
+	//	h := xxhash64(key) ^ xxhash64(node)
+	//	h ^= h >> 12
+	//	h ^= h << 25
+	//	h ^= h >> 27
+	//	h *= 0x2545F4914F6CDD1D
+	//
+	// with 64-bit unsigned arithmetic and xxhash64 being XXH64 with seed 0,
+	// so services in any language can implement it in a few lines. The
+	// mapping is unweighted: every node with a positive weight is ranked as
+	// if its weight were 1. testdata/compat/go-rendezvous.json holds test
+	// vectors produced by go-rendezvous itself.
+	GoRendezvous Compatibility = iota + 1
+)
+
+// String returns the name of the implementation.
+func (c Compatibility) String() string {
+	switch c {
+	case GoRendezvous:
+		return "go-rendezvous"
+	}
+	return fmt.Sprintf("Compatibility(%d)", int(c))
+}
+
+// WithCompatibility reproduces the key to node mapping of another
+// implementation by fixing the hash function and score formula, so services
+// written in other languages agree with the ring on ownership. It must not
+// be followed by options changing them, such as WithSeed or WithWeighting,
+// nor combined with options placing keys by other means, such as
+// WithSlotTable, WithSkeleton or WithBackend. Ties, which need two nodes whose mixed
+// hashes share their 62 high bits, are broken as by every ring rather than
+// by the order nodes were added in. It panics if c is unknown.
+func WithCompatibility(c Compatibility) Option {
+	if c != GoRendezvous {
+		panic("rendezvous: unknown compatibility " + c.String())
+	}
+	return func(o *options) {
+		o.hasher = xxhash.Sum64String
+		o.hasherName = defaultHasherName
+		o.seed, o.seeded = 0, false
+		o.score = goRendezvousScore
+		o.defaultScore = false
+	}
+}
+
+// goRendezvousScore turns the mixed hash into a float64 ordered like the
+// hash itself: shifted right by two bits, it is the bit pattern of a finite,
+// non-negative float64, and such floats order like their bit patterns.
+func goRendezvousScore(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+	if nodeWeight <= 0 {
+		return 0
+	}
+	return math.Float64frombits(combineHashes(keyHash, nodeHash) >> 2)
+}
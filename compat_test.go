@@ -0,0 +1,46 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestWithCompatibility(t *testing.T) {
+	data, err := os.ReadFile("testdata/compat/go-rendezvous.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites []struct {
+		Nodes   []string
+		Vectors []struct {
+			Key, Node string
+		}
+	}
+	if err := json.Unmarshal(data, &suites); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, suite := range suites {
+		for _, opts := range [][]Option{
+			{WithCompatibility(GoRendezvous)},
+			{WithSeed(42), WithCompatibility(GoRendezvous)},
+		} {
+			rv := New(opts...)
+			rv.AddAll(suite.Nodes)
+			rv.AddWithWeight(suite.Nodes[0], 5)
+			for _, v := range suite.Vectors {
+				if node := rv.Lookup(v.Key); node != v.Node {
+					t.Errorf("Expected %q to map to %s among %d nodes but got %s", v.Key, v.Node, len(suite.Nodes), node)
+				}
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected an unknown compatibility to panic")
+		}
+	}()
+	WithCompatibility(Compatibility(0))
+}
@@ -0,0 +1,252 @@
+// Package config loads rendezvous rings from YAML or TOML files and writes
+// them back out, so rings can live in version-controlled configuration.
+//
+// A configuration names the ring's hash function and seed, both optional,
+// and lists its nodes, for example in YAML
+//
+//	hasher: xxhash
+//	nodes:
+//	  - name: 10.0.0.1:6379
+//	    weight: 2
+//	    tags:
+//	      zone: z1
+//	  - name: 10.0.0.2:6379
+//	    drained: true
+//
+// or in TOML
+//
+//	hasher = "xxhash"
+//
+//	[[nodes]]
+//	name = "10.0.0.1:6379"
+//	weight = 2.0
+//	tags = { zone = "z1" }
+//
+//	[[nodes]]
+//	name = "10.0.0.2:6379"
+//	drained = true
+//
+// A node without a weight has the default weight.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mosuka/rendezvous"
+	"gopkg.in/yaml.v3"
+)
+
+// A Format is an encoding of a Config.
+type Format int
+
+const (
+	// YAML encodes configurations as YAML.
+	YAML Format = iota + 1
+	// TOML encodes configurations as TOML.
+	TOML
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case YAML:
+		return "yaml"
+	case TOML:
+		return "toml"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// FormatOf returns the format of a file by its extension: .yaml or .yml for
+// YAML and .toml for TOML.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML, nil
+	case ".toml":
+		return TOML, nil
+	}
+	return 0, fmt.Errorf("config: unknown format of %s", path)
+}
+
+// A Config defines a ring.
+type Config struct {
+	// Hasher is the name of the ring's hash function, which must be
+	// registered, see rendezvous.RegisterHasher. Without a name, rings keep
+	// the hash function of their options.
+	Hasher string `json:"hasher,omitempty" yaml:"hasher,omitempty" toml:"hasher,omitempty"`
+	// Seed seeds the ring's hashes, see rendezvous.WithSeed. TOML integers
+	// are signed, so TOML seeds must not exceed math.MaxInt64.
+	Seed  *uint64 `json:"seed,omitempty" yaml:"seed,omitempty" toml:"seed,omitempty"`
+	Nodes []Node  `json:"nodes" yaml:"nodes" toml:"nodes"`
+}
+
+// A Node defines a node of a ring.
+type Node struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Weight is the node's weight; nil means the default weight, and zero
+	// a member that is never selected.
+	Weight  *float64          `json:"weight,omitempty" yaml:"weight,omitempty" toml:"weight,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Drained bool              `json:"drained,omitempty" yaml:"drained,omitempty" toml:"drained,omitempty"`
+}
+
+// Parse decodes a configuration encoded in format.
+func Parse(data []byte, format Format) (*Config, error) {
+	var c Config
+	var err error
+	switch format {
+	case YAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&c); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case TOML:
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), &c)
+		if err == nil {
+			if undecoded := md.Undecoded(); len(undecoded) > 0 {
+				err = fmt.Errorf("unknown field %s", undecoded[0])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("config: unknown format %v", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Marshal encodes the configuration in format.
+func (c *Config) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case YAML:
+		return yaml.Marshal(c)
+	case TOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("config: unknown format %v", format)
+}
+
+// NewRing creates a ring configured by opts with the configuration's hash
+// function, seed and nodes. The configuration's hash function and seed take
+// precedence over opts.
+func (c *Config) NewRing(opts ...rendezvous.Option) (*rendezvous.Ring, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	encoded := *c
+	encoded.Nodes = make([]Node, len(c.Nodes))
+	for i, n := range c.Nodes {
+		if n.Weight == nil {
+			weight := 1.0
+			n.Weight = &weight
+		}
+		encoded.Nodes[i] = n
+	}
+	data, err := json.Marshal(&encoded)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	ring := rendezvous.New(opts...)
+	if err := ring.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return ring, nil
+}
+
+// FromRing returns the configuration of ring. Rings using an unnamed hash
+// function, see rendezvous.WithHasher, have a configuration without a hasher
+// name.
+func FromRing(ring *rendezvous.Ring) (*Config, error) {
+	data, err := ring.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &c, nil
+}
+
+// Load creates a ring configured by opts from the configuration file at
+// path, whose format is chosen by FormatOf.
+func Load(path string, opts ...rendezvous.Option) (*rendezvous.Ring, error) {
+	c, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewRing(opts...)
+}
+
+// ReadFile decodes the configuration file at path, whose format is chosen
+// by FormatOf.
+func ReadFile(path string) (*Config, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, format)
+}
+
+// Save writes the configuration of ring to the file at path, whose format is
+// chosen by FormatOf.
+func Save(path string, ring *rendezvous.Ring) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+	c, err := FromRing(ring)
+	if err != nil {
+		return err
+	}
+	data, err := c.Marshal(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// validate reports nodes without a name, duplicated or with an invalid
+// weight.
+func (c *Config) validate() error {
+	names := make(map[string]struct{}, len(c.Nodes))
+	for _, n := range c.Nodes {
+		if n.Name == "" {
+			return errors.New("config: node without a name")
+		}
+		if _, dup := names[n.Name]; dup {
+			return fmt.Errorf("config: duplicate node %q", n.Name)
+		}
+		if n.Weight != nil {
+			if err := rendezvous.CheckWeight(*n.Weight); err != nil {
+				return fmt.Errorf("config: %w of node %q", err, n.Name)
+			}
+		}
+		names[n.Name] = struct{}{}
+	}
+	return nil
+}
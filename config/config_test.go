@@ -0,0 +1,136 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+const yamlConfig = `
+hasher: xxhash
+seed: 42
+nodes:
+  - name: a
+    weight: 2
+    tags:
+      zone: z1
+  - name: b
+    drained: true
+  - name: c
+`
+
+const tomlConfig = `
+hasher = "xxhash"
+seed = 42
+
+[[nodes]]
+name = "a"
+weight = 2.0
+tags = { zone = "z1" }
+
+[[nodes]]
+name = "b"
+drained = true
+
+[[nodes]]
+name = "c"
+`
+
+func TestParse(t *testing.T) {
+	expected := rendezvous.New(rendezvous.WithSeed(42), rendezvous.WithNodes("a", "b", "c"))
+	expected.AddWithTags("a", 2, map[string]string{"zone": "z1"})
+	expected.Drain("b")
+
+	for _, test := range []struct {
+		format Format
+		data   string
+	}{
+		{YAML, yamlConfig},
+		{TOML, tomlConfig},
+	} {
+		t.Run(test.format.String(), func(t *testing.T) {
+			c, err := Parse([]byte(test.data), test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ring, err := c.NewRing()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ring.List(), expected.List()) || ring.Weight("a") != 2 || ring.Weight("c") != 1 ||
+				ring.Tags("a")["zone"] != "z1" || !ring.Drained("b") {
+				t.Errorf("Expected the configured nodes")
+			}
+			for _, key := range []string{"k1", "k2", "k3", "k4"} {
+				if node := ring.Lookup(key); node != expected.Lookup(key) {
+					t.Errorf("Expected %s to map to %s but got %s", key, expected.Lookup(key), node)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"UnknownField", YAML, "nodes:\n  - name: a\n    wieght: 2\n"},
+		{"UnknownTOMLField", TOML, "[[nodes]]\nname = \"a\"\nwieght = 2.0\n"},
+		{"Unnamed", YAML, "nodes:\n  - weight: 2\n"},
+		{"Duplicate", TOML, "[[nodes]]\nname = \"a\"\n[[nodes]]\nname = \"a\"\n"},
+		{"NegativeWeight", YAML, "nodes:\n  - name: a\n    weight: -1\n"},
+		{"UnknownFormat", 0, "nodes: []"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse([]byte(test.data), test.format); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+
+	c, err := Parse([]byte("hasher: unknown\n"), YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewRing(); err == nil || !strings.Contains(err.Error(), "unknown hasher") {
+		t.Errorf("Expected an unknown hasher error but got %v", err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithSeed(7), rendezvous.WithNodes("a", "b"))
+	ring.AddWithTags("c", 3, map[string]string{"zone": "z2"})
+	ring.AddWithWeight("z", 0)
+	ring.Drain("a")
+
+	for _, ext := range []string{".yaml", ".yml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ring"+ext)
+			if err := Save(path, ring); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := rendezvous.Compare(ring, loaded); !diff.Empty() {
+				t.Errorf("Expected the saved ring but got %+v", diff)
+			}
+			if !loaded.Drained("a") || loaded.Tags("c")["zone"] != "z2" || loaded.Hash("k") != ring.Hash("k") {
+				t.Errorf("Expected saved states, tags and hashing")
+			}
+			if !loaded.Contains("z") || loaded.Weight("z") != 0 {
+				t.Errorf("Expected the zero weight of z to be saved but got %v", loaded.Weight("z"))
+			}
+		})
+	}
+
+	if err := Save(filepath.Join(t.TempDir(), "ring.ini"), ring); err == nil {
+		t.Errorf("Expected an unknown format error")
+	}
+}
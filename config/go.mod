@@ -0,0 +1,17 @@
+module github.com/mosuka/rendezvous/config
+
+go 1.23
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/mosuka/rendezvous v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
+
+replace github.com/mosuka/rendezvous => ../
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mosuka/rendezvous"
+)
+
+// A Reload describes a configuration file applied to a ring by a Watcher.
+type Reload struct {
+	// Diff lists the nodes added, removed and reweighted.
+	Diff rendezvous.RingDiff
+	// Drained and Activated list the nodes whose state changed.
+	Drained, Activated []string
+	// Moved is the share of sample keys that changed their owner, see
+	// WithSampleKeys.
+	Moved float64
+}
+
+// A Watcher applies a configuration file to a live ring whenever it changes,
+// so operators can change weights by editing the file.
+type Watcher struct {
+	ring *rendezvous.Ring
+	path string
+	opts options
+	keys []string
+}
+
+// An Option configures a Watcher.
+type Option func(*options)
+
+type options struct {
+	onError    func(error)
+	onReload   func(Reload)
+	debounce   time.Duration
+	sampleKeys int
+}
+
+// WithErrorHandler reports files that fail to read, decode or apply to fn.
+// The ring keeps its membership until the file is fixed.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// WithReloadHandler reports every applied file to fn, for example to log
+// the movement it caused.
+func WithReloadHandler(fn func(Reload)) Option {
+	return func(o *options) {
+		o.onReload = fn
+	}
+}
+
+// WithDebounce sets how long a file must be left unchanged before it is
+// applied, so the partial writes of an editor are not; the default is 100
+// milliseconds.
+func WithDebounce(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.debounce = d
+		}
+	}
+}
+
+// WithSampleKeys sets the number of synthetic keys Reload.Moved is measured
+// with; the default is 10000, and zero disables the measurement.
+func WithSampleKeys(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.sampleKeys = n
+		}
+	}
+}
+
+// NewWatcher returns a Watcher applying the configuration file at path,
+// whose format is chosen by FormatOf, to ring.
+func NewWatcher(ring *rendezvous.Ring, path string, opts ...Option) *Watcher {
+	w := &Watcher{
+		ring: ring,
+		path: filepath.Clean(path),
+		opts: options{debounce: 100 * time.Millisecond, sampleKeys: 10000},
+	}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	w.keys = make([]string, w.opts.sampleKeys)
+	for i := range w.keys {
+		w.keys[i] = "key-" + strconv.Itoa(i)
+	}
+	return w
+}
+
+// Run applies the file and then every change to it until ctx is done,
+// returning ctx's error, or an error if the file cannot be watched. Run
+// watches the file's directory, so files replaced by a rename, as many
+// editors save them, are followed.
+func (w *Watcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	w.reload()
+
+	timer := time.NewTimer(w.opts.debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return ctx.Err()
+			}
+			if filepath.Clean(e.Name) == w.path && e.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				timer.Reset(w.opts.debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return ctx.Err()
+			}
+			w.report(fmt.Errorf("config: %w", err))
+		case <-timer.C:
+			w.reload()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Reload applies the file to the ring now. Membership, weights and tags are
+// applied in a single write, see rendezvous.Ring.Reconcile, followed by a
+// write for every node whose drained state changed. Nodes left in the ring
+// keep their loads and payloads. A file naming a different hash function or
+// seed than the ring's is rejected, since rehashing a live ring would move
+// most keys.
+func (w *Watcher) Reload() (Reload, error) {
+	c, err := ReadFile(w.path)
+	if err != nil {
+		return Reload{}, err
+	}
+	current, err := FromRing(w.ring)
+	if err != nil {
+		return Reload{}, err
+	}
+	if c.Hasher != "" && c.Hasher != current.Hasher {
+		return Reload{}, fmt.Errorf("config: cannot change hasher %q of a live ring to %q", current.Hasher, c.Hasher)
+	}
+	if (c.Seed == nil) != (current.Seed == nil) || (c.Seed != nil && *c.Seed != *current.Seed) {
+		return Reload{}, fmt.Errorf("config: cannot change the seed of a live ring")
+	}
+
+	before := w.ring.Clone()
+	members := make([]rendezvous.Member, len(c.Nodes))
+	for i, n := range c.Nodes {
+		members[i] = rendezvous.Member{Name: n.Name, Tags: n.Tags}
+		if n.Weight != nil {
+			members[i].Weight, members[i].HasWeight = *n.Weight, true
+		}
+	}
+	if err := w.ring.Reconcile(members); err != nil {
+		return Reload{}, fmt.Errorf("config: %w", err)
+	}
+
+	var reload Reload
+	for _, n := range c.Nodes {
+		switch {
+		case n.Drained && !w.ring.Drained(n.Name):
+			w.ring.Drain(n.Name)
+			reload.Drained = append(reload.Drained, n.Name)
+		case !n.Drained && w.ring.Drained(n.Name):
+			w.ring.Activate(n.Name)
+			reload.Activated = append(reload.Activated, n.Name)
+		}
+	}
+
+	reload.Diff = rendezvous.Compare(before, w.ring)
+	if len(w.keys) > 0 {
+		reload.Moved = float64(len(rendezvous.Diff(before, w.ring, w.keys))) / float64(len(w.keys))
+	}
+	return reload, nil
+}
+
+func (w *Watcher) reload() {
+	reload, err := w.Reload()
+	if err != nil {
+		w.report(err)
+		return
+	}
+	if w.opts.onReload != nil {
+		w.opts.onReload(reload)
+	}
+}
+
+func (w *Watcher) report(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.yaml")
+	write(t, path, "nodes:\n  - name: a\n  - name: b\n  - name: c\n")
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b"))
+	ring.Drain("b")
+
+	reload, err := NewWatcher(ring, path).Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reload.Diff.Added, []string{"c"}) || !reflect.DeepEqual(reload.Activated, []string{"b"}) {
+		t.Errorf("Expected c to be added and b to be activated but got %+v", reload)
+	}
+	if reload.Moved < 0.5 || reload.Moved > 0.8 {
+		t.Errorf("Expected about two thirds of the keys to move but got %v", reload.Moved)
+	}
+
+	write(t, path, "nodes:\n  - name: a\n  - name: b\n  - name: c\n    weight: 0\n")
+	if _, err := NewWatcher(ring, path).Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if ring.Weight("a") != 1 || !ring.Contains("c") || ring.Weight("c") != 0 {
+		t.Errorf("Expected weights 1 and 0 but got %v and %v", ring.Weight("a"), ring.Weight("c"))
+	}
+
+	write(t, path, "seed: 1\nnodes:\n  - name: a\n")
+	if _, err := NewWatcher(ring, path).Reload(); err == nil || !strings.Contains(err.Error(), "seed") {
+		t.Errorf("Expected a seed change to be rejected but got %v", err)
+	}
+	if ring.Len() != 3 {
+		t.Errorf("Expected a rejected file to leave the ring unchanged")
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.toml")
+	write(t, path, "[[nodes]]\nname = \"a\"\n")
+	ring := rendezvous.New()
+
+	reloads := make(chan Reload, 10)
+	errs := make(chan error, 10)
+	w := NewWatcher(ring, path,
+		WithDebounce(10*time.Millisecond),
+		WithSampleKeys(100),
+		WithReloadHandler(func(r Reload) { reloads <- r }),
+		WithErrorHandler(func(err error) { errs <- err }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	next := func() Reload {
+		t.Helper()
+		select {
+		case r := <-reloads:
+			return r
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected a reload")
+		}
+		return Reload{}
+	}
+	if r := next(); !reflect.DeepEqual(r.Diff.Added, []string{"a"}) {
+		t.Errorf("Expected a to be added but got %+v", r)
+	}
+
+	write(t, path, "[[nodes]]\nname = \"a\"\nweight = 3.0\n")
+	if r := next(); !reflect.DeepEqual(r.Diff.Reweighted, []rendezvous.WeightChange{{Name: "a", From: 1, To: 3}}) {
+		t.Errorf("Expected a to be reweighted but got %+v", r)
+	}
+
+	renamed := path + ".tmp"
+	write(t, renamed, "[[nodes]]\nname = \"b\"\n")
+	if err := os.Rename(renamed, path); err != nil {
+		t.Fatal(err)
+	}
+	if r := next(); !reflect.DeepEqual(r.Diff.Removed, []string{"a"}) || r.Moved != 1 {
+		t.Errorf("Expected a to be replaced by b but got %+v", r)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected %v but got %v", context.Canceled, err)
+	}
+}
+
+func write(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package consul keeps a rendezvous.Ring in sync with the healthy instances
+// of a service in the Consul catalog.
+package consul
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/mosuka/rendezvous"
+)
+
+// WeightMeta is the default service metadata key holding an instance's
+// weight, see WithWeightMeta.
+const WeightMeta = "weight"
+
+// A Health queries the health of service instances; *api.Health implements
+// it.
+type Health interface {
+	Service(service, tag string, passingOnly bool, q *api.QueryOptions) ([]*api.ServiceEntry, *api.QueryMeta, error)
+}
+
+// A Watcher applies the healthy instances of a service to a ring.
+type Watcher struct {
+	health  Health
+	ring    *rendezvous.Ring
+	service string
+	opts    options
+}
+
+// An Option configures a Watcher.
+type Option func(*options)
+
+type options struct {
+	tag        string
+	weightMeta string
+	waitTime   time.Duration
+	backoff    time.Duration
+	onError    func(error)
+}
+
+// WithTag only considers instances with tag.
+func WithTag(tag string) Option {
+	return func(o *options) {
+		o.tag = tag
+	}
+}
+
+// WithWeightMeta reads instance weights from the service metadata key
+// instead of WeightMeta. Instances without the key are weighted by their
+// Consul passing weight; a weight of 0 in the key makes the instance a
+// member that is never selected.
+func WithWeightMeta(key string) Option {
+	return func(o *options) {
+		o.weightMeta = key
+	}
+}
+
+// WithWaitTime sets the maximum duration of a blocking query; the default is
+// five minutes.
+func WithWaitTime(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.waitTime = d
+		}
+	}
+}
+
+// WithBackoff sets the delay before retrying a failed query; the default is
+// one second.
+func WithBackoff(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.backoff = d
+		}
+	}
+}
+
+// WithErrorHandler reports failed queries and malformed weights to fn. The
+// ring keeps its membership when a query fails.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// New returns a Watcher applying the instances of service to ring.
+func New(health Health, ring *rendezvous.Ring, service string, opts ...Option) *Watcher {
+	w := &Watcher{
+		health:  health,
+		ring:    ring,
+		service: service,
+		opts: options{
+			weightMeta: WeightMeta,
+			waitTime:   5 * time.Minute,
+			backoff:    time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	return w
+}
+
+// Run reconciles the ring with the service's passing instances and then
+// follows changes with blocking queries until ctx is done, returning ctx's
+// error. Instances are named address:port and tagged with their service
+// metadata; instances failing a health check are removed.
+func (w *Watcher) Run(ctx context.Context) error {
+	var index uint64
+	for {
+		q := (&api.QueryOptions{WaitIndex: index, WaitTime: w.opts.waitTime}).WithContext(ctx)
+		entries, meta, err := w.health.Service(w.service, w.opts.tag, true, q)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			w.report(err)
+			index = 0
+
+			timer := time.NewTimer(w.opts.backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+			continue
+		}
+
+		if err := w.ring.Reconcile(w.members(entries)); err != nil {
+			w.report(fmt.Errorf("consul: %w", err))
+		}
+
+		// Consul's index may go backwards, for example after a restore, which
+		// requires starting over.
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+	}
+}
+
+func (w *Watcher) members(entries []*api.ServiceEntry) []rendezvous.Member {
+	members := make([]rendezvous.Member, 0, len(entries))
+	for _, e := range entries {
+		address := e.Service.Address
+		if address == "" && e.Node != nil {
+			address = e.Node.Address
+		}
+		name := net.JoinHostPort(address, strconv.Itoa(e.Service.Port))
+
+		m := rendezvous.Member{
+			Name:   name,
+			Weight: float64(e.Service.Weights.Passing),
+			Tags:   make(map[string]string, len(e.Service.Meta)),
+		}
+		for k, v := range e.Service.Meta {
+			if k != w.opts.weightMeta {
+				m.Tags[k] = v
+				continue
+			}
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err == nil {
+				err = rendezvous.CheckWeight(parsed)
+			}
+			if err != nil {
+				w.report(fmt.Errorf("consul: malformed weight %q of instance %s", v, name))
+				continue
+			}
+			m.Weight, m.HasWeight = parsed, true
+		}
+
+		members = append(members, m)
+	}
+	return members
+}
+
+func (w *Watcher) report(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+	}
+}
@@ -0,0 +1,129 @@
+package consul
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/mosuka/rendezvous"
+)
+
+// fakeHealth answers blocking queries with a queue of responses.
+type fakeHealth struct {
+	t         *testing.T
+	responses chan fakeResponse
+	queries   chan *api.QueryOptions
+}
+
+type fakeResponse struct {
+	entries []*api.ServiceEntry
+	index   uint64
+	err     error
+}
+
+func (h *fakeHealth) Service(service, tag string, passingOnly bool, q *api.QueryOptions) ([]*api.ServiceEntry, *api.QueryMeta, error) {
+	if service != "cache" || tag != "primary" || !passingOnly {
+		h.t.Errorf("Unexpected query %q %q %v", service, tag, passingOnly)
+	}
+	h.queries <- q
+	select {
+	case resp := <-h.responses:
+		return resp.entries, &api.QueryMeta{LastIndex: resp.index}, resp.err
+	case <-q.Context().Done():
+		return nil, nil, q.Context().Err()
+	}
+}
+
+func entry(address string, port int, passing int, meta map[string]string) *api.ServiceEntry {
+	return &api.ServiceEntry{
+		Node: &api.Node{Address: "10.0.0.100"},
+		Service: &api.AgentService{
+			Address: address,
+			Port:    port,
+			Meta:    meta,
+			Weights: api.AgentWeights{Passing: passing, Warning: 1},
+		},
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	health := &fakeHealth{t: t, responses: make(chan fakeResponse), queries: make(chan *api.QueryOptions)}
+	ring := rendezvous.New()
+	var errs []error
+	var errsMutex sync.Mutex
+	w := New(health, ring, "cache", WithTag("primary"), WithBackoff(time.Millisecond), WithErrorHandler(func(err error) {
+		errsMutex.Lock()
+		defer errsMutex.Unlock()
+		errs = append(errs, err)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	respond := func(wantIndex uint64, resp fakeResponse) {
+		t.Helper()
+		if q := <-health.queries; q.WaitIndex != wantIndex {
+			t.Errorf("Expected wait index %d but got %d", wantIndex, q.WaitIndex)
+		}
+		health.responses <- resp
+	}
+
+	respond(0, fakeResponse{err: errors.New("unavailable")})
+	respond(0, fakeResponse{index: 10, entries: []*api.ServiceEntry{
+		entry("10.0.0.1", 6379, 1, map[string]string{"weight": "3", "zone": "z1"}),
+		entry("", 6379, 2, nil),
+		entry("10.0.0.2", 6379, 1, map[string]string{"weight": "x"}),
+	}})
+	respond(10, fakeResponse{index: 11, entries: []*api.ServiceEntry{
+		entry("10.0.0.1", 6379, 1, map[string]string{"weight": "3", "zone": "z1"}),
+	}})
+
+	// The next query waits on the latest index, so the previous responses
+	// have been applied.
+	<-health.queries
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"10.0.0.1:6379"}) {
+		t.Errorf("Expected the failing instances to be removed but got %v", names)
+	}
+	if ring.Weight("10.0.0.1:6379") != 3 {
+		t.Errorf("Expected the weight from the metadata but got %v", ring.Weight("10.0.0.1:6379"))
+	}
+	if tags := ring.Tags("10.0.0.1:6379"); !reflect.DeepEqual(tags, map[string]string{"zone": "z1"}) {
+		t.Errorf("Expected the metadata as tags but got %v", tags)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+	errsMutex.Lock()
+	defer errsMutex.Unlock()
+	if len(errs) != 2 {
+		t.Errorf("Expected the failed query and the malformed weight to be reported but got %v", errs)
+	}
+}
+
+func TestWatcher_members(t *testing.T) {
+	w := New(nil, rendezvous.New(), "cache")
+	members := w.members([]*api.ServiceEntry{
+		entry("", 80, 2, nil),
+		entry("::1", 80, 1, map[string]string{"weight": "0.5"}),
+		entry("10.0.0.3", 80, 1, map[string]string{"weight": "NaN"}),
+		entry("10.0.0.4", 80, 1, map[string]string{"weight": "-Inf"}),
+		entry("10.0.0.5", 80, 1, map[string]string{"weight": "0"}),
+	})
+	expected := []rendezvous.Member{
+		{Name: "10.0.0.100:80", Weight: 2, Tags: map[string]string{}},
+		{Name: "[::1]:80", Weight: 0.5, HasWeight: true, Tags: map[string]string{}},
+		{Name: "10.0.0.3:80", Weight: 1, Tags: map[string]string{}},
+		{Name: "10.0.0.4:80", Weight: 1, Tags: map[string]string{}},
+		{Name: "10.0.0.5:80", Weight: 0, HasWeight: true, Tags: map[string]string{}},
+	}
+	if !reflect.DeepEqual(members, expected) {
+		t.Errorf("Expected %v but got %v", expected, members)
+	}
+}
@@ -0,0 +1,181 @@
+// Package dns populates a rendezvous.Ring from DNS, for environments where
+// service discovery is DNS only.
+package dns
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// A Resolver resolves DNS names; *net.Resolver implements it.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// A Populator periodically resolves a DNS name and reconciles a ring with
+// the result, see rendezvous.Ring.Reconcile.
+type Populator struct {
+	ring    *rendezvous.Ring
+	resolve func(ctx context.Context) ([]rendezvous.Member, error)
+	opts    options
+}
+
+// An Option configures a Populator.
+type Option func(*options)
+
+type options struct {
+	resolver Resolver
+	interval time.Duration
+	jitter   time.Duration
+	port     int
+	onError  func(error)
+}
+
+// WithResolver resolves names with resolver instead of net.DefaultResolver.
+func WithResolver(resolver Resolver) Option {
+	return func(o *options) {
+		o.resolver = resolver
+	}
+}
+
+// WithInterval sets the time between refreshes; the default is 30 seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithJitter delays every refresh by a random duration of up to jitter, so
+// that many clients do not resolve in lockstep.
+func WithJitter(jitter time.Duration) Option {
+	return func(o *options) {
+		if jitter >= 0 {
+			o.jitter = jitter
+		}
+	}
+}
+
+// WithPort names the nodes found by NewHost host:port instead of host.
+func WithPort(port int) Option {
+	return func(o *options) {
+		o.port = port
+	}
+}
+
+// WithErrorHandler reports failed refreshes of Run to fn. The ring keeps its
+// membership when a refresh fails.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+func newPopulator(ring *rendezvous.Ring, opts []Option) *Populator {
+	p := &Populator{
+		ring: ring,
+		opts: options{
+			resolver: net.DefaultResolver,
+			interval: 30 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(&p.opts)
+	}
+	return p
+}
+
+// NewSRV returns a Populator resolving the SRV records of _service._proto.name.
+// Nodes are named target:port and weighted by their SRV weight, where a
+// weight of 0 maps to the default weight: RFC 2782 still selects such
+// targets, so they are not members that are never selected. Only the
+// targets of the lowest priority are used, as they are preferred by RFC 2782.
+func NewSRV(ring *rendezvous.Ring, service, proto, name string, opts ...Option) *Populator {
+	p := newPopulator(ring, opts)
+	p.resolve = func(ctx context.Context) ([]rendezvous.Member, error) {
+		_, records, err := p.opts.resolver.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].Priority < records[j].Priority
+		})
+
+		members := make([]rendezvous.Member, 0, len(records))
+		for _, srv := range records {
+			if srv.Priority != records[0].Priority {
+				break
+			}
+			target := strings.TrimSuffix(srv.Target, ".")
+			members = append(members, rendezvous.Member{
+				Name:   net.JoinHostPort(target, strconv.Itoa(int(srv.Port))),
+				Weight: float64(srv.Weight),
+			})
+		}
+		return members, nil
+	}
+	return p
+}
+
+// NewHost returns a Populator resolving the A and AAAA records of host, with
+// a node of the default weight per address.
+func NewHost(ring *rendezvous.Ring, host string, opts ...Option) *Populator {
+	p := newPopulator(ring, opts)
+	p.resolve = func(ctx context.Context) ([]rendezvous.Member, error) {
+		addrs, err := p.opts.resolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		members := make([]rendezvous.Member, 0, len(addrs))
+		for _, addr := range addrs {
+			if p.opts.port != 0 {
+				addr = net.JoinHostPort(addr, strconv.Itoa(p.opts.port))
+			}
+			members = append(members, rendezvous.Member{Name: addr})
+		}
+		return members, nil
+	}
+	return p
+}
+
+// Refresh resolves the name once and reconciles the ring with the result.
+// On error, the ring is left unchanged.
+func (p *Populator) Refresh(ctx context.Context) error {
+	members, err := p.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	return p.ring.Reconcile(members)
+}
+
+// Run refreshes the ring immediately and then periodically until ctx is
+// done, and returns ctx's error.
+func (p *Populator) Run(ctx context.Context) error {
+	for {
+		if err := p.Refresh(ctx); err != nil && ctx.Err() == nil && p.opts.onError != nil {
+			p.opts.onError(err)
+		}
+
+		delay := p.opts.interval
+		if p.opts.jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(p.opts.jitter) + 1))
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+)
+
+type fakeResolver struct {
+	mutex sync.Mutex
+	srv   []*net.SRV
+	hosts []string
+	err   error
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if service != "cache" || proto != "tcp" || name != "example.com" {
+		return "", nil, errors.New("no such host")
+	}
+	return "_cache._tcp.example.com.", append([]*net.SRV(nil), r.srv...), r.err
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.hosts, r.err
+}
+
+func TestNewSRV(t *testing.T) {
+	resolver := &fakeResolver{srv: []*net.SRV{
+		{Target: "backup.example.com.", Port: 6379, Priority: 20, Weight: 10},
+		{Target: "a.example.com.", Port: 6379, Priority: 10, Weight: 3},
+		{Target: "b.example.com.", Port: 6380, Priority: 10, Weight: 0},
+	}}
+	ring := rendezvous.New()
+	p := NewSRV(ring, "cache", "tcp", "example.com", WithResolver(resolver))
+
+	if err := p.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"a.example.com:6379", "b.example.com:6380"}) {
+		t.Errorf("Expected the lowest priority targets but got %v", names)
+	}
+	if ring.Weight("a.example.com:6379") != 3 || ring.Weight("b.example.com:6380") != 1 {
+		t.Errorf("Expected the SRV weights to be applied")
+	}
+
+	resolver.err = errors.New("timeout")
+	if err := p.Refresh(context.Background()); err == nil || ring.Len() != 2 {
+		t.Errorf("Expected a failed refresh to keep the ring")
+	}
+}
+
+func TestNewHost(t *testing.T) {
+	resolver := &fakeResolver{hosts: []string{"10.0.0.1", "::1"}}
+	ring := rendezvous.New()
+	p := NewHost(ring, "cache.example.com", WithResolver(resolver), WithPort(6379))
+
+	if err := p.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"10.0.0.1:6379", "[::1]:6379"}) {
+		t.Errorf("Expected the addresses but got %v", names)
+	}
+}
+
+func TestPopulator_Run(t *testing.T) {
+	resolver := &fakeResolver{hosts: []string{"10.0.0.1"}, err: errors.New("timeout")}
+	ring := rendezvous.New()
+	errs := make(chan error, 1)
+	p := NewHost(ring, "cache.example.com",
+		WithResolver(resolver),
+		WithInterval(time.Millisecond),
+		WithJitter(time.Millisecond),
+		WithErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+
+	<-errs
+	resolver.mutex.Lock()
+	resolver.err = nil
+	resolver.mutex.Unlock()
+	for deadline := time.Now().Add(5 * time.Second); !ring.Contains("10.0.0.1") && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if !ring.Contains("10.0.0.1") {
+		t.Errorf("Expected a later refresh to populate the ring")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+}
@@ -1,4 +1,14 @@
 // Package rendezvous implements rendezvous hashing (a.k.a. highest random
 // weight hashing). See http://en.wikipedia.org/wiki/Rendezvous_hashing for
 // more information.
+//
+// Nodes are ranked for a key by descending score. Nodes with equal scores
+// are ranked by ascending hash of their names and then by name, so rankings
+// are reproducible across runs and machines, as replicated deciders require.
+//
+// Unlike hash rings, rendezvous rings need no virtual nodes: every node's
+// expected share of keys is its fraction of the total weight, whatever the
+// number of nodes, and scoring a node as several derived points would not
+// change it. Small rings deviate from their shares only by the sampling noise
+// of the keys, which AnalyzeBalance measures.
 package rendezvous
@@ -0,0 +1,25 @@
+package rendezvous
+
+// Drain takes the named node out of rotation: it stays a member, and keeps
+// its rank in LookupAll, but Lookup, LookupTopN and the other selecting
+// lookups skip it. Keys it owned move to their next ranked node, exactly as if
+// it had been removed. Drain reports whether the node exists.
+func (r *Ring) Drain(name string) bool {
+	return r.update(name, func(n *Node) {
+		n.drained = true
+	})
+}
+
+// Activate puts a drained node back into rotation. It reports whether the
+// node exists.
+func (r *Ring) Activate(name string) bool {
+	return r.update(name, func(n *Node) {
+		n.drained = false
+	})
+}
+
+// Drained reports whether the named node is drained.
+func (r *Ring) Drained(name string) bool {
+	n, ok := r.get(name)
+	return ok && n.drained
+}
@@ -0,0 +1,81 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Drain(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+	all := rv.LookupAll("foo")
+
+	if !rv.Drain(all[0]) {
+		t.Fatalf("Expected Drain to find %s", all[0])
+	}
+	if rv.Drain("z") {
+		t.Errorf("Expected Drain to report a non-existent node")
+	}
+
+	t.Run("SkippedByLookups", func(t *testing.T) {
+		if node := rv.Lookup("foo"); node != all[1] {
+			t.Errorf("Expected %s but got %s", all[1], node)
+		}
+		if names := rv.LookupTopN("foo", 2); !reflect.DeepEqual(names, all[1:3]) {
+			t.Errorf("Expected %v but got %v", all[1:3], names)
+		}
+		if names := rv.AppendTopN(nil, "foo", 2); !reflect.DeepEqual(names, all[1:3]) {
+			t.Errorf("Expected %v but got %v", all[1:3], names)
+		}
+		if names := rv.LookupBatch([]string{"foo"}); names[0] != all[1] {
+			t.Errorf("Expected %s but got %s", all[1], names[0])
+		}
+		if node := rv.LookupNode("foo"); node.Name() != all[1] {
+			t.Errorf("Expected %s but got %s", all[1], node.Name())
+		}
+	})
+
+	t.Run("KeptByMembershipAndLookupAll", func(t *testing.T) {
+		if !rv.Contains(all[0]) || rv.Len() != 5 {
+			t.Errorf("Expected %s to stay a member", all[0])
+		}
+		if names := rv.LookupAll("foo"); !reflect.DeepEqual(names, all) {
+			t.Errorf("Expected %v but got %v", all, names)
+		}
+		scoredNodes := rv.LookupAllScored("foo")
+		if !scoredNodes[0].Drained() || scoredNodes[1].Drained() {
+			t.Errorf("Expected only %s to be flagged as drained", all[0])
+		}
+		if !rv.Drained(all[0]) || rv.Drained(all[1]) {
+			t.Errorf("Expected only %s to be drained", all[0])
+		}
+	})
+
+	t.Run("MovesOnlyDrainedKeys", func(t *testing.T) {
+		other := New(WithNodes("a", "b", "c", "d", "e"))
+		other.Remove(all[0])
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := other.Lookup(key), rv.Lookup(key); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+	})
+
+	t.Run("Activate", func(t *testing.T) {
+		if !rv.Activate(all[0]) {
+			t.Fatalf("Expected Activate to find %s", all[0])
+		}
+		if node := rv.Lookup("foo"); node != all[0] {
+			t.Errorf("Expected %s but got %s", all[0], node)
+		}
+	})
+
+	t.Run("AllDrained", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		rv.Drain("a")
+		if node := rv.Lookup("foo"); node != "" {
+			t.Errorf("Expected empty string but got %s", node)
+		}
+	})
+}
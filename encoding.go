@@ -0,0 +1,152 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+type ringJSON struct {
+	Hasher  string     `json:"hasher,omitempty"`
+	Seed    *uint64    `json:"seed,omitempty"`
+	Version uint64     `json:"version,omitempty"`
+	Nodes   []nodeJSON `json:"nodes"`
+}
+
+type nodeJSON struct {
+	Name     string            `json:"name"`
+	Weight   float64           `json:"weight"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Drained  bool              `json:"drained,omitempty"`
+	Capacity int64             `json:"capacity,omitempty"`
+}
+
+// MarshalJSON encodes the ring's nodes, weights, tags and states together
+// with the name of its hash function and its seed. Rings using an unnamed
+// hash function, see WithHasher, encode without a hasher name.
+func (r *Ring) MarshalJSON() ([]byte, error) {
+	v := ringJSON{
+		Hasher:  r.opts.hasherName,
+		Version: r.Version(),
+		Nodes:   make([]nodeJSON, 0),
+	}
+	if r.opts.seeded {
+		seed := r.opts.seed
+		v.Seed = &seed
+	}
+	for _, n := range r.load() {
+		v.Nodes = append(v.Nodes, nodeJSON{
+			Name:     n.name,
+			Weight:   n.weight,
+			Tags:     n.tags,
+			Drained:  n.drained,
+			Capacity: n.capacity,
+		})
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON replaces the ring's membership and hashing with the encoded
+// ring. A named hash function must be registered, see RegisterHasher; without
+// a name, the ring keeps its current hash function. Options that cannot be
+// encoded, such as the score function, are kept as well. UnmarshalJSON may be
+// called on a zero Ring.
+//
+// Lookups read the hash function without locking, so it and the seed may
+// only change while the ring has no nodes and is not in use; decoding a ring
+// hashed differently into a ring with nodes returns ErrHashingChanged.
+func (r *Ring) UnmarshalJSON(data []byte) error {
+	var v ringJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	opts, err := r.decodedOptions(v.Hasher, v.Seed)
+	if err != nil {
+		return err
+	}
+	hasher := opts.seededHasher()
+
+	nodes := make([]*Node, 0, len(v.Nodes))
+	for _, nj := range v.Nodes {
+		nodes = append(nodes, &Node{
+			name:     nj.Name,
+			hash:     hasher(nj.Name),
+			weight:   nj.Weight,
+			tags:     nj.Tags,
+			drained:  nj.Drained,
+			capacity: nj.Capacity,
+			load:     new(atomic.Int64),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+	if err := checkSorted(nodes); err != nil {
+		return err
+	}
+
+	if err := r.setHashing(opts); err != nil {
+		return err
+	}
+	r.store(nodes)
+	r.restoreVersion(v.Version)
+
+	return nil
+}
+
+// decodedOptions returns the ring's options with the encoded hash function
+// and seed applied, starting from the defaults on a zero Ring.
+func (r *Ring) decodedOptions(hasherName string, seed *uint64) (options, error) {
+	opts := r.opts
+	if opts.hasher == nil {
+		opts = *defaultOptions()
+	}
+	if hasherName != "" {
+		hasher, ok := registeredHasher(hasherName)
+		if !ok {
+			return opts, fmt.Errorf("rendezvous: unknown hasher %q", hasherName)
+		}
+		opts.hasher = hasher
+		opts.hasherName = hasherName
+	}
+	opts.seed, opts.seeded = 0, seed != nil
+	if seed != nil {
+		opts.seed = *seed
+	}
+	return opts, nil
+}
+
+// setHashing makes decoded options the ring's options. Options hashing like
+// the current ones are not written, so that decoding into a ring in use does
+// not race with its lookups. The caller must hold the mutex.
+func (r *Ring) setHashing(opts options) error {
+	current := r.opts
+	if current.hasher != nil && current.hasherName == opts.hasherName &&
+		current.seeded == opts.seeded && current.seed == opts.seed {
+		return nil
+	}
+	if len(r.load()) > 0 {
+		return ErrHashingChanged
+	}
+	r.opts = opts
+	r.hasher = opts.seededHasher()
+	return nil
+}
+
+// checkSorted reports decoded nodes that are out of order or duplicated.
+func checkSorted(nodes []*Node) error {
+	for i := 1; i < len(nodes); i++ {
+		switch {
+		case nodes[i].name == nodes[i-1].name:
+			return fmt.Errorf("rendezvous: duplicate node %q", nodes[i].name)
+		case nodes[i].name < nodes[i-1].name:
+			return fmt.Errorf("rendezvous: node %q out of order", nodes[i].name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRing_MarshalJSON(t *testing.T) {
+	rv := New(WithSeed(42), WithNodes("b", "a"))
+	rv.AddWithTags("c", 2.5, map[string]string{"zone": "z1"})
+	rv.Drain("b")
+	rv.SetCapacity("a", 10)
+
+	data, err := json.Marshal(rv)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	expected := `{"hasher":"xxhash","seed":42,"version":4,"nodes":[` +
+		`{"name":"a","weight":1,"capacity":10},` +
+		`{"name":"b","weight":1,"drained":true},` +
+		`{"name":"c","weight":2.5,"tags":{"zone":"z1"}}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s but got %s", expected, data)
+	}
+
+	data, _ = json.Marshal(New(WithHasher(otherHasher)))
+	if expected := `{"nodes":[]}`; string(data) != expected {
+		t.Errorf("Expected %s but got %s", expected, data)
+	}
+}
+
+func TestRing_UnmarshalJSON(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		rv := New(WithSeed(42), WithNodes("a", "b", "d"))
+		rv.AddWithTags("c", 2.5, map[string]string{"zone": "z1"})
+		rv.Drain("b")
+
+		data, _ := json.Marshal(rv)
+		var decoded Ring
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := rv.Lookup(key), decoded.Lookup(key); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+		if zone := decoded.Tags("c")["zone"]; zone != "z1" || !decoded.Drained("b") {
+			t.Errorf("Expected tags and states to be decoded")
+		}
+
+		decoded.Add("e")
+		if !decoded.Contains("e") {
+			t.Errorf("Expected decoded ring to be usable")
+		}
+	})
+
+	t.Run("ReplacesMembership", func(t *testing.T) {
+		rv := New(WithHasher(otherHasher), WithNodes("x", "y"))
+		if err := json.Unmarshal([]byte(`{"nodes":[{"name":"b","weight":1},{"name":"a","weight":2}]}`), rv); err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+			t.Errorf("Expected [a b] but got %v", names)
+		}
+		if n, _ := rv.get("a"); n.hash != otherHasher("a") {
+			t.Errorf("Expected an unnamed hasher to be kept")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		for _, data := range []string{
+			`{"hasher":"nope","nodes":[]}`,
+			`{"nodes":[{"name":"b"},{"name":"b"}]}`,
+			`{"nodes":`,
+		} {
+			if err := json.Unmarshal([]byte(data), rv); err == nil {
+				t.Errorf("Expected an error for %s", data)
+			}
+		}
+		if names := rv.List(); !reflect.DeepEqual(names, []string{"a"}) {
+			t.Errorf("Expected a failed decode to keep the ring but got %v", names)
+		}
+	})
+
+	t.Run("Hashing", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		data, _ := json.Marshal(New(WithSeed(42), WithNodes("b")))
+		if err := json.Unmarshal(data, rv); !errors.Is(err, ErrHashingChanged) {
+			t.Errorf("Expected ErrHashingChanged but got %v", err)
+		}
+		if names := rv.List(); !reflect.DeepEqual(names, []string{"a"}) {
+			t.Errorf("Expected a failed decode to keep the ring but got %v", names)
+		}
+
+		empty := New()
+		if err := json.Unmarshal(data, empty); err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		if n, _ := empty.get("b"); n.hash != empty.Hash("b") || empty.Hash("b") == rv.Hash("b") {
+			t.Errorf("Expected an empty ring to take the encoded seed")
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		data, _ := json.Marshal(New(WithNodes("b", "c")))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 1000; i++ {
+				rv.Lookup("k" + strconv.Itoa(i))
+			}
+		}()
+		for i := 0; i < 10; i++ {
+			if err := json.Unmarshal(data, rv); err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		}
+		<-done
+	})
+}
+
+func TestRegisterHasher(t *testing.T) {
+	RegisterHasher("test-other", otherHasher)
+
+	rv := New(WithNamedHasher("test-other"), WithNodes("a", "b"))
+	data, _ := json.Marshal(rv)
+	if !strings.Contains(string(data), `"hasher":"test-other"`) {
+		t.Errorf("Expected the hasher name to be encoded but got %s", data)
+	}
+
+	var decoded Ring
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if n, _ := decoded.get("a"); n.hash != otherHasher("a") {
+		t.Errorf("Expected the named hasher to be used")
+	}
+	if names := Hashers(); !slices.Contains(names, "test-other") || !slices.Contains(names, defaultHasherName) || !sort.StringsAreSorted(names) {
+		t.Errorf("Expected the sorted registered hashers but got %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a duplicate registration to panic")
+		}
+	}()
+	RegisterHasher("test-other", otherHasher)
+}
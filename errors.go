@@ -0,0 +1,26 @@
+package rendezvous
+
+import "errors"
+
+var (
+	// ErrEmptyRing is returned by lookups of a ring without nodes.
+	ErrEmptyRing = errors.New("rendezvous: empty ring")
+	// ErrNoActiveNodes is returned by lookups of a ring whose nodes are all
+	// drained, unhealthy or weighted zero.
+	ErrNoActiveNodes = errors.New("rendezvous: no active nodes")
+	// ErrEmptyName is returned for nodes without a name.
+	ErrEmptyName = errors.New("rendezvous: empty node name")
+	// ErrInvalidWeight is returned for weights that are negative, infinite or
+	// NaN, which would produce meaningless scores.
+	ErrInvalidWeight = errors.New("rendezvous: invalid weight")
+	// ErrNodeExists is returned when adding a node that is already a member.
+	ErrNodeExists = errors.New("rendezvous: node exists")
+	// ErrNodeNotFound is returned when changing a node that is not a member.
+	ErrNodeNotFound = errors.New("rendezvous: node not found")
+	// ErrInvalidLoad is returned for load samples that are negative,
+	// infinite or NaN.
+	ErrInvalidLoad = errors.New("rendezvous: invalid load")
+	// ErrHashingChanged is returned when decoding a ring with another hash
+	// function or seed into a ring that has nodes.
+	ErrHashingChanged = errors.New("rendezvous: cannot change the hashing of a non-empty ring")
+)
@@ -0,0 +1,187 @@
+// Package etcd keeps a rendezvous.Ring in sync with the nodes stored under
+// an etcd prefix.
+//
+// Every key under the prefix names a node by its remaining suffix, and its
+// value is the node's JSON encoded Value, for example
+//
+//	/services/cache/nodes/10.0.0.1:6379 => {"weight":2,"tags":{"zone":"z1"}}
+//
+// An empty value, or one without a weight, adds the node with the default
+// weight.
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Value is the JSON encoded value of a node's key.
+type Value struct {
+	// Weight is the node's weight; nil means the default weight, and zero a
+	// member that is never selected.
+	Weight *float64          `json:"weight,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+// A Client reads and watches etcd keys; *clientv3.Client implements it.
+type Client interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+// A Watcher applies the nodes under an etcd prefix to a ring.
+type Watcher struct {
+	client Client
+	ring   *rendezvous.Ring
+	prefix string
+	opts   options
+}
+
+// An Option configures a Watcher.
+type Option func(*options)
+
+type options struct {
+	onError func(error)
+	backoff time.Duration
+}
+
+// WithErrorHandler reports failed reads and watches, as well as malformed
+// values, to fn. Nodes with malformed values are left out of the ring.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// WithBackoff sets the delay before resyncing after a failure; the default
+// is one second.
+func WithBackoff(backoff time.Duration) Option {
+	return func(o *options) {
+		if backoff > 0 {
+			o.backoff = backoff
+		}
+	}
+}
+
+// New returns a Watcher applying the nodes under prefix to ring.
+func New(client Client, ring *rendezvous.Ring, prefix string, opts ...Option) *Watcher {
+	w := &Watcher{
+		client: client,
+		ring:   ring,
+		prefix: prefix,
+		opts:   options{backoff: time.Second},
+	}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	return w
+}
+
+// Run syncs the ring with the prefix and then applies every change until ctx
+// is done, returning ctx's error. Each etcd revision is applied to the ring
+// in a single write. When the watch fails, for example because its revision
+// was compacted, Run reads the prefix again and resumes from there.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		if err := w.sync(ctx); err != nil && ctx.Err() == nil {
+			w.report(err)
+		}
+
+		timer := time.NewTimer(w.opts.backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// sync reads the prefix, reconciles the ring with it and applies changes
+// until the watch fails.
+func (w *Watcher) sync(ctx context.Context) error {
+	resp, err := w.client.Get(ctx, w.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	members := make(map[string]rendezvous.Member, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		w.put(members, kv.Key, kv.Value)
+	}
+	w.apply(members)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watch := w.client.Watch(watchCtx, w.prefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+	for wresp := range watch {
+		if err := wresp.Err(); err != nil {
+			return err
+		}
+		if wresp.CompactRevision != 0 {
+			return fmt.Errorf("etcd: watch compacted at revision %d", wresp.CompactRevision)
+		}
+		for _, e := range wresp.Events {
+			switch e.Type {
+			case clientv3.EventTypePut:
+				w.put(members, e.Kv.Key, e.Kv.Value)
+			case clientv3.EventTypeDelete:
+				delete(members, strings.TrimPrefix(string(e.Kv.Key), w.prefix))
+			}
+		}
+		if len(wresp.Events) > 0 {
+			w.apply(members)
+		}
+	}
+	return ctx.Err()
+}
+
+func (w *Watcher) put(members map[string]rendezvous.Member, key, value []byte) {
+	name := strings.TrimPrefix(string(key), w.prefix)
+	if name == "" {
+		return
+	}
+
+	var v Value
+	if len(value) > 0 {
+		if err := json.Unmarshal(value, &v); err != nil {
+			delete(members, name)
+			w.report(fmt.Errorf("etcd: malformed value of node %q: %w", name, err))
+			return
+		}
+		if v.Weight != nil {
+			if err := rendezvous.CheckWeight(*v.Weight); err != nil {
+				delete(members, name)
+				w.report(fmt.Errorf("etcd: malformed value of node %q: %w", name, err))
+				return
+			}
+		}
+	}
+	m := rendezvous.Member{Name: name, Tags: v.Tags}
+	if v.Weight != nil {
+		m.Weight, m.HasWeight = *v.Weight, true
+	}
+	members[name] = m
+}
+
+func (w *Watcher) apply(members map[string]rendezvous.Member) {
+	list := make([]rendezvous.Member, 0, len(members))
+	for _, m := range members {
+		list = append(list, m)
+	}
+	if err := w.ring.Reconcile(list); err != nil {
+		w.report(fmt.Errorf("etcd: %w", err))
+	}
+}
+
+func (w *Watcher) report(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+	}
+}
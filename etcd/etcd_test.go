@@ -0,0 +1,166 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeClient serves a key space with a revision history from memory.
+type fakeClient struct {
+	mutex    sync.Mutex
+	kvs      map[string]string
+	revision int64
+	watches  chan chan clientv3.WatchResponse
+	gets     int
+	fail     error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		kvs:     make(map[string]string),
+		watches: make(chan chan clientv3.WatchResponse, 10),
+	}
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.gets++
+	if c.fail != nil {
+		err := c.fail
+		c.fail = nil
+		return nil, err
+	}
+	resp := &clientv3.GetResponse{Header: &etcdserverpb.ResponseHeader{Revision: c.revision}}
+	for k, v := range c.kvs {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	return resp, nil
+}
+
+func (c *fakeClient) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	if op := clientv3.OpGet(key, opts...); !op.IsOptsWithPrefix() || op.Rev() != c.revision+1 {
+		panic("unexpected watch options")
+	}
+	in, out := make(chan clientv3.WatchResponse), make(chan clientv3.WatchResponse)
+	go func() {
+		// Like clientv3, close the channel when ctx is done.
+		defer close(out)
+		for {
+			select {
+			case resp := <-in:
+				select {
+				case out <- resp:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	c.watches <- in
+	return out
+}
+
+// event applies puts (non-empty values) and deletes to the key space as a
+// single revision and returns the matching watch response.
+func (c *fakeClient) event(kvs map[string]string) clientv3.WatchResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.revision++
+	var resp clientv3.WatchResponse
+	for k, v := range kvs {
+		e := &clientv3.Event{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)}}
+		if v == "" {
+			e.Type = clientv3.EventTypeDelete
+			delete(c.kvs, k)
+		} else {
+			c.kvs[k] = v
+		}
+		resp.Events = append(resp.Events, e)
+	}
+	return resp
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatalf("Expected the condition to hold eventually")
+}
+
+func TestWatcher(t *testing.T) {
+	client := newFakeClient()
+	client.kvs["/nodes/a"] = `{"weight":2}`
+	client.kvs["/nodes/b"] = `{"tags":{"zone":"z1"}}`
+	client.kvs["/nodes/z"] = `{"weight":0}`
+	client.kvs["/other/c"] = `{}`
+	client.fail = errors.New("unavailable")
+
+	ring := rendezvous.New()
+	var errs []error
+	var errsMutex sync.Mutex
+	w := New(client, ring, "/nodes/", WithBackoff(time.Millisecond), WithErrorHandler(func(err error) {
+		errsMutex.Lock()
+		defer errsMutex.Unlock()
+		errs = append(errs, err)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	watch := <-client.watches
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"a", "b", "z"}) {
+		t.Errorf("Expected [a b z] but got %v", names)
+	}
+	if ring.Weight("a") != 2 || ring.Weight("b") != 1 || ring.Weight("z") != 0 || ring.Tags("b")["zone"] != "z1" {
+		t.Errorf("Expected the values to be applied")
+	}
+
+	// A revision is applied in a single write.
+	version := ring.Version()
+	watch <- client.event(map[string]string{"/nodes/a": "", "/nodes/c": `{"weight":3}`, "/nodes/d": `x`, "/nodes/f": `{"weight":-1}`})
+	eventually(t, func() bool { return ring.Contains("c") })
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"b", "c", "z"}) || ring.Version() != version+1 {
+		t.Errorf("Expected [b c z] in one write but got %v", names)
+	}
+
+	// A compacted watch resyncs from a fresh read.
+	client.mutex.Lock()
+	client.kvs["/nodes/e"] = `{}`
+	client.mutex.Unlock()
+	watch <- clientv3.WatchResponse{CompactRevision: 1}
+	<-client.watches
+	if !ring.Contains("e") {
+		t.Errorf("Expected e after a resync but got %v", ring.List())
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+	errsMutex.Lock()
+	defer errsMutex.Unlock()
+	if len(errs) != 6 {
+		t.Errorf("Expected the failed read, the malformed values and the compaction to be reported but got %v", errs)
+	}
+}
@@ -0,0 +1,56 @@
+package rendezvous
+
+import "slices"
+
+// LookupExcluding is like Lookup but skips the excluded nodes, for example to
+// retry a request on the next node after the owner failed it, without
+// changing the ring. Unless the owner is excluded, it returns the owner
+// Lookup returns; the other nodes are tried in ranking order. It returns ""
+// if no other node can serve.
+func (r *Ring) LookupExcluding(key string, exclude ...string) string {
+	keyHash := r.computeHash(key)
+	nodes := r.load()
+	if n, ok := r.routedOwner(nodes, keyHash); ok && n != nil && !excluded(n, exclude) {
+		return n.name
+	}
+
+	var buf [1]ScoredNode
+	scoredNodes := r.topNInto(buf[:0], nodes, keyHash, 1, func(n *Node) bool {
+		return active(n) && !excluded(n, exclude)
+	})
+	if len(scoredNodes) == 0 {
+		if fallback := r.opts.fallback; fallback != "" && !slices.Contains(exclude, fallback) {
+			return fallback
+		}
+		return ""
+	}
+	return scoredNodes[0].node.name
+}
+
+// excluded reports whether n is one of the named nodes. Exclusion lists are
+// short, so a linear scan beats building a set.
+func excluded(n *Node, names []string) bool {
+	for _, name := range names {
+		if n.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the node to try after the failed nodes, for retry loops:
+// starting with no failed nodes and adding every node that fails yields the
+// owner and then the rest of the lookup ranking without repetitions, ending
+// with "". It is LookupExcluding
+// under a name that reads well in such loops:
+//
+//	var failed []string
+//	for node := ring.Next(key); node != ""; node = ring.Next(key, failed...) {
+//		if err := send(node); err == nil {
+//			break
+//		}
+//		failed = append(failed, node)
+//	}
+func (r *Ring) Next(key string, failed ...string) string {
+	return r.LookupExcluding(key, failed...)
+}
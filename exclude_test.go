@@ -0,0 +1,63 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_LookupExcluding(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d"))
+	all := rv.LookupAll("foo")
+
+	if node := rv.LookupExcluding("foo"); node != all[0] {
+		t.Errorf("Expected %s but got %s", all[0], node)
+	}
+	if node := rv.LookupExcluding("foo", all[0], "z"); node != all[1] {
+		t.Errorf("Expected %s but got %s", all[1], node)
+	}
+	if node := rv.LookupExcluding("foo", all[1], all[0]); node != all[2] {
+		t.Errorf("Expected %s but got %s", all[2], node)
+	}
+	if node := rv.LookupExcluding("foo", all...); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+
+	rv.Drain(all[1])
+	if node := rv.LookupExcluding("foo", all[0]); node != all[2] {
+		t.Errorf("Expected drained nodes to be skipped but got %s", node)
+	}
+
+	rv.Pin("foo", all[3])
+	if node := rv.LookupExcluding("foo", all[3]); node != all[0] {
+		t.Errorf("Expected an excluded pin to fall back to the ranking but got %s", node)
+	}
+}
+
+func BenchmarkRing_LookupExcluding(b *testing.B) {
+	rv := New()
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.LookupExcluding("k"+strconv.Itoa(i), "n1", "n2")
+	}
+}
+
+func TestRing_Next(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+	rv.Drain("c")
+
+	var failed []string
+	for node := rv.Next("foo"); node != ""; node = rv.Next("foo", failed...) {
+		failed = append(failed, node)
+	}
+	if expected := rv.LookupTopN("foo", 5); !reflect.DeepEqual(failed, expected) {
+		t.Errorf("Expected %v but got %v", expected, failed)
+	}
+}
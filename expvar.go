@@ -0,0 +1,81 @@
+package rendezvous
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+// PublishExpvar publishes the ring's nodes, weights, states and version
+// under name, together with the number of lookups per chosen node, see
+// ObserveLookups. Lookups returning the fallback of WithFallback count as
+// misses. Like expvar.Publish, it panics if name is already in use.
+func (r *Ring) PublishExpvar(name string) {
+	lookups := &lookupCounter{ring: r}
+	r.ObserveLookups(lookups)
+	// The counts of removed nodes are dropped, so they do not pile up on
+	// rings with churning membership.
+	r.OnChange(func(e ChangeEvent) {
+		if e.Type == NodeRemoved {
+			lookups.counts.Delete(e.Name())
+		}
+	})
+
+	expvar.Publish(name, expvar.Func(func() any {
+		return r.expvar(lookups)
+	}))
+}
+
+type expvarNode struct {
+	Weight  float64 `json:"weight"`
+	Drained bool    `json:"drained,omitempty"`
+	Lookups uint64  `json:"lookups"`
+}
+
+func (r *Ring) expvar(lookups *lookupCounter) any {
+	version := r.Version()
+	nodes := r.load()
+
+	v := struct {
+		Version uint64                `json:"version"`
+		Nodes   map[string]expvarNode `json:"nodes"`
+		Misses  uint64                `json:"misses"`
+	}{
+		Version: version,
+		Nodes:   make(map[string]expvarNode, len(nodes)),
+		Misses:  lookups.count(""),
+	}
+	for _, n := range nodes {
+		v.Nodes[n.name] = expvarNode{
+			Weight:  n.weight,
+			Drained: n.drained,
+			Lookups: lookups.count(n.name),
+		}
+	}
+	return v
+}
+
+// A lookupCounter counts lookups per chosen node of ring, and misses under
+// "".
+type lookupCounter struct {
+	ring   *Ring
+	counts sync.Map // node name to *atomic.Uint64
+}
+
+func (c *lookupCounter) ObserveLookup(node string) {
+	if node != "" && node == c.ring.opts.fallback && !c.ring.Contains(node) {
+		node = ""
+	}
+	count, ok := c.counts.Load(node)
+	if !ok {
+		count, _ = c.counts.LoadOrStore(node, new(atomic.Uint64))
+	}
+	count.(*atomic.Uint64).Add(1)
+}
+
+func (c *lookupCounter) count(node string) uint64 {
+	if count, ok := c.counts.Load(node); ok {
+		return count.(*atomic.Uint64).Load()
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestRing_PublishExpvar(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rv.AddWithWeight("c", 2)
+	rv.Drain("b")
+	rv.PublishExpvar("rendezvous_test")
+
+	owner := rv.Lookup("key")
+	rv.Lookup("key")
+
+	var v struct {
+		Version uint64 `json:"version"`
+		Nodes   map[string]struct {
+			Weight  float64 `json:"weight"`
+			Drained bool    `json:"drained"`
+			Lookups uint64  `json:"lookups"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("rendezvous_test").String()), &v); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if v.Version != rv.Version() || len(v.Nodes) != 3 {
+		t.Errorf("Expected version %d and 3 nodes but got %+v", rv.Version(), v)
+	}
+	if n := v.Nodes["c"]; n.Weight != 2 {
+		t.Errorf("Expected weight 2 but got %v", n.Weight)
+	}
+	if n := v.Nodes["b"]; !n.Drained {
+		t.Errorf("Expected b to be drained")
+	}
+	if n := v.Nodes[owner]; n.Lookups != 2 {
+		t.Errorf("Expected 2 lookups but got %d", n.Lookups)
+	}
+}
+
+func TestRing_PublishExpvar_Prune(t *testing.T) {
+	rv := New(WithNodes("a"), WithFallback("default"))
+	rv.PublishExpvar("rendezvous_test_prune")
+
+	rv.Lookup("key")
+	rv.Remove("a")
+	rv.Lookup("key")
+	rv.Lookup("key")
+
+	var v struct {
+		Misses uint64 `json:"misses"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("rendezvous_test_prune").String()), &v); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if v.Misses != 2 {
+		t.Errorf("Expected 2 misses but got %d", v.Misses)
+	}
+
+	// Only the misses are left counted.
+	lookups := (*rv.observers.Load())[0].LookupObserver.(*lookupCounter)
+	var names []any
+	lookups.counts.Range(func(name, _ any) bool {
+		names = append(names, name)
+		return true
+	})
+	if len(names) != 1 || names[0] != "" {
+		t.Errorf("Expected only misses to be counted but got %v", names)
+	}
+}
@@ -0,0 +1,45 @@
+package rendezvous
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithFallback(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Default", nil},
+		{"LookupCache", []Option{WithLookupCache(16)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rv := New(append(test.opts, WithFallback("shard-0"))...)
+			if node := rv.Lookup("k"); node != "shard-0" {
+				t.Errorf("Expected the fallback for an empty ring but got %q", node)
+			}
+
+			rv.Add("a")
+			if node := rv.Lookup("k"); node != "a" {
+				t.Errorf("Expected a but got %q", node)
+			}
+
+			rv.Drain("a")
+			if node := rv.Lookup("k"); node != "shard-0" {
+				t.Errorf("Expected the fallback for a drained ring but got %q", node)
+			}
+			if node, err := rv.LookupErr("k"); node != "shard-0" || !errors.Is(err, ErrNoActiveNodes) {
+				t.Errorf("Expected the fallback with %v but got %q, %v", ErrNoActiveNodes, node, err)
+			}
+		})
+	}
+
+	rv := New(WithNodes("a", "b"), WithFallback("shard-0"))
+	var tried []string
+	for node := rv.Next("k"); node != ""; node = rv.Next("k", tried...) {
+		tried = append(tried, node)
+	}
+	if len(tried) != 3 || tried[2] != "shard-0" {
+		t.Errorf("Expected the fallback to be tried last but got %v", tried)
+	}
+}
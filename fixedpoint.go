@@ -0,0 +1,86 @@
+package rendezvous
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// fixedLogBits is the number of mantissa bits indexing fixedLog2.
+const fixedLogBits = 12
+
+var (
+	fixedLog2     [1<<fixedLogBits + 1]uint64
+	fixedLog2Once sync.Once
+)
+
+// initFixedLog2 fills fixedLog2 with log2(1 + i/4096) in Q32, computed bit
+// by bit by repeated squaring so that the table is the same on every
+// platform.
+func initFixedLog2() {
+	for i := range fixedLog2 {
+		x := uint64(1)<<62 + uint64(i)<<(62-fixedLogBits) // Q62 in [1, 2]
+		var log uint64
+		for b := 31; b >= 0; b-- {
+			hi, lo := bits.Mul64(x, x)
+			x = hi<<2 | lo>>62
+			if x >= 1<<63 {
+				x >>= 1
+				log |= 1 << b
+			}
+		}
+		if i == len(fixedLog2)-1 {
+			log = 1 << 32
+		}
+		fixedLog2[i] = log
+	}
+}
+
+// ln2Q56 is ln(2) in Q56.
+const ln2Q56 = 0xb17217f7d1cf79
+
+// fixedScore is the score function of FixedPointWeighting: the weight
+// divided by -ln(u) for the combined hash u scaled to (0, 1), as
+// computeScore, evaluated with integer arithmetic in Q56. The result is
+// converted to float64 exactly once, so it is the same on every platform.
+func fixedScore(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+	h := combineHashes(keyHash, nodeHash)
+	if h == 0 || !(nodeWeight > 0) {
+		return 0
+	}
+
+	// e is -ln(u) in Q56. Close to u = 1, where the highest scores are
+	// decided, it is the series v + v²/2 + v³/3 of v = 1 - u; elsewhere it
+	// is ln(2) times log2(u) interpolated from fixedLog2.
+	var e uint64
+	if v := -h; v < 1<<58 {
+		v2, _ := bits.Mul64(v, v)
+		v3, _ := bits.Mul64(v2, v)
+		e = (v + v2/2 + v3/3) >> 8
+	} else {
+		lz := bits.LeadingZeros64(h)
+		f := h << lz << 1
+		i, r := f>>(64-fixedLogBits), f<<fixedLogBits>>32
+		frac := fixedLog2[i] + (fixedLog2[i+1]-fixedLog2[i])*r>>32
+		log := uint64(lz+1)<<32 - frac // -log2(u) in Q32
+		hi, lo := bits.Mul64(log, ln2Q56)
+		e = hi<<32 | lo>>32
+	}
+	if e == 0 {
+		e = 1
+	}
+
+	// The weight is taken in Q16, and the quotient of the normalized weight
+	// and e is scaled back into a float64 exactly.
+	weight := uint64(math.MaxUint64)
+	if nodeWeight < 1<<47 {
+		weight = uint64(nodeWeight * (1 << 16))
+	}
+	if weight == 0 {
+		return 0
+	}
+	t, s := bits.LeadingZeros64(weight), bits.LeadingZeros64(e)
+	weight, e = weight<<t, e<<s
+	q, _ := bits.Div64(weight>>1, weight<<63, e)
+	return math.Ldexp(float64(q), s-t-23)
+}
@@ -0,0 +1,41 @@
+package rendezvous
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFixedScore(t *testing.T) {
+	fixedLog2Once.Do(initFixedLog2)
+
+	for i := uint64(0); i < 100000; i++ {
+		keyHash, nodeHash := mix64(i), mix64(^i)
+		weight := float64(1 + i%7)
+		expected := computeScore(keyHash, nodeHash, weight)
+		if score := fixedScore(keyHash, nodeHash, weight); math.Abs(score-expected) > expected*1e-5 {
+			t.Fatalf("Expected a score close to %g but got %g", expected, score)
+		}
+	}
+
+	if score := fixedScore(1, 2, 0); score != 0 {
+		t.Errorf("Expected a zero score for a zero weight but got %g", score)
+	}
+}
+
+func TestWithWeighting_FixedPoint(t *testing.T) {
+	names := make([]string, 100)
+	for i := range names {
+		names[i] = "n" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	fixed, plain := New(WithNodes(names...), WithWeighting(FixedPointWeighting)), New(WithNodes(names...))
+
+	differ := 0
+	for i := uint64(0); i < 100000; i++ {
+		if fixed.LookupHash(mix64(i)) != plain.LookupHash(mix64(i)) {
+			differ++
+		}
+	}
+	if differ > 10 {
+		t.Errorf("Expected placements to agree with the default weighting but %d differ", differ)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/mosuka/rendezvous
 
-go 1.17
+go 1.21
 
 require github.com/cespare/xxhash/v2 v2.1.2
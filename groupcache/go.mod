@@ -0,0 +1,16 @@
+module github.com/mosuka/rendezvous/groupcache
+
+go 1.21
+
+require (
+	github.com/golang/groupcache v0.0.0-20241129210726-2c02b8208cf8
+	github.com/golang/protobuf v1.5.4
+	github.com/mosuka/rendezvous v0.0.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+)
+
+replace github.com/mosuka/rendezvous => ../
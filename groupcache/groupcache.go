@@ -0,0 +1,198 @@
+// Package groupcache picks the groupcache peer owning a key with a
+// rendezvous.Ring instead of groupcache's consistent hash, so peers can be
+// weighted, drained and kept in sync by any of the ring's integrations.
+//
+// The ring's nodes are the peers' base URLs, such as "http://10.0.0.1:8080",
+// and replacing
+//
+//	pool := groupcache.NewHTTPPool("http://10.0.0.1:8080")
+//
+// with
+//
+//	pool := rdvgroupcache.NewHTTPPool(ring, "http://10.0.0.1:8080")
+//
+// serves and fetches values over the same HTTP protocol, so peers using
+// either pool interoperate.
+package groupcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	gc "github.com/golang/groupcache"
+	pb "github.com/golang/groupcache/groupcachepb"
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/rendezvous"
+)
+
+const defaultBasePath = "/_groupcache/"
+
+// A Pool is a groupcache.PeerPicker choosing peers with a ring, and an
+// http.Handler serving the values of this peer's groups to the others.
+type Pool struct {
+	ring *rendezvous.Ring
+	self string
+	opts options
+
+	mutex   sync.Mutex
+	getters map[string]*httpGetter
+}
+
+// An Option configures a Pool.
+type Option func(*options)
+
+type options struct {
+	basePath  string
+	transport func(context.Context) http.RoundTripper
+}
+
+// WithBasePath sets the HTTP path prefix of the pool; the default is
+// "/_groupcache/", as for groupcache.HTTPPool.
+func WithBasePath(path string) Option {
+	return func(o *options) {
+		if path != "" {
+			o.basePath = path
+		}
+	}
+}
+
+// WithTransport sets the transport fetching values from other peers; the
+// default is http.DefaultTransport.
+func WithTransport(transport func(context.Context) http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// NewHTTPPool is like New and also serves the pool on http.DefaultServeMux,
+// like groupcache.NewHTTPPool.
+func NewHTTPPool(ring *rendezvous.Ring, self string, opts ...Option) *Pool {
+	p := New(ring, self, opts...)
+	http.Handle(p.opts.basePath, p)
+	return p
+}
+
+// New returns a Pool for the peer self and registers it as groupcache's
+// peer picker, which groupcache allows only once per process. Keys owned by
+// self, or by no peer because the ring is empty, are loaded locally.
+func New(ring *rendezvous.Ring, self string, opts ...Option) *Pool {
+	p := newPool(ring, self, opts...)
+	gc.RegisterPeerPicker(func() gc.PeerPicker { return p })
+	return p
+}
+
+func newPool(ring *rendezvous.Ring, self string, opts ...Option) *Pool {
+	p := &Pool{
+		ring:    ring,
+		self:    self,
+		opts:    options{basePath: defaultBasePath},
+		getters: make(map[string]*httpGetter),
+	}
+	for _, opt := range opts {
+		opt(&p.opts)
+	}
+	return p
+}
+
+// PickPeer returns the getter of the peer owning key, and false if the key
+// is owned by self or the ring is empty.
+func (p *Pool) PickPeer(key string) (gc.ProtoGetter, bool) {
+	peer := p.ring.Lookup(key)
+	if peer == "" || peer == p.self {
+		return nil, false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	getter, ok := p.getters[peer]
+	if !ok {
+		getter = &httpGetter{transport: p.opts.transport, baseURL: peer + p.opts.basePath}
+		p.getters[peer] = getter
+	}
+	return getter, true
+}
+
+// ServeHTTP serves GET requests for basePath/group/key with the value of
+// the key in the named group.
+func (p *Pool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Getters query-escape the group and key, so they are unescaped the same
+	// way rather than taken from the decoded path, which would turn "+" in
+	// place of spaces into literal pluses.
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), p.opts.basePath)
+	escapedGroup, escapedKey, found := strings.Cut(path, "/")
+	if !ok || !found {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	groupName, err := url.QueryUnescape(escapedGroup)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	key, err := url.QueryUnescape(escapedKey)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	group := gc.GetGroup(groupName)
+	if group == nil {
+		http.Error(w, "no such group: "+groupName, http.StatusNotFound)
+		return
+	}
+
+	group.Stats.ServerRequests.Add(1)
+	var value []byte
+	if err := group.Get(r.Context(), key, gc.AllocatingByteSliceSink(&value)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body, err := proto.Marshal(&pb.GetResponse{Value: value})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(body)
+}
+
+// An httpGetter fetches values from a peer's Pool or groupcache.HTTPPool.
+type httpGetter struct {
+	transport func(context.Context) http.RoundTripper
+	baseURL   string
+}
+
+func (h *httpGetter) Get(ctx context.Context, in *pb.GetRequest, out *pb.GetResponse) error {
+	u := h.baseURL + url.QueryEscape(in.GetGroup()) + "/" + url.QueryEscape(in.GetKey())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport
+	if h.transport != nil {
+		transport = h.transport(ctx)
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("groupcache: peer returned %v", res.Status)
+	}
+	var body bytes.Buffer
+	if _, err := io.Copy(&body, res.Body); err != nil {
+		return fmt.Errorf("groupcache: reading response body: %w", err)
+	}
+	if err := proto.Unmarshal(body.Bytes(), out); err != nil {
+		return fmt.Errorf("groupcache: decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package groupcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gc "github.com/golang/groupcache"
+	pb "github.com/golang/groupcache/groupcachepb"
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/rendezvous"
+)
+
+var group = gc.NewGroup("test", 1<<20, gc.GetterFunc(func(ctx context.Context, key string, dest gc.Sink) error {
+	return dest.SetString("value-" + key)
+}))
+
+func TestPool_PickPeer(t *testing.T) {
+	ring := rendezvous.New()
+	p := newPool(ring, "http://self")
+
+	if _, ok := p.PickPeer("key"); ok {
+		t.Fatal("PickPeer picked a peer of an empty ring")
+	}
+
+	ring.AddAll([]string{"http://self", "http://other"})
+	var self, other int
+	for i := 0; i < 1000; i++ {
+		key := "key-" + string(rune('a'+i%26)) + strings.Repeat("x", i%7)
+		getter, ok := p.PickPeer(key)
+		switch owner := ring.Lookup(key); {
+		case owner == "http://self" && ok:
+			t.Fatalf("PickPeer(%q) picked a peer for a key owned by self", key)
+		case owner == "http://other" && !ok:
+			t.Fatalf("PickPeer(%q) picked no peer for a key owned by other", key)
+		case ok:
+			other++
+			if again, _ := p.PickPeer(key); again != getter {
+				t.Fatal("PickPeer did not reuse the peer's getter")
+			}
+		default:
+			self++
+		}
+	}
+	if self == 0 || other == 0 {
+		t.Fatalf("keys owned by self: %d, by other: %d", self, other)
+	}
+
+	ring.Drain("http://other")
+	for i := 0; i < 100; i++ {
+		if _, ok := p.PickPeer("key-" + string(rune('a'+i))); ok {
+			t.Fatal("PickPeer picked a drained peer")
+		}
+	}
+}
+
+func TestPool_Get(t *testing.T) {
+	server := httptest.NewServer(newPool(rendezvous.New(), ""))
+	defer server.Close()
+
+	ring := rendezvous.New()
+	ring.Add(server.URL)
+	p := newPool(ring, "http://self")
+
+	getter, ok := p.PickPeer("k")
+	if !ok {
+		t.Fatal("PickPeer picked no peer")
+	}
+	var res pb.GetResponse
+	group, key := "test", "a b/c"
+	if err := getter.Get(context.Background(), &pb.GetRequest{Group: &group, Key: &key}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(res.GetValue()), "value-a b/c"; got != want {
+		t.Fatalf("Get = %q, want %q", got, want)
+	}
+
+	missing := "missing"
+	if err := getter.Get(context.Background(), &pb.GetRequest{Group: &missing, Key: &key}, &res); err == nil {
+		t.Fatal("Get of a missing group succeeded")
+	}
+}
+
+func TestPool_ServeHTTP(t *testing.T) {
+	p := newPool(rendezvous.New(), "", WithBasePath("/cache/"))
+
+	for path, code := range map[string]int{
+		"/cache/test/x":    http.StatusOK,
+		"/cache/missing/x": http.StatusNotFound,
+		"/cache/test":      http.StatusBadRequest,
+		"/other/test/x":    http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, code)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_groupcache/remote/key" {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := proto.Marshal(&pb.GetResponse{Value: []byte("from-peer")})
+		if err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	ring := rendezvous.New()
+	ring.Add(server.URL)
+	New(ring, "http://self", WithTransport(func(context.Context) http.RoundTripper {
+		return http.DefaultTransport
+	}))
+
+	// Every key is owned by the server, so the group fetches from it
+	// instead of loading locally.
+	remote := gc.NewGroup("remote", 1<<20, gc.GetterFunc(func(ctx context.Context, key string, dest gc.Sink) error {
+		t.Errorf("loaded %q locally", key)
+		return dest.SetString("local")
+	}))
+	var value string
+	if err := remote.Get(context.Background(), "key", gc.StringSink(&value)); err != nil {
+		t.Fatal(err)
+	}
+	if value != "from-peer" {
+		t.Fatalf("Get = %q, want %q", value, "from-peer")
+	}
+	if got := remote.Stats.PeerLoads.Get(); got != 1 {
+		t.Fatalf("peer loads = %d, want 1", got)
+	}
+}
@@ -0,0 +1,85 @@
+package rendezvouspb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mosuka/rendezvous"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// A Client consults a remote ring served by a Server.
+type Client struct {
+	rpc RingServiceClient
+}
+
+// NewClient returns a Client using cc.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{rpc: NewRingServiceClient(cc)}
+}
+
+// Lookup returns the node owning key.
+func (c *Client) Lookup(ctx context.Context, key string) (string, error) {
+	resp, err := c.rpc.Lookup(ctx, &LookupRequest{Key: key})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetNode(), nil
+}
+
+// LookupTopN returns the n highest ranked nodes for key. It returns an error
+// for a negative n without calling the server.
+func (c *Client) LookupTopN(ctx context.Context, key string, n int) ([]string, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("rendezvouspb: negative n %d", n)
+	}
+	resp, err := c.rpc.LookupTopN(ctx, &LookupTopNRequest{Key: key, N: uint32(n)})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetNodes(), nil
+}
+
+// Ring returns a local copy of the remote ring, for lookups without a round
+// trip. The copy does not follow later changes, see Watch.
+func (c *Client) Ring(ctx context.Context, opts ...rendezvous.Option) (*rendezvous.Ring, error) {
+	resp, err := c.rpc.List(ctx, &ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	ring := rendezvous.New(opts...)
+	if err := ring.UnmarshalProto(data); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// Add adds the named node with weight or changes its weight.
+func (c *Client) Add(ctx context.Context, name string, weight float64) error {
+	_, err := c.rpc.Add(ctx, &AddRequest{Name: name, Weight: weight})
+	return err
+}
+
+// Remove removes the named node and reports whether it existed.
+func (c *Client) Remove(ctx context.Context, name string) (bool, error) {
+	resp, err := c.rpc.Remove(ctx, &RemoveRequest{Name: name})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.GetRemoved(), nil
+}
+
+// Watch streams every change to the remote ring until ctx is done.
+func (c *Client) Watch(ctx context.Context) (grpc.ServerStreamingClient[ChangeEvent], error) {
+	return c.rpc.Watch(ctx, &WatchRequest{})
+}
@@ -0,0 +1,7 @@
+// Package rendezvouspb serves a rendezvous.Ring over gRPC and provides a
+// client for it, so that processes in other languages and sidecars can
+// consult one authoritative ring. The service and messages are defined in
+// service.proto and ../../proto/rendezvous.proto.
+package rendezvouspb
+
+//go:generate protoc -I ../../proto -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative rendezvous.proto service.proto
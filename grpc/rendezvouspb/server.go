@@ -0,0 +1,115 @@
+package rendezvouspb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mosuka/rendezvous"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// A Server implements RingServiceServer on top of a ring.
+type Server struct {
+	UnimplementedRingServiceServer
+
+	ring *rendezvous.Ring
+}
+
+// NewServer returns a Server for ring. Register it with
+// RegisterRingServiceServer.
+func NewServer(ring *rendezvous.Ring) *Server {
+	return &Server{ring: ring}
+}
+
+// Lookup implements RingServiceServer.
+func (s *Server) Lookup(ctx context.Context, req *LookupRequest) (*LookupResponse, error) {
+	node := s.ring.LookupContext(ctx, req.GetKey())
+	if node == "" {
+		return nil, status.Error(codes.Unavailable, "no active nodes")
+	}
+	return &LookupResponse{Node: node}, nil
+}
+
+// LookupTopN implements RingServiceServer.
+func (s *Server) LookupTopN(ctx context.Context, req *LookupTopNRequest) (*LookupTopNResponse, error) {
+	n := int(req.GetN())
+	if n == 0 {
+		n = 1
+	}
+	return &LookupTopNResponse{Nodes: s.ring.LookupTopN(req.GetKey(), n)}, nil
+}
+
+// List implements RingServiceServer.
+func (s *Server) List(ctx context.Context, req *ListRequest) (*Ring, error) {
+	data, err := s.ring.MarshalProto()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ring := &Ring{}
+	if err := proto.Unmarshal(data, ring); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return ring, nil
+}
+
+// Add implements RingServiceServer.
+func (s *Server) Add(ctx context.Context, req *AddRequest) (*AddResponse, error) {
+	weight := req.GetWeight()
+	if weight == 0 {
+		weight = 1
+	}
+	// Adding an existing node changes its weight.
+	err := s.ring.AddWithWeightChecked(req.GetName(), weight)
+	switch {
+	case errors.Is(err, rendezvous.ErrNodeExists):
+		s.ring.AddWithWeight(req.GetName(), weight)
+	case errors.Is(err, rendezvous.ErrEmptyName), errors.Is(err, rendezvous.ErrInvalidWeight):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &AddResponse{}, nil
+}
+
+// Remove implements RingServiceServer. Removing a node that is not a member
+// fails with NotFound.
+func (s *Server) Remove(ctx context.Context, req *RemoveRequest) (*RemoveResponse, error) {
+	if _, ok := s.ring.TryRemove(req.GetName()); !ok {
+		return nil, status.Errorf(codes.NotFound, "%v: %q", rendezvous.ErrNodeNotFound, req.GetName())
+	}
+	return &RemoveResponse{Removed: true}, nil
+}
+
+// Watch implements RingServiceServer.
+func (s *Server) Watch(req *WatchRequest, stream grpc.ServerStreamingServer[ChangeEvent]) error {
+	for e := range s.ring.Watch(stream.Context()) {
+		if err := stream.Send(changeEvent(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func changeEvent(e rendezvous.ChangeEvent) *ChangeEvent {
+	return &ChangeEvent{
+		Type:    ChangeEvent_Type(e.Type),
+		Old:     node(e.Old),
+		New:     node(e.New),
+		Version: e.Version,
+	}
+}
+
+func node(n *rendezvous.Node) *Node {
+	if n == nil {
+		return nil
+	}
+	return &Node{
+		Name:    n.Name(),
+		Weight:  n.Weight(),
+		Tags:    n.Tags(),
+		Drained: n.Drained(),
+	}
+}
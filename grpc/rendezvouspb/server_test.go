@@ -0,0 +1,131 @@
+package rendezvouspb
+
+import (
+	"context"
+	"math"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func serve(t *testing.T, ring *rendezvous.Ring) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterRingServiceServer(srv, NewServer(ring))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return NewClient(cc)
+}
+
+func TestServer(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b"))
+	client := serve(t, ring)
+	ctx := context.Background()
+
+	if node, err := client.Lookup(ctx, "key"); err != nil || node != ring.Lookup("key") {
+		t.Errorf("Expected %s but got %s, %v", ring.Lookup("key"), node, err)
+	}
+	if nodes, err := client.LookupTopN(ctx, "key", 2); err != nil || !reflect.DeepEqual(nodes, ring.LookupTopN("key", 2)) {
+		t.Errorf("Expected %v but got %v, %v", ring.LookupTopN("key", 2), nodes, err)
+	}
+
+	if err := client.Add(ctx, "c", 2); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if err := client.Add(ctx, "", 0); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument but got %v", err)
+	}
+	for _, weight := range []float64{-1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if err := client.Add(ctx, "d", weight); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v but got %v", weight, err)
+		}
+	}
+	if ring.Contains("d") {
+		t.Errorf("Expected d not to be added")
+	}
+	if err := client.Add(ctx, "b", 3); err != nil || ring.Weight("b") != 3 {
+		t.Errorf("Expected b to be reweighted but got %v, %v", ring.Weight("b"), err)
+	}
+	if err := client.Add(ctx, "b", 1); err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
+	if _, err := client.LookupTopN(ctx, "key", -1); err == nil {
+		t.Errorf("Expected an error for a negative n")
+	}
+	if removed, err := client.Remove(ctx, "a"); err != nil || !removed {
+		t.Errorf("Expected a to be removed but got %v, %v", removed, err)
+	}
+	if removed, err := client.Remove(ctx, "a"); err != nil || removed {
+		t.Errorf("Expected a to be gone but got %v, %v", removed, err)
+	}
+	if _, err := client.rpc.Remove(ctx, &RemoveRequest{Name: "a"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound but got %v", err)
+	}
+
+	local, err := client.Ring(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if !reflect.DeepEqual(local.List(), []string{"b", "c"}) || local.Weight("c") != 2 || local.Version() != ring.Version() {
+		t.Errorf("Expected a copy of the ring but got %v", local.List())
+	}
+	for _, key := range []string{"k1", "k2", "k3"} {
+		if local.Lookup(key) != ring.Lookup(key) {
+			t.Errorf("Expected %s but got %s", ring.Lookup(key), local.Lookup(key))
+		}
+	}
+
+	ring.RemoveAll([]string{"b", "c"})
+	if _, err := client.Lookup(ctx, "key"); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable but got %v", err)
+	}
+}
+
+func TestServer_Watch(t *testing.T) {
+	ring := rendezvous.New()
+	client := serve(t, ring)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	// The server subscribes asynchronously, so keep changing the ring until
+	// the first change arrives.
+	go func() {
+		for i := 0; ctx.Err() == nil && i < 500; i++ {
+			ring.Add("n" + strconv.Itoa(i))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if expected := "n" + strconv.Itoa(int(e.GetVersion())-1); e.GetType() != ChangeEvent_TYPE_ADDED || e.GetNew().GetName() != expected {
+		t.Errorf("Expected %s to be added but got %v", expected, e)
+	}
+}
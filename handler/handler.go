@@ -0,0 +1,204 @@
+// Package handler exposes a rendezvous.Ring over HTTP, for example on an
+// admin port:
+//
+//	GET    /lookup?key=k           the node owning k
+//	GET    /topn?key=k&n=3         the n highest ranked nodes for k
+//	GET    /nodes                  the nodes, weights and states
+//	PUT    /nodes/{name}?weight=w  add a node or change its weight
+//	DELETE /nodes/{name}           remove a node
+//	GET    /stats                  the ring's size, total weight and version
+//
+// All responses are JSON. Mount the handler under a prefix with
+// http.StripPrefix.
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// An Option configures the handler returned by New.
+type Option func(*options)
+
+type options struct {
+	readOnly bool
+}
+
+// ReadOnly rejects requests modifying the ring with 405 Method Not Allowed.
+func ReadOnly() Option {
+	return func(o *options) {
+		o.readOnly = true
+	}
+}
+
+type handler struct {
+	ring *rendezvous.Ring
+	opts options
+	mux  *http.ServeMux
+}
+
+// Node is the JSON representation of a node.
+type Node struct {
+	Name    string  `json:"name"`
+	Weight  float64 `json:"weight"`
+	Drained bool    `json:"drained,omitempty"`
+}
+
+// Stats is the JSON representation of the ring's statistics. Active counts
+// the nodes lookups may select, see rendezvous.NodeInfo.Active.
+type Stats struct {
+	Nodes       int     `json:"nodes"`
+	Active      int     `json:"active"`
+	TotalWeight float64 `json:"total_weight"`
+	Version     uint64  `json:"version"`
+}
+
+// New returns an http.Handler serving ring.
+func New(ring *rendezvous.Ring, opts ...Option) http.Handler {
+	h := &handler{ring: ring, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+
+	h.mux.HandleFunc("/lookup", h.lookup)
+	h.mux.HandleFunc("/topn", h.topN)
+	h.mux.HandleFunc("/nodes", h.nodes)
+	h.mux.HandleFunc("/nodes/", h.node)
+	h.mux.HandleFunc("/stats", h.stats)
+	return h.mux
+}
+
+func (h *handler) lookup(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	key, ok := requireKey(w, r)
+	if !ok {
+		return
+	}
+	node := h.ring.Lookup(key)
+	if node == "" {
+		writeError(w, http.StatusServiceUnavailable, "no active nodes")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"key": key, "node": node})
+}
+
+func (h *handler) topN(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	key, ok := requireKey(w, r)
+	if !ok {
+		return
+	}
+	n := 1
+	if s := r.URL.Query().Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid n")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"key": key, "nodes": h.ring.LookupTopN(key, n)})
+}
+
+func (h *handler) nodes(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	infos := h.ring.Nodes()
+	nodes := make([]Node, 0, len(infos))
+	for _, info := range infos {
+		nodes = append(nodes, Node{Name: info.Name, Weight: info.Weight, Drained: info.State == rendezvous.NodeDrained})
+	}
+	writeJSON(w, http.StatusOK, nodes)
+}
+
+func (h *handler) node(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	if h.opts.readOnly {
+		writeError(w, http.StatusMethodNotAllowed, "ring is read-only")
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/nodes/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusNotFound, "invalid node name")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		weight := 1.0
+		if s := r.URL.Query().Get("weight"); s != "" {
+			var err error
+			if weight, err = strconv.ParseFloat(s, 64); err == nil {
+				err = rendezvous.CheckWeight(weight)
+			}
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid weight")
+				return
+			}
+		}
+		h.ring.AddWithWeight(name, weight)
+		writeJSON(w, http.StatusOK, Node{Name: name, Weight: weight, Drained: h.ring.Drained(name)})
+	case http.MethodDelete:
+		if _, ok := h.ring.TryRemove(name); !ok {
+			writeError(w, http.StatusNotFound, "unknown node")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *handler) stats(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	// Nodes reads a single snapshot, so the counts agree with each other.
+	stats := Stats{Version: h.ring.Version()}
+	for _, info := range h.ring.Nodes() {
+		stats.Nodes++
+		if info.Active() {
+			stats.Active++
+		}
+		stats.TotalWeight += info.Weight
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func requireKey(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "missing key")
+		return "", false
+	}
+	return key, true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
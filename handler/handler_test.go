@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestHandler(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b"))
+	ring.Drain("b")
+	h := New(ring)
+
+	for _, tt := range []struct {
+		method, target string
+		status         int
+		body           string
+	}{
+		{http.MethodGet, "/lookup?key=k", http.StatusOK, `{"key":"k","node":"a"}`},
+		{http.MethodGet, "/lookup", http.StatusBadRequest, `{"error":"missing key"}`},
+		{http.MethodGet, "/topn?key=k&n=2", http.StatusOK, `{"key":"k","nodes":["a"]}`},
+		{http.MethodGet, "/topn?key=k&n=x", http.StatusBadRequest, `{"error":"invalid n"}`},
+		{http.MethodPut, "/nodes/c?weight=2", http.StatusOK, `{"name":"c","weight":2}`},
+		{http.MethodPut, "/nodes/c?weight=-1", http.StatusBadRequest, `{"error":"invalid weight"}`},
+		{http.MethodPut, "/nodes/c?weight=NaN", http.StatusBadRequest, `{"error":"invalid weight"}`},
+		{http.MethodPut, "/nodes/c?weight=Inf", http.StatusBadRequest, `{"error":"invalid weight"}`},
+		{http.MethodPut, "/nodes/d?weight=0", http.StatusOK, `{"name":"d","weight":0}`},
+		{http.MethodGet, "/nodes", http.StatusOK, `[{"name":"a","weight":1},{"name":"b","weight":1,"drained":true},{"name":"c","weight":2},{"name":"d","weight":0}]`},
+		{http.MethodDelete, "/nodes/a", http.StatusNoContent, ``},
+		{http.MethodDelete, "/nodes/a", http.StatusNotFound, `{"error":"unknown node"}`},
+		{http.MethodGet, "/stats", http.StatusOK, `{"nodes":3,"active":1,"total_weight":3,"version":5}`},
+		{http.MethodPost, "/stats", http.StatusMethodNotAllowed, `{"error":"method not allowed"}`},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if body := strings.TrimSpace(rec.Body.String()); rec.Code != tt.status || body != tt.body {
+			t.Errorf("%s %s: Expected %d %s but got %d %s", tt.method, tt.target, tt.status, tt.body, rec.Code, body)
+		}
+	}
+}
+
+func TestHandler_ReadOnly(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a"))
+	h := New(ring, ReadOnly())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/nodes/a", nil))
+	if rec.Code != http.StatusMethodNotAllowed || !ring.Contains("a") {
+		t.Errorf("Expected the ring to be read-only but got %d", rec.Code)
+	}
+}
@@ -0,0 +1,33 @@
+// Package fnv provides an FNV-1a hasher for rendezvous rings. It was the
+// default hasher before xxHash; use it to keep the placements of rings built
+// with earlier releases.
+package fnv
+
+import "github.com/mosuka/rendezvous"
+
+// Name is the name Sum64 is registered under, see rendezvous.RegisterHasher.
+const Name = "fnv1a"
+
+func init() {
+	rendezvous.RegisterHasher(Name, Sum64)
+}
+
+// WithHasher returns an option hashing with Sum64.
+func WithHasher() rendezvous.Option {
+	return rendezvous.WithNamedHasher(Name)
+}
+
+// Sum64 returns the 64-bit FNV-1a hash of s. It matches hash/fnv.New64a but
+// keeps no state, so it is safe for concurrent use.
+func Sum64(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
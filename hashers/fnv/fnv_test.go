@@ -0,0 +1,43 @@
+package fnv
+
+import (
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestSum64(t *testing.T) {
+	for _, s := range []string{"", "a", "foo", "some_client_addr"} {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(s))
+		if expected, actual := h.Sum64(), Sum64(s); actual != expected {
+			t.Errorf("Expected %d but got %d", expected, actual)
+		}
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	// Placements of rings built before xxHash became the default.
+	rv := rendezvous.New(WithHasher(), rendezvous.WithNodes("a", "b", "c", "d", "e"))
+
+	names := rv.LookupAll("foo")
+	expected := []string{"d", "b", "c", "a", "e"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				Sum64(key)
+			}
+		})
+	}
+}
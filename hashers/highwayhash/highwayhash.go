@@ -0,0 +1,143 @@
+// Package highwayhash provides a keyed HighwayHash-64 hasher for rendezvous
+// rings.
+package highwayhash
+
+import (
+	"encoding/binary"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// KeySize is the size of a HighwayHash key in bytes.
+const KeySize = 32
+
+var (
+	init0 = [4]uint64{0xdbe6d5d5fe4cce2f, 0xa4093822299f31d0, 0x13198a2e03707344, 0x243f6a8885a308d3}
+	init1 = [4]uint64{0x3bd39e10cb0ef593, 0xc0acf169b5f18a8c, 0xbe5466cf34e90c6c, 0x452821e638d01377}
+)
+
+// WithHasher returns an option hashing with HighwayHash-64 under key.
+func WithHasher(key [KeySize]byte) rendezvous.Option {
+	return rendezvous.WithHasher(New(key))
+}
+
+// New returns a HighwayHash-64 hash function keyed with key.
+func New(key [KeySize]byte) func(string) uint64 {
+	var k [4]uint64
+	for i := range k {
+		k[i] = binary.LittleEndian.Uint64(key[8*i:])
+	}
+	return func(s string) uint64 {
+		return sum64(&k, s)
+	}
+}
+
+// Sum64 returns the HighwayHash-64 hash of s keyed with key.
+func Sum64(key [KeySize]byte, s string) uint64 {
+	return New(key)(s)
+}
+
+type state struct {
+	v0, v1, mul0, mul1 [4]uint64
+}
+
+func sum64(key *[4]uint64, s string) uint64 {
+	var st state
+	st.mul0 = init0
+	st.mul1 = init1
+	for i, k := range key {
+		st.v0[i] = init0[i] ^ k
+		st.v1[i] = init1[i] ^ rotate32(k)
+	}
+
+	var packet [4]uint64
+	for len(s) >= KeySize {
+		for i := range packet {
+			packet[i] = load64(s[8*i:])
+		}
+		st.update(&packet)
+		s = s[KeySize:]
+	}
+
+	if len(s) > 0 {
+		st.updateRemainder(s)
+	}
+
+	for i := 0; i < 4; i++ {
+		packet = [4]uint64{rotate32(st.v0[2]), rotate32(st.v0[3]), rotate32(st.v0[0]), rotate32(st.v0[1])}
+		st.update(&packet)
+	}
+
+	return st.v0[0] + st.v1[0] + st.mul0[0] + st.mul1[0]
+}
+
+func (st *state) update(packet *[4]uint64) {
+	for i, lane := range packet {
+		st.v1[i] += st.mul0[i] + lane
+		st.mul0[i] ^= uint64(uint32(st.v1[i])) * (st.v0[i] >> 32)
+		st.v0[i] += st.mul1[i]
+		st.mul1[i] ^= uint64(uint32(st.v0[i])) * (st.v1[i] >> 32)
+	}
+
+	st.v0[0], st.v0[1] = zipperMergeAndAdd(st.v1[0], st.v1[1], st.v0[0], st.v0[1])
+	st.v0[2], st.v0[3] = zipperMergeAndAdd(st.v1[2], st.v1[3], st.v0[2], st.v0[3])
+	st.v1[0], st.v1[1] = zipperMergeAndAdd(st.v0[0], st.v0[1], st.v1[0], st.v1[1])
+	st.v1[2], st.v1[3] = zipperMergeAndAdd(st.v0[2], st.v0[3], st.v1[2], st.v1[3])
+}
+
+// updateRemainder hashes the final 1 to 31 bytes of the input.
+func (st *state) updateRemainder(s string) {
+	size := uint64(len(s))
+	for i := range st.v0 {
+		st.v0[i] += size<<32 + size
+	}
+	for i, v := range st.v1 {
+		lo := uint32(v)
+		hi := uint32(v >> 32)
+		lo = lo<<size | lo>>(32-size)
+		hi = hi<<size | hi>>(32-size)
+		st.v1[i] = uint64(hi)<<32 | uint64(lo)
+	}
+
+	var block [KeySize]byte
+	mod4 := len(s) & 3
+	remainder := len(s) - mod4
+	copy(block[:], s[:remainder])
+	if len(s) >= 16 {
+		copy(block[28:], s[len(s)-4:])
+	} else if mod4 != 0 {
+		block[16] = s[remainder]
+		block[17] = s[remainder+mod4>>1]
+		block[18] = s[len(s)-1]
+	}
+
+	var packet [4]uint64
+	for i := range packet {
+		packet[i] = binary.LittleEndian.Uint64(block[8*i:])
+	}
+	st.update(&packet)
+}
+
+// zipperMergeAndAdd spreads the bytes of (v0, v1) so that the multiplications
+// in the next round mix them well, and adds the result to (a0, a1).
+func zipperMergeAndAdd(v0, v1, a0, a1 uint64) (uint64, uint64) {
+	a0 += ((v0&0xff000000|v1&0xff00000000)>>24 |
+		(v0&0xff0000000000|v1&0xff000000000000)>>16 |
+		v0&0xff0000 | (v0&0xff00)<<32 |
+		(v1&0xff00000000000000)>>8 | v0<<56)
+	a1 += ((v1&0xff000000|v0&0xff00000000)>>24 |
+		v1&0xff0000 | (v1&0xff0000000000)>>16 |
+		(v1&0xff00)<<24 | (v0&0xff000000000000)>>8 |
+		(v1&0xff)<<48 | v0&0xff00000000000000)
+	return a0, a1
+}
+
+func rotate32(x uint64) uint64 {
+	return x>>32 | x<<32
+}
+
+func load64(s string) uint64 {
+	_ = s[7]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
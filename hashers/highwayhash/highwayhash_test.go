@@ -0,0 +1,61 @@
+package highwayhash
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// Test vectors from the reference implementation, with key 00 01 .. 1f and
+// messages 00 01 .. (n-1).
+func TestSum64(t *testing.T) {
+	var key [KeySize]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	vectors := map[int]uint64{
+		0:  0x907a56de22c26e53,
+		1:  0x7eab43aac7cddd78,
+		3:  0x5c6befab8a463d80,
+		16: 0xcfab3489f97eb832,
+		31: 0x9fc7007ccf035a68,
+		32: 0xa0c964d9ecd580fc,
+		33: 0x2c90f73ca03181fc,
+		63: 0xab8eebe9bf2139a0,
+	}
+
+	for n, expected := range vectors {
+		msg := make([]byte, n)
+		for i := range msg {
+			msg[i] = byte(i)
+		}
+		if actual := Sum64(key, string(msg)); actual != expected {
+			t.Errorf("Expected %#x for length %d but got %#x", expected, n, actual)
+		}
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	key := [KeySize]byte{1, 2, 3}
+	rv := rendezvous.New(WithHasher(key), rendezvous.WithNodes("a", "b", "c", "d"))
+	for i := 0; i < 100; i++ {
+		k := "k" + strconv.Itoa(i)
+		if rv.LookupHash(Sum64(key, k)) != rv.Lookup(k) {
+			t.Errorf("Expected ring to hash with HighwayHash")
+		}
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	hasher := New([KeySize]byte{1, 2, 3})
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				hasher(key)
+			}
+		})
+	}
+}
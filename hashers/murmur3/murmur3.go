@@ -0,0 +1,113 @@
+// Package murmur3 provides a MurmurHash3 hasher for rendezvous rings.
+package murmur3
+
+import (
+	"math/bits"
+
+	"github.com/mosuka/rendezvous"
+)
+
+const (
+	c1 = 0x87c37b91114253d5
+	c2 = 0x4cf5ad432745937f
+)
+
+// Name is the name Sum64 is registered under, see rendezvous.RegisterHasher.
+const Name = "murmur3"
+
+func init() {
+	rendezvous.RegisterHasher(Name, Sum64)
+}
+
+// WithHasher returns an option hashing with Sum64.
+func WithHasher() rendezvous.Option {
+	return rendezvous.WithNamedHasher(Name)
+}
+
+// Sum64 returns the first 64 bits of the MurmurHash3 x64 128-bit hash of s,
+// with a zero seed.
+func Sum64(s string) uint64 {
+	h1, _ := Sum128(0, s)
+	return h1
+}
+
+// Sum128 returns the MurmurHash3 x64 128-bit hash of s. Both halves of the
+// state are initialized to seed, as in the reference implementation.
+func Sum128(seed uint32, s string) (uint64, uint64) {
+	h1, h2 := uint64(seed), uint64(seed)
+
+	n := len(s)
+	for len(s) >= 16 {
+		k1 := load64(s)
+		k2 := load64(s[8:])
+		s = s[16:]
+
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	var k1, k2 uint64
+	for i := len(s) - 1; i >= 0; i-- {
+		if i >= 8 {
+			k2 = k2<<8 | uint64(s[i])
+		} else {
+			k1 = k1<<8 | uint64(s[i])
+		}
+	}
+	if len(s) > 8 {
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+	}
+	if len(s) > 0 {
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	h1 ^= uint64(n)
+	h2 ^= uint64(n)
+
+	h1 += h2
+	h2 += h1
+
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+
+	h1 += h2
+	h2 += h1
+
+	return h1, h2
+}
+
+func load64(s string) uint64 {
+	_ = s[7]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,56 @@
+package murmur3
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestSum128(t *testing.T) {
+	vectors := []struct {
+		s                string
+		h1, h2           uint64
+		seeded1, seeded2 uint64
+	}{
+		{"", 0x0, 0x0, 0xf02aa77dfa1b8523, 0xd1016610da11cbb9},
+		{"a", 0x85555565f6597889, 0xe6b53a48510e895a, 0x28259ca4fdf626b0, 0x25ebca9125f82b15},
+		{"hello", 0xcbd8a7b341bd9b02, 0x5b1e906a48ae1d19, 0xc4b8b3c960af6f08, 0x2334b875b0efbc7a},
+		{"hello, world", 0x342fac623a5ebc8e, 0x4cdcbc079642414d, 0xb91864d797caa956, 0xd5d139a55afe6150},
+		{"The quick brown fox jumps over the lazy dog", 0xe34bbc7bbc071b6c, 0x7a433ca9c49a9347, 0x740dcf93fe0bd5d7, 0xc4546cf4ec705c8f},
+	}
+
+	for _, v := range vectors {
+		if h1, h2 := Sum128(0, v.s); h1 != v.h1 || h2 != v.h2 {
+			t.Errorf("Expected %#x %#x for %q but got %#x %#x", v.h1, v.h2, v.s, h1, h2)
+		}
+		if h1, h2 := Sum128(42, v.s); h1 != v.seeded1 || h2 != v.seeded2 {
+			t.Errorf("Expected %#x %#x for %q with seed but got %#x %#x", v.seeded1, v.seeded2, v.s, h1, h2)
+		}
+		if h := Sum64(v.s); h != v.h1 {
+			t.Errorf("Expected %#x for %q but got %#x", v.h1, v.s, h)
+		}
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	rv := rendezvous.New(WithHasher(), rendezvous.WithNodes("a", "b", "c", "d"))
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if rv.LookupHash(Sum64(key)) != rv.Lookup(key) {
+			t.Errorf("Expected ring to hash with MurmurHash3")
+		}
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				Sum64(key)
+			}
+		})
+	}
+}
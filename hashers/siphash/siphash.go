@@ -0,0 +1,83 @@
+// Package siphash provides a keyed SipHash-2-4 hasher for rendezvous rings.
+//
+// Unlike the unkeyed hashers, SipHash with a secret key prevents an attacker
+// who can choose keys from predicting or flooding their placement.
+package siphash
+
+import (
+	"math/bits"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// WithHasher returns an option hashing with SipHash-2-4 under the 128-bit
+// key (k0, k1).
+func WithHasher(k0, k1 uint64) rendezvous.Option {
+	return rendezvous.WithHasher(New(k0, k1))
+}
+
+// New returns a SipHash-2-4 hash function keyed with (k0, k1).
+func New(k0, k1 uint64) func(string) uint64 {
+	return func(s string) uint64 {
+		return Sum64(k0, k1, s)
+	}
+}
+
+// Sum64 returns the SipHash-2-4 hash of s keyed with (k0, k1), where k0 and
+// k1 are the little-endian halves of the 16-byte key.
+func Sum64(k0, k1 uint64, s string) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	b := uint64(len(s)) << 56
+	for len(s) >= 8 {
+		m := uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+			uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+		s = s[8:]
+
+		v3 ^= m
+		v0, v1, v2, v3 = round(v0, v1, v2, v3)
+		v0, v1, v2, v3 = round(v0, v1, v2, v3)
+		v0 ^= m
+	}
+
+	for i := len(s) - 1; i >= 0; i-- {
+		b |= uint64(s[i]) << (8 * uint(i))
+	}
+
+	v3 ^= b
+	v0, v1, v2, v3 = round(v0, v1, v2, v3)
+	v0, v1, v2, v3 = round(v0, v1, v2, v3)
+	v0 ^= b
+
+	v2 ^= 0xff
+	for i := 0; i < 4; i++ {
+		v0, v1, v2, v3 = round(v0, v1, v2, v3)
+	}
+
+	return v0 ^ v1 ^ v2 ^ v3
+}
+
+func round(v0, v1, v2, v3 uint64) (uint64, uint64, uint64, uint64) {
+	v0 += v1
+	v1 = bits.RotateLeft64(v1, 13)
+	v1 ^= v0
+	v0 = bits.RotateLeft64(v0, 32)
+
+	v2 += v3
+	v3 = bits.RotateLeft64(v3, 16)
+	v3 ^= v2
+
+	v0 += v3
+	v3 = bits.RotateLeft64(v3, 21)
+	v3 ^= v0
+
+	v2 += v1
+	v1 = bits.RotateLeft64(v1, 17)
+	v1 ^= v2
+	v2 = bits.RotateLeft64(v2, 32)
+
+	return v0, v1, v2, v3
+}
@@ -0,0 +1,64 @@
+package siphash
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// Test vectors from the SipHash paper, with key 00 01 .. 0f and messages
+// 00 01 .. (n-1).
+func TestSum64(t *testing.T) {
+	const (
+		k0 = 0x0706050403020100
+		k1 = 0x0f0e0d0c0b0a0908
+	)
+	vectors := map[int]uint64{
+		0:  0x726fdb47dd0e0e31,
+		1:  0x74f839c593dc67fd,
+		8:  0x93f5f5799a932462,
+		15: 0xa129ca6149be45e5,
+	}
+
+	for n, expected := range vectors {
+		msg := make([]byte, n)
+		for i := range msg {
+			msg[i] = byte(i)
+		}
+		if actual := Sum64(k0, k1, string(msg)); actual != expected {
+			t.Errorf("Expected %#x for length %d but got %#x", expected, n, actual)
+		}
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	rv1 := rendezvous.New(WithHasher(1, 2), rendezvous.WithNodes("a", "b", "c", "d"))
+	rv2 := rendezvous.New(WithHasher(3, 4), rendezvous.WithNodes("a", "b", "c", "d"))
+
+	differs := 0
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if rv1.LookupHash(Sum64(1, 2, key)) != rv1.Lookup(key) {
+			t.Errorf("Expected ring to hash with SipHash")
+		}
+		if rv1.Lookup(key) != rv2.Lookup(key) {
+			differs++
+		}
+	}
+	if differs == 0 {
+		t.Errorf("Expected different keys to produce different placements")
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				Sum64(1, 2, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package xxhash provides an xxHash64 hasher for rendezvous rings. It is the
+// default hasher of rendezvous.New.
+package xxhash
+
+import (
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// Name is the name Sum64 is registered under, see rendezvous.RegisterHasher.
+const Name = "xxhash"
+
+// WithHasher returns an option hashing with Sum64.
+func WithHasher() rendezvous.Option {
+	return rendezvous.WithNamedHasher(Name)
+}
+
+// Sum64 returns the xxHash64 hash of s with a zero seed.
+func Sum64(s string) uint64 {
+	return xxhash.Sum64String(s)
+}
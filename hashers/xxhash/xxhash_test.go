@@ -0,0 +1,34 @@
+package xxhash
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestWithHasher(t *testing.T) {
+	rv1 := rendezvous.New(WithHasher(), rendezvous.WithNodes("a", "b", "c", "d"))
+	rv2 := rendezvous.New(rendezvous.WithNodes("a", "b", "c", "d"))
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if rv1.LookupHash(Sum64(key)) != rv1.Lookup(key) {
+			t.Errorf("Expected ring to hash with xxHash")
+		}
+		if rv1.Lookup(key) != rv2.Lookup(key) {
+			t.Errorf("Expected xxHash to be the default hasher")
+		}
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				Sum64(key)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package rendezvous
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A HealthChecker probes whether a node can serve requests; a nil error
+// means healthy. The health subpackage provides TCP and HTTP probes.
+type HealthChecker interface {
+	Check(ctx context.Context, node *Node) error
+}
+
+// HealthCheckerFunc adapts a function to a HealthChecker.
+type HealthCheckerFunc func(ctx context.Context, node *Node) error
+
+func (f HealthCheckerFunc) Check(ctx context.Context, node *Node) error {
+	return f(ctx, node)
+}
+
+// SetHealthy marks the named node healthy or unhealthy. Like drained nodes,
+// unhealthy nodes stay members but are skipped by the selecting lookups.
+// SetHealthy reports whether the node exists.
+func (r *Ring) SetHealthy(name string, healthy bool) bool {
+	return r.update(name, func(n *Node) {
+		n.unhealthy = !healthy
+	})
+}
+
+// Healthy reports whether the named node exists and is healthy.
+func (r *Ring) Healthy(name string) bool {
+	n, ok := r.get(name)
+	return ok && !n.unhealthy
+}
+
+// defaultProbes is the number of concurrent probes of CheckHealth, see
+// WithHealthCheckConcurrency.
+const defaultProbes = 64
+
+// CheckHealth probes every node with checker, up to the limit of
+// WithHealthCheckConcurrency at a time, and applies the results under a
+// single write. Nodes removed while being probed are left out.
+func (r *Ring) CheckHealth(ctx context.Context, checker HealthChecker) {
+	nodes := r.load()
+	healthy := make([]bool, len(nodes))
+
+	workers := r.opts.probes
+	if workers <= 0 {
+		workers = defaultProbes
+	}
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(nodes) {
+					return
+				}
+				healthy[i] = checker.Check(ctx, nodes[i]) == nil
+			}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current := r.load()
+	var updated []*Node
+	for i, n := range nodes {
+		ix := sort.Search(len(current), cmp(current, n.name))
+		if ix == len(current) || current[ix].name != n.name || current[ix].unhealthy != healthy[i] {
+			continue
+		}
+		if updated == nil {
+			updated = make([]*Node, len(current))
+			copy(updated, current)
+		}
+		c := *current[ix]
+		c.unhealthy = !healthy[i]
+		updated[ix] = &c
+	}
+	if updated != nil {
+		r.store(updated)
+	}
+}
+
+// RunHealthChecks calls CheckHealth every interval until ctx is done, and
+// returns ctx's error.
+func (r *Ring) RunHealthChecks(ctx context.Context, checker HealthChecker, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.CheckHealth(ctx, checker)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
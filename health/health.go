@@ -0,0 +1,123 @@
+// Package health provides TCP and HTTP probes for rendezvous.Ring health
+// checks.
+//
+//	go ring.RunHealthChecks(ctx, health.TCP(), 5*time.Second)
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// AddressTag is the node tag probes connect to. Nodes without it are probed
+// at their name.
+const AddressTag = "address"
+
+// An Option configures a probe.
+type Option func(*options)
+
+type options struct {
+	timeout time.Duration
+	address func(*rendezvous.Node) string
+	client  *http.Client
+	scheme  string
+}
+
+// WithTimeout bounds every probe; the default is one second.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithAddress probes the address returned by fn instead of the node's
+// address tag or name.
+func WithAddress(fn func(node *rendezvous.Node) string) Option {
+	return func(o *options) {
+		o.address = fn
+	}
+}
+
+// WithClient sends HTTP probes with client instead of http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithHTTPS sends HTTP probes over TLS.
+func WithHTTPS() Option {
+	return func(o *options) {
+		o.scheme = "https"
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		timeout: time.Second,
+		address: address,
+		client:  http.DefaultClient,
+		scheme:  "http",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func address(node *rendezvous.Node) string {
+	if addr, ok := node.Tag(AddressTag); ok {
+		return addr
+	}
+	return node.Name()
+}
+
+// TCP returns a probe that considers a node healthy if a TCP connection to
+// its host:port address can be established.
+func TCP(opts ...Option) rendezvous.HealthChecker {
+	o := newOptions(opts)
+	var dialer net.Dialer
+	return rendezvous.HealthCheckerFunc(func(ctx context.Context, node *rendezvous.Node) error {
+		ctx, cancel := context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+
+		conn, err := dialer.DialContext(ctx, "tcp", o.address(node))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// HTTP returns a probe that considers a node healthy if a GET request for
+// path at its host:port address succeeds with a 2xx status.
+func HTTP(path string, opts ...Option) rendezvous.HealthChecker {
+	o := newOptions(opts)
+	return rendezvous.HealthCheckerFunc(func(ctx context.Context, node *rendezvous.Node) error {
+		ctx, cancel := context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+
+		url := o.scheme + "://" + o.address(node) + path
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("health: %s returned %s", url, resp.Status)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	defer ln.Close()
+
+	rv := rendezvous.New()
+	rv.Add(ln.Addr().String())
+	rv.AddWithTags("b", 1, map[string]string{AddressTag: closed.Addr().String()})
+	rv.CheckHealth(context.Background(), TCP())
+
+	if !rv.Healthy(ln.Addr().String()) {
+		t.Errorf("Expected the listening node to be healthy")
+	}
+	if rv.Healthy("b") {
+		t.Errorf("Expected the closed node to be unhealthy")
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	rv := rendezvous.New()
+	rv.AddWithTags("a", 1, map[string]string{AddressTag: healthy.Listener.Addr().String()})
+	rv.AddWithTags("b", 1, map[string]string{AddressTag: failing.Listener.Addr().String()})
+	rv.CheckHealth(context.Background(), HTTP("/healthz"))
+
+	if !rv.Healthy("a") || rv.Healthy("b") {
+		t.Errorf("Expected only a to be healthy")
+	}
+
+	rv.CheckHealth(context.Background(), HTTP("/missing"))
+	if rv.Healthy("a") {
+		t.Errorf("Expected a 404 to be unhealthy")
+	}
+}
@@ -0,0 +1,112 @@
+package rendezvous
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRing_SetHealthy(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	all := rv.LookupAll("foo")
+
+	if !rv.SetHealthy(all[0], false) || rv.SetHealthy("z", false) {
+		t.Fatalf("Expected SetHealthy to report existence")
+	}
+	if rv.Healthy(all[0]) || !rv.Healthy(all[1]) {
+		t.Errorf("Expected only %s to be unhealthy", all[0])
+	}
+	if node := rv.Lookup("foo"); node != all[1] {
+		t.Errorf("Expected %s but got %s", all[1], node)
+	}
+	if !rv.Contains(all[0]) || len(rv.LookupAll("foo")) != 3 {
+		t.Errorf("Expected %s to stay a member", all[0])
+	}
+
+	rv.SetHealthy(all[0], true)
+	if node := rv.Lookup("foo"); node != all[0] {
+		t.Errorf("Expected %s but got %s", all[0], node)
+	}
+}
+
+func TestRing_CheckHealth(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.SetHealthy("c", false)
+
+	checker := HealthCheckerFunc(func(ctx context.Context, node *Node) error {
+		if node.Name() == "a" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	version := rv.Version()
+	rv.CheckHealth(context.Background(), checker)
+
+	if rv.Healthy("a") || !rv.Healthy("b") || !rv.Healthy("c") {
+		t.Errorf("Expected only a to be unhealthy")
+	}
+	if rv.Version() != version+1 {
+		t.Errorf("Expected a single write but got %d", rv.Version()-version)
+	}
+
+	rv.CheckHealth(context.Background(), checker)
+	if rv.Version() != version+1 {
+		t.Errorf("Expected no write without changes")
+	}
+}
+
+func TestRing_CheckHealth_Concurrency(t *testing.T) {
+	names := make([]string, 200)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+
+	for _, limit := range []int{0, 5} {
+		var opts []Option
+		expected := defaultProbes
+		if limit > 0 {
+			opts = append(opts, WithHealthCheckConcurrency(limit))
+			expected = limit
+		}
+		rv := New(append(opts, WithNodes(names...))...)
+
+		var running, peak atomic.Int64
+		rv.CheckHealth(context.Background(), HealthCheckerFunc(func(ctx context.Context, node *Node) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			return errors.New("down")
+		}))
+
+		if p := peak.Load(); p > int64(expected) {
+			t.Errorf("Expected at most %d concurrent probes but got %d", expected, p)
+		}
+		for _, name := range names {
+			if rv.Healthy(name) {
+				t.Fatalf("Expected every node to be probed but %s was not", name)
+			}
+		}
+	}
+}
+
+func TestRing_RunHealthChecks(t *testing.T) {
+	rv := New(WithNodes("a"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- rv.RunHealthChecks(ctx, HealthCheckerFunc(func(ctx context.Context, node *Node) error {
+			return errors.New("timeout")
+		}), time.Millisecond)
+	}()
+
+	eventually(t, func() bool { return !rv.Healthy("a") })
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package rendezvous
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Hierarchy routes keys in two levels: it first selects a group, such as a
+// cluster or data center, by rendezvous hashing over the weighted groups, and
+// then a node of that group by rendezvous hashing over its weighted nodes.
+// Weights at each level are independent, so shifting traffic between groups
+// does not depend on how many nodes they have.
+//
+// Like a Ring, a Hierarchy never locks on lookups and is safe for concurrent
+// use.
+type Hierarchy struct {
+	groups *Ring
+	opts   []Option
+
+	// rings is replaced on every change to the groups, under mutex.
+	mutex sync.Mutex
+	rings atomic.Pointer[map[string]*Ring]
+}
+
+// NewHierarchy creates an empty Hierarchy whose group level and group rings
+// are configured by opts.
+func NewHierarchy(opts ...Option) *Hierarchy {
+	return &Hierarchy{
+		groups: New(opts...),
+		opts:   opts,
+	}
+}
+
+// AddGroup adds the named group with weight, or reweights an existing one.
+// A new group has no nodes; add them to the ring returned by Group.
+func (h *Hierarchy) AddGroup(group string, weight float64) *Ring {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	rings := h.load()
+	ring, ok := rings[group]
+	if !ok {
+		ring = New(h.opts...)
+		updated := make(map[string]*Ring, len(rings)+1)
+		for name, r := range rings {
+			updated[name] = r
+		}
+		updated[group] = ring
+		h.rings.Store(&updated)
+	}
+	h.groups.AddWithWeight(group, weight)
+	return ring
+}
+
+// RemoveGroup removes the named group and its nodes.
+func (h *Hierarchy) RemoveGroup(group string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.groups.Remove(group)
+	rings := h.load()
+	if _, ok := rings[group]; !ok {
+		return
+	}
+	updated := make(map[string]*Ring, len(rings))
+	for name, r := range rings {
+		if name != group {
+			updated[name] = r
+		}
+	}
+	h.rings.Store(&updated)
+}
+
+// Group returns the ring holding the nodes of the named group, or nil if
+// there is no such group.
+func (h *Hierarchy) Group(group string) *Ring {
+	return h.load()[group]
+}
+
+// Groups returns the ring of groups, for example to drain a whole group.
+// Add and remove groups with AddGroup and RemoveGroup only.
+func (h *Hierarchy) Groups() *Ring {
+	return h.groups
+}
+
+// Lookup returns the group and node owning key. If the selected group has no
+// node that lookups may return, the key falls through to the next ranked
+// group, as if the group were drained. It returns empty names if no group
+// can serve.
+func (h *Hierarchy) Lookup(key string) (group, node string) {
+	keyHash := h.groups.computeHash(key)
+	rings := h.load()
+
+	// Most keys are served by their first group, which needs no ranking.
+	if group, node := lookupGroups(rings, h.groups.topN(keyHash, 1), key); node != "" {
+		return group, node
+	}
+	return lookupGroups(rings, h.groups.topN(keyHash, h.groups.Len()), key)
+}
+
+// lookupGroups looks key up in the rings of groups, in order, and returns
+// the first group and node found.
+func lookupGroups(rings map[string]*Ring, groups []ScoredNode, key string) (group, node string) {
+	for _, scoredNode := range groups {
+		ring, ok := rings[scoredNode.node.name]
+		if !ok {
+			continue
+		}
+		if node := ring.Lookup(key); node != "" {
+			return scoredNode.node.name, node
+		}
+	}
+	return "", ""
+}
+
+func (h *Hierarchy) load() map[string]*Ring {
+	if rings := h.rings.Load(); rings != nil {
+		return *rings
+	}
+	return nil
+}
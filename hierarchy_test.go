@@ -0,0 +1,56 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestHierarchy_Lookup(t *testing.T) {
+	h := NewHierarchy()
+	east := h.AddGroup("east", 3)
+	west := h.AddGroup("west", 1)
+	for i := 0; i < 50; i++ {
+		east.Add("e" + strconv.Itoa(i))
+	}
+	west.Add("w0")
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		group, node := h.Lookup(strconv.Itoa(i))
+		if h.Group(group).Lookup(strconv.Itoa(i)) != node {
+			t.Fatalf("Expected %s to be looked up in %s", node, group)
+		}
+		counts[group]++
+	}
+
+	// Group weights decide the split, regardless of the number of nodes.
+	if counts["east"] < 2800 || counts["east"] > 3200 {
+		t.Errorf("Expected about 3000 keys in east but got %v", counts)
+	}
+
+	if h.AddGroup("west", 1) != west {
+		t.Errorf("Expected AddGroup to keep the ring of an existing group")
+	}
+}
+
+func TestHierarchy_Fallthrough(t *testing.T) {
+	h := NewHierarchy()
+	h.AddGroup("a", 1).Add("a0")
+	h.AddGroup("b", 1).Add("b0")
+	h.AddGroup("empty", 1)
+
+	for i := 0; i < 100; i++ {
+		if group, node := h.Lookup(strconv.Itoa(i)); group == "empty" || node == "" {
+			t.Fatalf("Expected keys to fall through empty groups but got %s/%s", group, node)
+		}
+	}
+
+	h.Group("a").Drain("a0")
+	h.RemoveGroup("b")
+	if group, node := h.Lookup("foo"); group != "" || node != "" {
+		t.Errorf("Expected no node but got %s/%s", group, node)
+	}
+	if h.Group("b") != nil || h.Groups().Contains("b") {
+		t.Errorf("Expected b to be removed")
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package rendezvous
+
+import "iter"
+
+// All returns an iterator over the nodes in ascending order of their names,
+// like Nodes but without building a slice. It iterates over the snapshot
+// of the ring taken by the call to All.
+func (r *Ring) All() iter.Seq[NodeInfo] {
+	nodes := r.load()
+	return func(yield func(NodeInfo) bool) {
+		for _, n := range nodes {
+			if !yield(n.info()) {
+				return
+			}
+		}
+	}
+}
+
+// Ranked returns an iterator over every node ranked for key, from highest to
+// lowest score, like LookupAll. The nodes are scored when iteration starts
+// and ordered as they are consumed, see EachCandidate.
+func (r *Ring) Ranked(key string) iter.Seq[NodeInfo] {
+	keyHash := r.computeHash(key)
+	return func(yield func(NodeInfo) bool) {
+		r.stream(r.load(), keyHash, nil, func(s ScoredNode) bool {
+			return yield(s.node.info())
+		})
+	}
+}
+
+// Candidates returns an iterator over the nodes lookups may select for key,
+// from highest to lowest score, see EachCandidate.
+func (r *Ring) Candidates(key string) iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		r.EachCandidate(key, yield)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build go1.23
+
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_All(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.Drain("b")
+
+	var infos []NodeInfo
+	for info := range rv.All() {
+		infos = append(infos, info)
+	}
+	if !reflect.DeepEqual(infos, rv.Nodes()) {
+		t.Errorf("Expected %v but got %v", rv.Nodes(), infos)
+	}
+
+	for info := range rv.All() {
+		if info.Name != "a" {
+			t.Errorf("Expected iteration to stop after a but got %s", info.Name)
+		}
+		break
+	}
+}
+
+func TestRing_Ranked(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+	rv.Drain("c")
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		var names []string
+		for info := range rv.Ranked(key) {
+			names = append(names, info.Name)
+		}
+		if expected := rv.LookupAll(key); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v for %s but got %v", expected, key, names)
+		}
+	}
+}
+
+func TestRing_Candidates(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+	rv.Drain("c")
+
+	var names []string
+	for n := range rv.Candidates("foo") {
+		names = append(names, n.Name())
+		if len(names) == 2 {
+			break
+		}
+	}
+	if expected := rv.LookupTopN("foo", 2); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+}
@@ -0,0 +1,173 @@
+// Package k8s keeps a rendezvous.Ring in sync with the ready endpoints of a
+// Kubernetes Service, as published in its EndpointSlices.
+package k8s
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Tags set on the nodes of endpoints that report them.
+const (
+	ZoneTag = "zone"
+	NodeTag = "node"
+	PodTag  = "pod"
+)
+
+// A Watcher applies the ready endpoints of a Service to a ring.
+type Watcher struct {
+	client    kubernetes.Interface
+	ring      *rendezvous.Ring
+	namespace string
+	service   string
+	opts      options
+}
+
+// An Option configures a Watcher.
+type Option func(*options)
+
+type options struct {
+	port   string
+	resync time.Duration
+}
+
+// WithPort names nodes after the Service port called name instead of the
+// first port of each EndpointSlice.
+func WithPort(name string) Option {
+	return func(o *options) {
+		o.port = name
+	}
+}
+
+// WithResync sets the informer's resync period; the default is no resync.
+func WithResync(d time.Duration) Option {
+	return func(o *options) {
+		o.resync = d
+	}
+}
+
+// New returns a Watcher applying the endpoints of the Service in namespace
+// to ring.
+func New(client kubernetes.Interface, ring *rendezvous.Ring, namespace, service string, opts ...Option) *Watcher {
+	w := &Watcher{
+		client:    client,
+		ring:      ring,
+		namespace: namespace,
+		service:   service,
+	}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	return w
+}
+
+// Run watches the Service's EndpointSlices and reconciles the ring with
+// their ready endpoints once they have all been listed, and then on every
+// change until ctx is done, returning ctx's error. Nodes are named address:port; terminating and unready endpoints are
+// removed.
+func (w *Watcher) Run(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(w.client, w.opts.resync,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.LabelSelector = labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: w.service}).String()
+		}),
+	)
+	slices := factory.Discovery().V1().EndpointSlices()
+	lister := slices.Lister()
+
+	// Until the informer has listed every EndpointSlice, the lister holds a
+	// partial list, which would remove live nodes. Reconciling is serialized,
+	// so a later reconcile always applies a later list.
+	var mutex sync.Mutex
+	var synced bool
+	reconcile := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !synced {
+			return
+		}
+		list, err := lister.List(labels.Everything())
+		if err == nil {
+			// Members carry no weights, so Reconcile cannot fail.
+			_ = w.ring.Reconcile(w.members(list))
+		}
+	}
+	if _, err := slices.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { reconcile() },
+		UpdateFunc: func(any, any) { reconcile() },
+		DeleteFunc: func(any) { reconcile() },
+	}); err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+	if !cache.WaitForCacheSync(ctx.Done(), slices.Informer().HasSynced) {
+		return ctx.Err()
+	}
+	mutex.Lock()
+	synced = true
+	mutex.Unlock()
+	reconcile()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (w *Watcher) members(slices []*discoveryv1.EndpointSlice) []rendezvous.Member {
+	var members []rendezvous.Member
+	for _, slice := range slices {
+		port, ok := w.port(slice)
+		if !ok {
+			continue
+		}
+		for _, e := range slice.Endpoints {
+			// Endpoints without a ready condition are ready.
+			if e.Conditions.Ready != nil && !*e.Conditions.Ready {
+				continue
+			}
+
+			tags := make(map[string]string)
+			if e.Zone != nil {
+				tags[ZoneTag] = *e.Zone
+			}
+			if e.NodeName != nil {
+				tags[NodeTag] = *e.NodeName
+			}
+			if e.TargetRef != nil && e.TargetRef.Kind == "Pod" {
+				tags[PodTag] = e.TargetRef.Name
+			}
+			for _, address := range e.Addresses {
+				members = append(members, rendezvous.Member{
+					Name: net.JoinHostPort(address, port),
+					Tags: tags,
+				})
+			}
+		}
+	}
+	return members
+}
+
+// port returns the slice's port for node names.
+func (w *Watcher) port(slice *discoveryv1.EndpointSlice) (string, bool) {
+	for _, p := range slice.Ports {
+		if p.Port == nil {
+			continue
+		}
+		if w.opts.port == "" || (p.Name != nil && *p.Name == w.opts.port) {
+			return strconv.Itoa(int(*p.Port)), true
+		}
+	}
+	return "", false
+}
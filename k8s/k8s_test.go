@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func slice(name, service string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports: []discoveryv1.EndpointPort{
+			{Name: ptr("metrics"), Port: ptr(int32(9090))},
+			{Name: ptr("http"), Port: ptr(int32(8080))},
+		},
+		Endpoints: endpoints,
+	}
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatalf("Expected the condition to hold eventually")
+}
+
+func TestWatcher(t *testing.T) {
+	client := fake.NewClientset(
+		slice("web-1", "web",
+			discoveryv1.Endpoint{
+				Addresses: []string{"10.0.0.1"},
+				Zone:      ptr("z1"),
+				NodeName:  ptr("n1"),
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-0"},
+			},
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr(false)}},
+		),
+		slice("other-1", "other", discoveryv1.Endpoint{Addresses: []string{"10.0.0.3"}}),
+	)
+	ring := rendezvous.New()
+	w := New(client, ring, "default", "web", WithPort("http"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	eventually(t, func() bool { return ring.Len() > 0 })
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"10.0.0.1:8080"}) {
+		t.Errorf("Expected [10.0.0.1:8080] but got %v", names)
+	}
+	expected := map[string]string{ZoneTag: "z1", NodeTag: "n1", PodTag: "web-0"}
+	if tags := ring.Tags("10.0.0.1:8080"); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %v but got %v", expected, tags)
+	}
+
+	updated := slice("web-1", "web",
+		discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr(false)}},
+		discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}},
+	)
+	if _, err := client.DiscoveryV1().EndpointSlices("default").Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return ring.Contains("10.0.0.2:8080") && !ring.Contains("10.0.0.1:8080") })
+
+	if err := client.DiscoveryV1().EndpointSlices("default").Delete(ctx, "web-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return ring.Len() == 0 })
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+}
+
+func TestWatcher_Startup(t *testing.T) {
+	client := fake.NewClientset(
+		slice("web-1", "web", discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}),
+		slice("web-2", "web", discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}}),
+		slice("web-3", "web", discoveryv1.Endpoint{Addresses: []string{"10.0.0.3"}}),
+	)
+	ring := rendezvous.New(rendezvous.WithNodes("10.0.0.1:8080", "10.0.0.2:8080", "10.0.0.3:8080"))
+	var events atomic.Int64
+	ring.OnChange(func(rendezvous.ChangeEvent) { events.Add(1) })
+	w := New(client, ring, "default", "web", WithPort("http"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	// Nodes are not removed while the EndpointSlices are partially listed.
+	if _, err := client.DiscoveryV1().EndpointSlices("default").Create(ctx,
+		slice("web-4", "web", discoveryv1.Endpoint{Addresses: []string{"10.0.0.4"}}), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return ring.Contains("10.0.0.4:8080") })
+	if events.Load() != 1 || ring.Len() != 4 {
+		t.Errorf("Expected only 10.0.0.4:8080 to be added but got %d changes and %v", events.Load(), ring.List())
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got %v", err)
+	}
+}
+
+func TestWatcher_members(t *testing.T) {
+	s := slice("web-1", "web", discoveryv1.Endpoint{Addresses: []string{"10.0.0.1", "10.0.0.2"}})
+	if members := New(nil, nil, "default", "web").members([]*discoveryv1.EndpointSlice{s}); len(members) != 2 || members[0].Name != "10.0.0.1:9090" {
+		t.Errorf("Expected the first port by default but got %v", members)
+	}
+	if members := New(nil, nil, "default", "web", WithPort("grpc")).members([]*discoveryv1.EndpointSlice{s}); len(members) != 0 {
+		t.Errorf("Expected slices without the port to be skipped but got %v", members)
+	}
+}
@@ -0,0 +1,40 @@
+package rendezvous
+
+// A KeyHandle is a key hashed once by Ring.Key, for keys that are looked up
+// over and over again. It is only valid for the ring that created it and for
+// rings hashing the same way, such as its clones.
+type KeyHandle struct {
+	key  string
+	hash uint64
+}
+
+// Key hashes key for repeated lookups with LookupKey, LookupTopNKey and
+// LookupAllKey.
+func (r *Ring) Key(key string) KeyHandle {
+	return KeyHandle{key: key, hash: r.computeHash(key)}
+}
+
+// String returns the key.
+func (k KeyHandle) String() string {
+	return k.key
+}
+
+// Hash returns the key's hash, as returned by Ring.Hash.
+func (k KeyHandle) Hash() uint64 {
+	return k.hash
+}
+
+// LookupKey is like Lookup but takes a key hashed by Key.
+func (r *Ring) LookupKey(key KeyHandle) string {
+	return r.lookup(key.hash)
+}
+
+// LookupTopNKey is like LookupTopN but takes a key hashed by Key.
+func (r *Ring) LookupTopNKey(key KeyHandle, n int) []string {
+	return r.lookupTopN(key.hash, n)
+}
+
+// LookupAllKey is like LookupAll but takes a key hashed by Key.
+func (r *Ring) LookupAllKey(key KeyHandle) []string {
+	return r.lookupAll(key.hash)
+}
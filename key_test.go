@@ -0,0 +1,39 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_Key(t *testing.T) {
+	rv := New(WithSeed(7), WithNodes("a", "b", "c", "d"))
+	key := rv.Key("queue")
+	if key.String() != "queue" || key.Hash() != rv.Hash("queue") {
+		t.Errorf("Expected the handle of queue but got %v", key)
+	}
+
+	if node, expected := rv.LookupKey(key), rv.Lookup("queue"); node != expected {
+		t.Errorf("Expected %s but got %s", expected, node)
+	}
+	if nodes, expected := rv.LookupTopNKey(key, 2), rv.LookupTopN("queue", 2); !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("Expected %v but got %v", expected, nodes)
+	}
+	if nodes, expected := rv.LookupAllKey(key), rv.LookupAll("queue"); !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("Expected %v but got %v", expected, nodes)
+	}
+
+	rv.Pin("queue", "d")
+	if node := rv.LookupKey(key); node != "d" {
+		t.Errorf("Expected the pinned node d but got %s", node)
+	}
+}
+
+func BenchmarkRing_LookupKey(b *testing.B) {
+	rv := New(WithNodes("a", "b", "c", "d", "e", "f", "g", "h"))
+	key := rv.Key("some-fairly-long-queue-name-that-takes-a-while-to-hash")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.LookupKey(key)
+	}
+}
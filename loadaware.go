@@ -0,0 +1,60 @@
+package rendezvous
+
+// A LoadReporter reports the current load of a node, such as its number of
+// open connections or in-flight requests. Lower values mean less loaded.
+// Implementations are called on every load-aware lookup and must be safe for
+// concurrent use.
+type LoadReporter interface {
+	Load(name string) float64
+}
+
+// The LoadReporterFunc type is an adapter to allow the use of ordinary
+// functions as load reporters.
+type LoadReporterFunc func(name string) float64
+
+func (f LoadReporterFunc) Load(name string) float64 {
+	return f(name)
+}
+
+// LookupLeastLoaded returns the least loaded of n candidates for key, as
+// reported by the ring's LoadReporter: the node Lookup returns, including
+// any pin or routing, followed by the next highest ranked nodes. Ties go to
+// the earlier candidate. Without a LoadReporter, or for n below 2, it
+// returns Lookup(key); without active nodes it returns the fallback of
+// WithFallback.
+func (r *Ring) LookupLeastLoaded(key string, n int) string {
+	reporter := r.opts.reporter
+	if reporter == nil || n < 2 {
+		return r.Lookup(key)
+	}
+
+	nodes := r.load()
+	keyHash := r.computeHash(key)
+	owner := r.owner(nodes, keyHash)
+	if owner == nil {
+		return r.opts.fallback
+	}
+
+	best, bestLoad := owner.name, reporter.Load(owner.name)
+	remaining := n - 1
+	r.stream(nodes, keyHash, active, func(s ScoredNode) bool {
+		if s.node == owner {
+			return true
+		}
+		if load := reporter.Load(s.node.name); load < bestLoad {
+			best, bestLoad = s.node.name, load
+		}
+		remaining--
+		return remaining > 0
+	})
+	return best
+}
+
+// LookupTwoChoices returns the less loaded of key's owner and the next
+// highest ranked node, the power of two choices: a key moves off its owner
+// only while the owner is busier than the runner-up, which keeps most
+// placements stable while cutting tail latency under skewed load. It is
+// LookupLeastLoaded with n = 2.
+func (r *Ring) LookupTwoChoices(key string) string {
+	return r.LookupLeastLoaded(key, 2)
+}
@@ -0,0 +1,129 @@
+package rendezvous
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestRing_LookupLeastLoaded(t *testing.T) {
+	t.Run("WithoutReporter", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c", "d", "e"), WithSlotTable(64))
+		rv.Pin("k0", "e")
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if expected, actual := rv.Lookup(key), rv.LookupLeastLoaded(key, 3); actual != expected {
+				t.Errorf("Expected %s but got %s", expected, actual)
+			}
+		}
+
+		if node := New(WithFallback("default")).LookupLeastLoaded("k0", 3); node != "default" {
+			t.Errorf("Expected the fallback but got %s", node)
+		}
+	})
+
+	t.Run("PicksLeastLoadedCandidate", func(t *testing.T) {
+		loads := map[string]float64{}
+		rv := New(
+			WithNodes("a", "b", "c", "d", "e"),
+			WithLoadReporter(LoadReporterFunc(func(name string) float64 {
+				return loads[name]
+			})),
+		)
+
+		top := rv.LookupTopN("foo", 3)
+		loads[top[0]] = 10
+		loads[top[1]] = 5
+		loads[top[2]] = 7
+		if node := rv.LookupLeastLoaded("foo", 3); node != top[1] {
+			t.Errorf("Expected %s but got %s", top[1], node)
+		}
+
+		// Nodes outside the top n are never considered.
+		for _, name := range rv.LookupAll("foo")[3:] {
+			loads[name] = -1
+		}
+		if node := rv.LookupLeastLoaded("foo", 3); node != top[1] {
+			t.Errorf("Expected %s but got %s", top[1], node)
+		}
+
+		loads[top[1]] = 10
+		loads[top[2]] = 10
+		if node := rv.LookupLeastLoaded("foo", 3); node != top[0] {
+			t.Errorf("Expected ties to go to %s but got %s", top[0], node)
+		}
+	})
+
+	t.Run("ClampsN", func(t *testing.T) {
+		rv := New(
+			WithNodes("a", "b", "c"),
+			WithLoadReporter(LoadReporterFunc(func(name string) float64 {
+				if name == "b" {
+					return 0
+				}
+				return 1
+			})),
+		)
+		if node := rv.LookupLeastLoaded("foo", math.MaxInt); node != "b" {
+			t.Errorf("Expected b but got %s", node)
+		}
+	})
+
+	t.Run("Routed", func(t *testing.T) {
+		loads := map[string]float64{}
+		rv := New(
+			WithNodes("a", "b", "c"),
+			WithLoadReporter(LoadReporterFunc(func(name string) float64 {
+				return loads[name]
+			})),
+		)
+		all := rv.LookupAll("foo")
+		rv.Pin("foo", all[2])
+		loads[all[2]] = 1
+		if node := rv.LookupLeastLoaded("foo", 1); node != all[2] {
+			t.Errorf("Expected the pinned %s but got %s", all[2], node)
+		}
+		if node := rv.LookupLeastLoaded("foo", 2); node != all[0] {
+			t.Errorf("Expected %s but got %s", all[0], node)
+		}
+		loads[all[2]] = 0
+		if node := rv.LookupLeastLoaded("foo", 2); node != all[2] {
+			t.Errorf("Expected ties to go to the pinned %s but got %s", all[2], node)
+		}
+
+		empty := New(WithFallback("default"), WithLoadReporter(LoadReporterFunc(func(string) float64 { return 0 })))
+		if node := empty.LookupLeastLoaded("foo", 2); node != "default" {
+			t.Errorf("Expected the fallback but got %s", node)
+		}
+	})
+
+	t.Run("EmptyRing", func(t *testing.T) {
+		if node := New().LookupLeastLoaded("foo", 2); node != "" {
+			t.Errorf("Expected empty string but got %s", node)
+		}
+	})
+}
+
+func TestRing_LookupTwoChoices(t *testing.T) {
+	loads := map[string]float64{}
+	rv := New(
+		WithNodes("a", "b", "c", "d"),
+		WithLoadReporter(LoadReporterFunc(func(name string) float64 {
+			return loads[name]
+		})),
+	)
+
+	top := rv.LookupTopN("foo", 3)
+	if node := rv.LookupTwoChoices("foo"); node != top[0] {
+		t.Errorf("Expected ties to go to the owner %s but got %s", top[0], node)
+	}
+	loads[top[0]] = 2
+	loads[top[1]] = 1
+	if node := rv.LookupTwoChoices("foo"); node != top[1] {
+		t.Errorf("Expected %s but got %s", top[1], node)
+	}
+	loads[top[2]] = 0
+	if node := rv.LookupTwoChoices("foo"); node != top[1] {
+		t.Errorf("Expected only two choices but got %s", node)
+	}
+}
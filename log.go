@@ -0,0 +1,49 @@
+package rendezvous
+
+import (
+	"context"
+	"log/slog"
+)
+
+// listenOptions registers the listeners configured by the ring's options.
+// The caller must hold the mutex or own the ring exclusively.
+func (r *Ring) listenOptions() {
+	if logger := r.opts.logger; logger != nil {
+		r.listeners = append(r.listeners, &listener{fn: func(e ChangeEvent) {
+			logChange(logger, e)
+		}})
+	}
+}
+
+// logChange logs a change to the ring with structured fields.
+func logChange(logger *slog.Logger, e ChangeEvent) {
+	ctx := context.Background()
+	version := slog.Uint64("version", e.Version)
+
+	switch e.Type {
+	case NodeAdded:
+		logger.LogAttrs(ctx, slog.LevelInfo, "node added",
+			slog.String("node", e.New.name), slog.Float64("weight", e.New.weight), version)
+	case NodeRemoved:
+		logger.LogAttrs(ctx, slog.LevelInfo, "node removed",
+			slog.String("node", e.Old.name), version)
+	case NodeChanged:
+		node := slog.String("node", e.New.name)
+		if e.Old.weight != e.New.weight {
+			logger.LogAttrs(ctx, slog.LevelInfo, "node weight updated", node,
+				slog.Float64("old_weight", e.Old.weight), slog.Float64("weight", e.New.weight), version)
+		}
+		switch {
+		case !e.Old.drained && e.New.drained:
+			logger.LogAttrs(ctx, slog.LevelInfo, "node drained", node, version)
+		case e.Old.drained && !e.New.drained:
+			logger.LogAttrs(ctx, slog.LevelInfo, "node activated", node, version)
+		}
+		switch {
+		case !e.Old.unhealthy && e.New.unhealthy:
+			logger.LogAttrs(ctx, slog.LevelWarn, "node unhealthy", node, version)
+		case e.Old.unhealthy && !e.New.unhealthy:
+			logger.LogAttrs(ctx, slog.LevelInfo, "node healthy", node, version)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package rendezvous
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	rv := New(WithLogger(logger), WithNodes("a"))
+	rv.AddWithWeight("a", 2)
+	rv.Drain("a")
+	rv.Activate("a")
+	rv.SetHealthy("a", false)
+	rv.SetHealthy("a", true)
+	rv.Clone().Remove("a")
+
+	expected := []string{
+		`level=INFO msg="node added" node=a weight=1 version=1`,
+		`level=INFO msg="node weight updated" node=a old_weight=1 weight=2 version=2`,
+		`level=INFO msg="node drained" node=a version=3`,
+		`level=INFO msg="node activated" node=a version=4`,
+		`level=WARN msg="node unhealthy" node=a version=5`,
+		`level=INFO msg="node healthy" node=a version=6`,
+		`level=INFO msg="node removed" node=a version=7`,
+	}
+	if actual := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected\n%s\nbut got\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+}
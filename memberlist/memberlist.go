@@ -0,0 +1,154 @@
+// Package memberlist keeps a rendezvous.Ring in sync with the live members of
+// a hashicorp/memberlist gossip cluster, turning the ring into a
+// self-maintaining shard router for peer-to-peer services.
+package memberlist
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/mosuka/rendezvous"
+)
+
+// AddressTag is the tag holding a member's gossip address, host:port, unless
+// its Meta sets the tag itself.
+const AddressTag = "address"
+
+// Meta is the metadata a member gossips to its peers, mapped to its weight
+// and tags in their rings.
+type Meta struct {
+	// Weight is the member's weight; nil means the default weight, and zero
+	// a member that is never selected.
+	Weight *float64          `json:"weight,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+// An Option configures an Events delegate.
+type Option func(*Events)
+
+// WithErrorHandler reports members gossiping malformed metadata or an
+// invalid weight to fn. Members with an invalid weight are left out of the
+// ring.
+func WithErrorHandler(fn func(error)) Option {
+	return func(e *Events) {
+		e.onError = fn
+	}
+}
+
+// Events is a memberlist.EventDelegate applying membership events to a ring.
+type Events struct {
+	ring    *rendezvous.Ring
+	onError func(error)
+}
+
+// NewEvents returns an Events delegate updating ring.
+func NewEvents(ring *rendezvous.Ring, opts ...Option) *Events {
+	e := &Events{ring: ring}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// NotifyJoin adds the member to the ring.
+func (e *Events) NotifyJoin(n *memberlist.Node) {
+	e.add(n)
+}
+
+// NotifyLeave removes the member from the ring.
+func (e *Events) NotifyLeave(n *memberlist.Node) {
+	e.ring.Remove(n.Name)
+}
+
+// NotifyUpdate applies the member's new metadata to the ring.
+func (e *Events) NotifyUpdate(n *memberlist.Node) {
+	e.add(n)
+}
+
+func (e *Events) add(n *memberlist.Node) {
+	var meta Meta
+	if len(n.Meta) > 0 {
+		// Members gossiping malformed metadata still join with the default
+		// weight rather than being invisible.
+		if err := json.Unmarshal(n.Meta, &meta); err != nil {
+			meta = Meta{}
+			e.report(fmt.Errorf("memberlist: malformed meta of member %q: %w", n.Name, err))
+		}
+	}
+	weight := 1.0
+	if meta.Weight != nil {
+		if err := rendezvous.CheckWeight(*meta.Weight); err != nil {
+			e.ring.Remove(n.Name)
+			e.report(fmt.Errorf("memberlist: %w of member %q", err, n.Name))
+			return
+		}
+		weight = *meta.Weight
+	}
+	tags := make(map[string]string, len(meta.Tags)+1)
+	tags[AddressTag] = n.Address()
+	for k, v := range meta.Tags {
+		tags[k] = v
+	}
+	e.ring.AddWithTags(n.Name, weight, tags)
+}
+
+func (e *Events) report(err error) {
+	if e.onError != nil {
+		e.onError(err)
+	}
+}
+
+// Delegate is a memberlist.Delegate gossiping the local member's Meta. It
+// ignores user messages and state exchange.
+type Delegate struct {
+	meta []byte
+}
+
+// NewDelegate returns a Delegate gossiping meta. It fails if the encoded meta
+// exceeds memberlist.MetaMaxSize.
+func NewDelegate(meta Meta) (*Delegate, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > memberlist.MetaMaxSize {
+		return nil, fmt.Errorf("memberlist: encoded meta of %d bytes exceeds %d bytes", len(data), memberlist.MetaMaxSize)
+	}
+	return &Delegate{meta: data}, nil
+}
+
+// NodeMeta implements memberlist.Delegate.
+func (d *Delegate) NodeMeta(limit int) []byte {
+	return d.meta
+}
+
+// NotifyMsg implements memberlist.Delegate.
+func (d *Delegate) NotifyMsg([]byte) {}
+
+// GetBroadcasts implements memberlist.Delegate.
+func (d *Delegate) GetBroadcasts(overhead, limit int) [][]byte {
+	return nil
+}
+
+// LocalState implements memberlist.Delegate.
+func (d *Delegate) LocalState(join bool) []byte {
+	return nil
+}
+
+// MergeRemoteState implements memberlist.Delegate.
+func (d *Delegate) MergeRemoteState(buf []byte, join bool) {}
+
+// Create creates a memberlist from conf that gossips meta for the local member
+// and keeps ring in sync with the live members, including the local one. It
+// replaces conf.Delegate and conf.Events, which opts configure. Join peers
+// with the returned Memberlist's Join.
+func Create(ring *rendezvous.Ring, conf *memberlist.Config, meta Meta, opts ...Option) (*memberlist.Memberlist, error) {
+	delegate, err := NewDelegate(meta)
+	if err != nil {
+		return nil, err
+	}
+	conf.Delegate = delegate
+	conf.Events = NewEvents(ring, opts...)
+	return memberlist.Create(conf)
+}
@@ -0,0 +1,106 @@
+package memberlist
+
+import (
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/mosuka/rendezvous"
+)
+
+func create(t *testing.T, name string, meta Meta) (*rendezvous.Ring, *memberlist.Memberlist) {
+	t.Helper()
+
+	conf := memberlist.DefaultLocalConfig()
+	conf.Name = name
+	conf.BindAddr = "127.0.0.1"
+	conf.BindPort = 0
+	conf.Logger = log.New(io.Discard, "", 0)
+
+	ring := rendezvous.New()
+	list, err := Create(ring, conf, meta)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	t.Cleanup(func() { _ = list.Shutdown() })
+	return ring, list
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatalf("Expected the condition to hold eventually")
+}
+
+func TestCreate(t *testing.T) {
+	weight := 2.0
+	ringA, listA := create(t, "a", Meta{Weight: &weight, Tags: map[string]string{"zone": "z1"}})
+	ringB, listB := create(t, "b", Meta{})
+
+	if _, err := listB.Join([]string{listA.LocalNode().Address()}); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	for _, ring := range []*rendezvous.Ring{ringA, ringB} {
+		eventually(t, func() bool {
+			return reflect.DeepEqual(ring.List(), []string{"a", "b"})
+		})
+		if ring.Weight("a") != 2 || ring.Weight("b") != 1 {
+			t.Errorf("Expected weights 2 and 1 but got %v and %v", ring.Weight("a"), ring.Weight("b"))
+		}
+		if tags := ring.Tags("a"); tags["zone"] != "z1" || tags[AddressTag] != listA.LocalNode().Address() {
+			t.Errorf("Expected the tags of a but got %v", tags)
+		}
+	}
+
+	if err := listB.Leave(time.Second); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	eventually(t, func() bool {
+		return reflect.DeepEqual(ringA.List(), []string{"a"})
+	})
+}
+
+func TestEvents(t *testing.T) {
+	ring := rendezvous.New()
+	var errs []error
+	e := NewEvents(ring, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	e.NotifyJoin(&memberlist.Node{Name: "a", Meta: []byte(`{"weight":0}`)})
+	e.NotifyJoin(&memberlist.Node{Name: "b", Meta: []byte(`{}`)})
+	e.NotifyJoin(&memberlist.Node{Name: "c", Meta: []byte(`x`)})
+	e.NotifyJoin(&memberlist.Node{Name: "d", Meta: []byte(`{"weight":-1}`)})
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("Expected [a b c] but got %v", names)
+	}
+	if ring.Weight("a") != 0 || ring.Weight("b") != 1 || ring.Weight("c") != 1 {
+		t.Errorf("Expected weights 0, 1 and 1 but got %v, %v and %v", ring.Weight("a"), ring.Weight("b"), ring.Weight("c"))
+	}
+
+	e.NotifyUpdate(&memberlist.Node{Name: "b", Meta: []byte(`{"weight":-2}`)})
+	if ring.Contains("b") {
+		t.Errorf("Expected b with an invalid weight to be left out")
+	}
+	if len(errs) != 3 {
+		t.Errorf("Expected the malformed meta and the invalid weights to be reported but got %v", errs)
+	}
+}
+
+func TestNewDelegate(t *testing.T) {
+	tags := map[string]string{}
+	for i := 0; i < 100; i++ {
+		tags[string(rune('a'+i%26))+string(rune('a'+i/26))] = "value"
+	}
+	if _, err := NewDelegate(Meta{Tags: tags}); err == nil {
+		t.Errorf("Expected an error for oversized meta")
+	}
+}
@@ -0,0 +1,108 @@
+package rendezvous
+
+import "fmt"
+
+// A MergePolicy decides the weight of a node that is a member of both rings
+// passed to Merge.
+type MergePolicy int
+
+const (
+	// MergePreferLeft keeps the weight of the ring merged into.
+	MergePreferLeft MergePolicy = iota
+	// MergeMax takes the higher of both weights.
+	MergeMax
+	// MergeSum adds both weights, for nodes serving both clusters.
+	MergeSum
+)
+
+// String returns the name of the policy.
+func (p MergePolicy) String() string {
+	switch p {
+	case MergePreferLeft:
+		return "prefer-left"
+	case MergeMax:
+		return "max"
+	case MergeSum:
+		return "sum"
+	}
+	return fmt.Sprintf("MergePolicy(%d)", int(p))
+}
+
+// A WeightChange is a node whose weight differs between two memberships.
+type WeightChange struct {
+	Name     string
+	From, To float64
+}
+
+// A MergeReport lists the changes Merge made.
+type MergeReport struct {
+	// Added are the nodes of the other ring that were new, in ascending
+	// order.
+	Added []string
+	// Reweighted are the nodes of both rings whose weight the policy
+	// changed, in ascending order of their names.
+	Reweighted []WeightChange
+}
+
+// Merge adds the nodes of other to the ring under a single write, for
+// example when federating two clusters. New nodes take their weight and tags
+// from other and start active; nodes of both rings keep their tags and
+// states and are weighted by policy. Merging a ring into itself changes
+// nothing.
+func (r *Ring) Merge(other *Ring, policy MergePolicy) MergeReport {
+	var report MergeReport
+	if other == r {
+		return report
+	}
+	otherNodes := other.load()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nodes := r.load()
+	arena := r.arena(len(otherNodes), 0)
+	merged := make([]*Node, 0, len(nodes)+len(otherNodes))
+	i, j := 0, 0
+	for i < len(nodes) || j < len(otherNodes) {
+		switch {
+		case j == len(otherNodes) || (i < len(nodes) && nodes[i].name < otherNodes[j].name):
+			merged = append(merged, nodes[i])
+			i++
+		case i == len(nodes) || otherNodes[j].name < nodes[i].name:
+			o := otherNodes[j]
+			n := arena.newNode(r, o.name)
+			n.weight = o.weight
+			n.tags = copyTags(o.tags)
+			merged = append(merged, n)
+			report.Added = append(report.Added, o.name)
+			j++
+		default:
+			n, weight := nodes[i], mergeWeight(nodes[i].weight, otherNodes[j].weight, policy)
+			if weight != n.weight {
+				report.Reweighted = append(report.Reweighted, WeightChange{Name: n.name, From: n.weight, To: weight})
+				n = arena.copyNode(n)
+				n.weight = weight
+			}
+			merged = append(merged, n)
+			i++
+			j++
+		}
+	}
+
+	if len(report.Added) > 0 || len(report.Reweighted) > 0 {
+		r.store(merged)
+	}
+	return report
+}
+
+func mergeWeight(left, right float64, policy MergePolicy) float64 {
+	switch policy {
+	case MergeMax:
+		if right > left {
+			return right
+		}
+	case MergeSum:
+		return left + right
+	}
+	return left
+}
@@ -0,0 +1,47 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_Merge(t *testing.T) {
+	for _, test := range []struct {
+		policy     MergePolicy
+		weight     float64
+		reweighted []WeightChange
+	}{
+		{MergePreferLeft, 1, nil},
+		{MergeMax, 3, []WeightChange{{Name: "b", From: 1, To: 3}}},
+		{MergeSum, 4, []WeightChange{{Name: "b", From: 1, To: 4}}},
+	} {
+		t.Run(test.policy.String(), func(t *testing.T) {
+			left := New(WithNodes("a", "b"))
+			left.Drain("b")
+			right := New()
+			right.AddWithWeight("b", 3)
+			right.AddWithTags("c", 2, map[string]string{ZoneTag: "z2"})
+
+			version := left.Version()
+			report := left.Merge(right, test.policy)
+			if !reflect.DeepEqual(report.Added, []string{"c"}) || !reflect.DeepEqual(report.Reweighted, test.reweighted) {
+				t.Errorf("Expected c to be added and %v to be reweighted but got %+v", test.reweighted, report)
+			}
+			if left.Version() != version+1 {
+				t.Errorf("Expected a single write")
+			}
+			if left.Weight("b") != test.weight || !left.Drained("b") || left.Weight("c") != 2 || left.Tags("c")[ZoneTag] != "z2" {
+				t.Errorf("Expected merged weights, states and tags")
+			}
+		})
+	}
+
+	rv := New(WithNodes("a"))
+	version := rv.Version()
+	if report := rv.Merge(rv, MergeSum); len(report.Added) != 0 || rv.Version() != version || rv.Weight("a") != 1 {
+		t.Errorf("Expected merging a ring into itself to change nothing")
+	}
+	if report := rv.Merge(New(WithNodes("a")), MergePreferLeft); report.Added != nil || rv.Version() != version {
+		t.Errorf("Expected no change but got %+v", report)
+	}
+}
@@ -0,0 +1,97 @@
+package rendezvous
+
+// A Movement records a key changing its owner from one node to another.
+// From or To is "" when there was or will be no owner at all.
+type Movement struct {
+	Key  string
+	From string
+	To   string
+}
+
+// PlanRemoval reports, for each of keys currently owned by the named node,
+// which node will own it once the node is removed. Keys owned by other nodes
+// are unaffected by the removal and are not reported. The ring is not
+// modified, so operators can hand data off before calling Remove.
+func (r *Ring) PlanRemoval(name string, keys []string) []Movement {
+	nodes, after := r.without(name)
+	afterNodes := after.load()
+
+	movements := make([]Movement, 0)
+	for _, key := range keys {
+		keyHash := r.computeHash(key)
+		if owner := r.owner(nodes, keyHash); owner == nil || owner.name != name {
+			continue
+		}
+
+		movement := Movement{Key: key, From: name}
+		if next := after.owner(afterNodes, keyHash); next != nil {
+			movement.To = next.name
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements
+}
+
+// without returns the current nodes of r and a scratch ring holding them
+// except the named one, which routes keys the way r will once the node is
+// removed.
+func (r *Ring) without(name string) ([]*Node, *Ring) {
+	// The backend is copied in the state it built the current nodes in.
+	r.mutex.Lock()
+	nodes := r.load()
+	backend := copyBackend(r.opts.backend)
+	r.mutex.Unlock()
+
+	remaining := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.name != name {
+			remaining = append(remaining, n)
+		}
+	}
+	return nodes, r.scratch(remaining, backend)
+}
+
+// scratch returns a ring configured like r holding nodes, sorted by name,
+// which routes keys like r would with these nodes, including through its
+// pins, slot table, backend or skeleton tree. It builds with backend, a copy
+// of a stateful backend of r, and has no listeners, logger, cache or slow
+// start, so building it has no side effects on r.
+func (r *Ring) scratch(nodes []*Node, backend Backend) *Ring {
+	opts := r.opts
+	opts.backend = backend
+	opts.logger, opts.slowStart = nil, 0
+	s := &Ring{opts: opts, hasher: r.hasher}
+	if pins := r.pins.Load(); pins != nil {
+		s.pins.Store(pins)
+	}
+
+	s.mutex.Lock()
+	s.store(nodes)
+	s.mutex.Unlock()
+
+	return s
+}
+
+// Diff reports the keys whose owner differs between the before and the after
+// ring, for example before and after a membership or weight change, so that
+// receiving nodes can be warmed up before traffic is cut over.
+func Diff(before, after *Ring, keys []string) []Movement {
+	beforeNodes, afterNodes := before.load(), after.load()
+
+	movements := make([]Movement, 0)
+	for _, key := range keys {
+		var from, to string
+		if owner := before.owner(beforeNodes, before.computeHash(key)); owner != nil {
+			from = owner.name
+		}
+		if owner := after.owner(afterNodes, after.computeHash(key)); owner != nil {
+			to = owner.name
+		}
+		if from != to {
+			movements = append(movements, Movement{Key: key, From: from, To: to})
+		}
+	}
+
+	return movements
+}
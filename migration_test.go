@@ -0,0 +1,111 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRing_PlanRemoval(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+
+	movements := rv.PlanRemoval("c", keys)
+	if len(movements) == 0 {
+		t.Fatalf("Expected c to own some of the keys")
+	}
+
+	after := New(WithNodes("a", "b", "d", "e"))
+	planned := make(map[string]Movement, len(movements))
+	for _, m := range movements {
+		planned[m.Key] = m
+		if m.From != "c" {
+			t.Errorf("Expected %s to move from c but got %s", m.Key, m.From)
+		}
+		if expected := after.Lookup(m.Key); m.To != expected {
+			t.Errorf("Expected %s to move to %s but got %s", m.Key, expected, m.To)
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := planned[key]; !ok && rv.Lookup(key) == "c" {
+			t.Errorf("Expected %s to be planned", key)
+		}
+	}
+
+	if !rv.Contains("c") {
+		t.Errorf("Expected PlanRemoval not to modify the ring")
+	}
+
+	t.Run("LastNode", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		movements := rv.PlanRemoval("a", []string{"foo"})
+		if len(movements) != 1 || movements[0].To != "" {
+			t.Errorf("Expected foo to lose its owner but got %v", movements)
+		}
+	})
+}
+
+func TestRing_PlanRemoval_Backend(t *testing.T) {
+	rv := New(WithBackend(&orderBackend{}))
+	for i := 0; i < 10; i++ {
+		rv.Add("n" + strconv.Itoa(i))
+	}
+
+	keys := make([]string, 2000)
+	before := make([]string, len(keys))
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+		before[i] = rv.Lookup(keys[i])
+	}
+
+	if movements := rv.PlanRemoval("n3", keys); len(movements) == 0 {
+		t.Fatalf("Expected n3 to own some of the keys")
+	}
+
+	// Rebuilding the backend without a membership change keeps every key in
+	// place, unless planning changed the state of the backend.
+	rv.AddWithWeight("n0", defaultWeight)
+	for i, key := range keys {
+		if node := rv.Lookup(key); node != before[i] {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, before[i], node)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := New(WithNodes("a", "b", "c", "d"))
+	after := New(WithNodes("a", "b", "c", "d", "e"))
+	after.AddWithWeight("a", 2.0)
+
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+
+	movements := Diff(before, after, keys)
+	moved := make(map[string]Movement, len(movements))
+	for _, m := range movements {
+		moved[m.Key] = m
+		if m.From == m.To {
+			t.Errorf("Expected %s to change owner but got %v", m.Key, m)
+		}
+		if m.To != "a" && m.To != "e" {
+			t.Errorf("Expected keys to move only to a or e but got %v", m)
+		}
+	}
+
+	for _, key := range keys {
+		from, to := before.Lookup(key), after.Lookup(key)
+		if m, ok := moved[key]; ok != (from != to) || (ok && (m.From != from || m.To != to)) {
+			t.Errorf("Expected %s to move from %s to %s but got %v", key, from, to, m)
+		}
+	}
+
+	if movements := Diff(before, before, keys); len(movements) != 0 {
+		t.Errorf("Expected no movements but got %v", movements)
+	}
+}
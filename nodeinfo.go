@@ -0,0 +1,67 @@
+package rendezvous
+
+// A NodeState is the state of a node as reported by Nodes.
+type NodeState int
+
+const (
+	// NodeActive is a node lookups may select.
+	NodeActive NodeState = iota
+	// NodeDrained is a node taken out of rotation, see Ring.Drain.
+	NodeDrained
+	// NodeUnhealthy is a node failing its health checks, see
+	// Ring.SetHealthy.
+	NodeUnhealthy
+)
+
+// String returns the name of the state.
+func (s NodeState) String() string {
+	switch s {
+	case NodeActive:
+		return "active"
+	case NodeDrained:
+		return "drained"
+	case NodeUnhealthy:
+		return "unhealthy"
+	}
+	return "unknown"
+}
+
+// NodeInfo describes a node, see Ring.Nodes.
+type NodeInfo struct {
+	Name   string
+	Weight float64
+	// Hash is the hash of the node's name.
+	Hash uint64
+	// State is NodeDrained for drained nodes, whether healthy or not.
+	State NodeState
+	Tags  map[string]string
+}
+
+// Active reports whether lookups may select the node: it is neither drained
+// nor unhealthy, and weighted above zero.
+func (i NodeInfo) Active() bool {
+	return i.State == NodeActive && i.Weight > 0
+}
+
+// Nodes describes every node in ascending order of their names, read from a
+// single snapshot of the ring. Nodes weighted zero are never selected
+// whatever their state. The tags are copies.
+func (r *Ring) Nodes() []NodeInfo {
+	nodes := r.load()
+	infos := make([]NodeInfo, len(nodes))
+	for i, n := range nodes {
+		infos[i] = n.info()
+	}
+	return infos
+}
+
+func (n *Node) info() NodeInfo {
+	state := NodeActive
+	switch {
+	case n.drained:
+		state = NodeDrained
+	case n.unhealthy:
+		state = NodeUnhealthy
+	}
+	return NodeInfo{Name: n.name, Weight: n.weight, Hash: n.hash, State: state, Tags: n.Tags()}
+}
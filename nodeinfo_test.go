@@ -0,0 +1,42 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_Nodes(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.AddWithTags("d", 2, map[string]string{ZoneTag: "z1"})
+	rv.Drain("b")
+	rv.SetHealthy("c", false)
+
+	var states []NodeState
+	for _, info := range rv.Nodes() {
+		states = append(states, info.State)
+		if info.Hash != rv.Hash(info.Name) || info.Weight != rv.Weight(info.Name) {
+			t.Errorf("Expected the hash and weight of %s", info.Name)
+		}
+	}
+	expected := []NodeState{NodeActive, NodeDrained, NodeUnhealthy, NodeActive}
+	if !reflect.DeepEqual(states, expected) {
+		t.Errorf("Expected %v but got %v", expected, states)
+	}
+
+	rv.AddWithWeight("e", 0)
+	var active []string
+	for _, info := range rv.Nodes() {
+		if info.Active() {
+			active = append(active, info.Name)
+		}
+	}
+	if !reflect.DeepEqual(active, []string{"a", "d"}) {
+		t.Errorf("Expected a and d to be active but got %v", active)
+	}
+
+	info := rv.Nodes()[3]
+	info.Tags[ZoneTag] = "z2"
+	if rv.Tags("d")[ZoneTag] != "z1" {
+		t.Errorf("Expected the tags to be copies")
+	}
+}
@@ -0,0 +1,86 @@
+package rendezvous
+
+import (
+	"context"
+)
+
+// A LookupObserver is notified of the node chosen by every Lookup,
+// LookupBytes and LookupHash call, see Ring.ObserveLookups. Observers are
+// called on the lookup path and must be cheap and safe for concurrent use.
+type LookupObserver interface {
+	// ObserveLookup is called with the chosen node, or "" if the ring has
+	// no active node.
+	ObserveLookup(node string)
+}
+
+// ObserveLookups registers o to be notified of lookups. Rings without
+// observers only pay for a nil check per lookup. The returned function
+// unregisters o.
+func (r *Ring) ObserveLookups(o LookupObserver) (unregister func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// observers is copied on write, since lookups read it without locking.
+	entry := &observer{o}
+	observers := &[]*observer{entry}
+	if current := r.observers.Load(); current != nil {
+		*observers = append(*observers, *current...)
+	}
+	r.observers.Store(observers)
+
+	return func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		current := r.observers.Load()
+		if current == nil {
+			return
+		}
+		remaining := make([]*observer, 0, len(*current))
+		for _, other := range *current {
+			if other != entry {
+				remaining = append(remaining, other)
+			}
+		}
+		if len(remaining) == 0 {
+			r.observers.Store(nil)
+		} else {
+			r.observers.Store(&remaining)
+		}
+	}
+}
+
+func (r *Ring) observeLookup(node string) {
+	if observers := r.observers.Load(); observers != nil {
+		for _, o := range *observers {
+			o.ObserveLookup(node)
+		}
+	}
+}
+
+// A LookupTracer instruments context-aware lookups, see WithLookupTracer and
+// LookupContext, for example to record them in distributed traces.
+type LookupTracer interface {
+	// StartLookup is called before key is looked up and returns a function
+	// that is called with the chosen node, or "" if the ring has no active
+	// node.
+	StartLookup(ctx context.Context, key string) (end func(node string))
+}
+
+// LookupContext is like Lookup but reports the lookup to the ring's
+// LookupTracer, if any, as part of the operation in ctx.
+func (r *Ring) LookupContext(ctx context.Context, key string) string {
+	if r.opts.tracer == nil {
+		return r.Lookup(key)
+	}
+	end := r.opts.tracer.StartLookup(ctx, key)
+	node := r.Lookup(key)
+	end(node)
+	return node
+}
+
+// An observer wraps a LookupObserver so that it can be unregistered by
+// identity, even if its dynamic type is not comparable.
+type observer struct {
+	LookupObserver
+}
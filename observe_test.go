@@ -0,0 +1,68 @@
+package rendezvous
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type observerFunc func(node string)
+
+func (f observerFunc) ObserveLookup(node string) { f(node) }
+
+func TestRing_ObserveLookups(t *testing.T) {
+	rv := New()
+
+	var first, second []string
+	unregister := rv.ObserveLookups(observerFunc(func(node string) {
+		first = append(first, node)
+	}))
+	rv.ObserveLookups(observerFunc(func(node string) {
+		second = append(second, node)
+	}))
+
+	rv.Lookup("key")
+	rv.Add("a")
+	rv.LookupBytes([]byte("key"))
+	rv.LookupHash(rv.Hash("key"))
+	rv.LookupTopN("key", 1)
+	unregister()
+	rv.Lookup("key")
+
+	if expected := []string{"", "a", "a"}; !reflect.DeepEqual(expected, first) {
+		t.Errorf("Expected %v but got %v", expected, first)
+	}
+	if expected := []string{"", "a", "a", "a"}; !reflect.DeepEqual(expected, second) {
+		t.Errorf("Expected %v but got %v", expected, second)
+	}
+}
+
+type tracerFunc func(ctx context.Context, key string) func(node string)
+
+func (f tracerFunc) StartLookup(ctx context.Context, key string) func(node string) {
+	return f(ctx, key)
+}
+
+func TestRing_LookupContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	var traced []string
+	tracer := tracerFunc(func(ctx context.Context, key string) func(node string) {
+		return func(node string) {
+			traced = append(traced, ctx.Value(ctxKey{}).(string)+" "+key+" "+node)
+		}
+	})
+
+	rv := New(WithLookupTracer(tracer), WithNodes("a"))
+	if node := rv.LookupContext(ctx, "key"); node != "a" {
+		t.Errorf("Expected a but got %s", node)
+	}
+	if expected := []string{"request key a"}; !reflect.DeepEqual(expected, traced) {
+		t.Errorf("Expected %v but got %v", expected, traced)
+	}
+
+	if node := New(WithNodes("a")).LookupContext(ctx, "key"); node != "a" {
+		t.Errorf("Expected a but got %s", node)
+	}
+}
@@ -0,0 +1,322 @@
+package rendezvous
+
+import (
+	stdhash "hash"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// A ScoreFunc computes the rendezvous score of a node with the given hash
+// and weight for a key hash. Nodes are ranked from highest to lowest score.
+type ScoreFunc func(keyHash, nodeHash uint64, nodeWeight float64) float64
+
+// An Option configures a Ring created by New.
+type Option func(*options)
+
+type options struct {
+	hasher     func(string) uint64
+	hasherName string
+	seed       uint64
+	seeded     bool
+	score      ScoreFunc
+	// defaultScore is set while score is computeScore, which rankings of
+	// many nodes inline.
+	defaultScore bool
+	nodes        []string
+
+	loadFactor float64
+	reporter   LoadReporter
+	tracer     LookupTracer
+	logger     *slog.Logger
+	ttlDrain   bool
+	slowStart  time.Duration
+	replicas   int
+	slots      int
+	backend    Backend
+	skeleton   int
+	parallel   int
+	capacity   int
+	cacheSize  int
+	fallback   string
+	probes     int
+}
+
+func defaultOptions() *options {
+	return &options{
+		hasher:       xxhash.Sum64String,
+		hasherName:   defaultHasherName,
+		score:        computeScore,
+		defaultScore: true,
+		replicas:     1,
+	}
+}
+
+// WithHash hashes keys and node names with the given hash.Hash64. Since a
+// hash.Hash64 carries state, every hash computation is serialized; prefer
+// WithHasher for concurrent workloads.
+func WithHash(hash stdhash.Hash64) Option {
+	var mutex sync.Mutex
+	return WithHasher(func(s string) uint64 {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		hash.Reset()
+		_, _ = io.WriteString(hash, s)
+		return hash.Sum64()
+	})
+}
+
+// WithHasher hashes keys and node names with the given stateless hash
+// function, which must be safe for concurrent use. The default is xxHash;
+// the hashers subpackages provide alternatives.
+func WithHasher(hasher func(string) uint64) Option {
+	return func(o *options) {
+		if hasher != nil {
+			o.hasher = hasher
+			o.hasherName = ""
+		}
+	}
+}
+
+// WithNamedHasher hashes keys and node names with the hash function
+// registered under name, see RegisterHasher. Unlike WithHasher, the name is
+// recorded so that encoded rings decode with the same hash function. It
+// panics if no hash function is registered under name.
+func WithNamedHasher(name string) Option {
+	hasher, ok := registeredHasher(name)
+	if !ok {
+		panic("rendezvous: unknown hasher " + name)
+	}
+	return func(o *options) {
+		o.hasher = hasher
+		o.hasherName = name
+	}
+}
+
+// WithSeed derives all key and node hashes from seed, so rings with the same
+// membership but different seeds produce independent key to node mappings.
+//
+// Seeding decorrelates placements but does not remove collisions of the
+// underlying hash function; rings exposed to adversarial keys should also use
+// a keyed hash function.
+func WithSeed(seed uint64) Option {
+	return func(o *options) {
+		o.seed = seed
+		o.seeded = true
+	}
+}
+
+// seededHasher returns the configured hash function with the seed, if any, mixed
+// into every hash.
+func (o *options) seededHasher() func(string) uint64 {
+	if !o.seeded {
+		return o.hasher
+	}
+	hasher, seed := o.hasher, o.seed
+	return func(s string) uint64 {
+		return mix64(hasher(s) ^ seed)
+	}
+}
+
+// WithScoreFunc replaces the default weighted score formula.
+func WithScoreFunc(score ScoreFunc) Option {
+	return func(o *options) {
+		if score != nil {
+			o.score = score
+			o.defaultScore = false
+		}
+	}
+}
+
+// WithLoadFactor bounds the load Acquire assigns to each node to factor times
+// the node's weighted share of the total load, rounded up. The factor must be
+// greater than 1; values closer to 1 balance better but move more keys away
+// from their preferred node.
+func WithLoadFactor(factor float64) Option {
+	return func(o *options) {
+		if factor > 1 {
+			o.loadFactor = factor
+		}
+	}
+}
+
+// WithLoadReporter makes load-aware lookups such as LookupLeastLoaded consult
+// reporter for the current load of candidate nodes.
+func WithLoadReporter(reporter LoadReporter) Option {
+	return func(o *options) {
+		o.reporter = reporter
+	}
+}
+
+// WithLookupTracer reports lookups made with LookupContext to tracer. Rings
+// without a tracer do no tracing work.
+func WithLookupTracer(tracer LookupTracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithLogger logs nodes being added, removed, reweighted, drained,
+// activated and changing health to logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithTTLDrain drains nodes that miss their TTL instead of removing them, see
+// AddWithTTL, so that they keep their membership until a heartbeat puts them
+// back into rotation.
+func WithTTLDrain() Option {
+	return func(o *options) {
+		o.ttlDrain = true
+	}
+}
+
+// WithSlowStart ramps the weight nodes are scored with from 0 to their
+// weight over d after they join the ring, so that cold caches warm up before
+// taking their full share of keys. Weight still reports the full weight.
+func WithSlowStart(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.slowStart = d
+		}
+	}
+}
+
+// WithReplicationFactor sets the number of replicas of every key, as
+// returned by Replicas; the default is 1.
+func WithReplicationFactor(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.replicas = n
+		}
+	}
+}
+
+// WithSlotTable routes Lookup and every other method returning a key's
+// owner, such as Owns, LookupExcluding, LookupBatch and PlanRemoval, through
+// a table of slots, such as 16384, precomputed on every membership change: a
+// key belongs to slot keyHash % slots, and the slot to its highest ranked
+// node. Lookups become a table index instead of scoring every node, at the
+// price of memory and slower writes. Keys of a slot always move together, so
+// placements differ from those of a ring without the table. Rankings such
+// as LookupTopN keep scoring nodes and may start with another node.
+func WithSlotTable(slots int) Option {
+	return func(o *options) {
+		if slots > 0 {
+			o.slots = slots
+		}
+	}
+}
+
+// WithBackend routes Lookup and every other method returning a key's owner,
+// such as Owns, LookupExcluding, LookupBatch and PlanRemoval, with backend
+// instead of rendezvous hashing, see Backend. Rankings such as LookupTopN
+// keep ranking nodes by score.
+func WithBackend(backend Backend) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// WithSkeleton routes Lookup and every other method returning a key's
+// owner, such as Owns, LookupExcluding, LookupBatch and PlanRemoval, through
+// a skeleton tree for rings expected to grow to about size nodes,
+// such as 100000: nodes are placed in a fixed tree of clusters of 16 by
+// their hash, and lookups descend it by ranking clusters by their total
+// weight, scoring O(log size) clusters instead of every node. Keys of
+// drained and unhealthy nodes move to the other nodes of their cluster, so
+// only their own keys move; adding or removing nodes also moves some keys
+// between the sibling clusters of the changed one. Placements differ from
+// those of a ring without the tree, size fixes its shape and must not
+// change, and rankings such as LookupTopN keep scoring every node.
+func WithSkeleton(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.skeleton = size
+		}
+	}
+}
+
+// WithParallelScoring splits the scoring of rings of at least size nodes,
+// such as 100000, across up to GOMAXPROCS goroutines and merges their best
+// nodes, so that lookups on very large rings use every core. Smaller rings
+// are scored on the calling goroutine, where the cost of starting
+// goroutines would outweigh the gain.
+func WithParallelScoring(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.parallel = size
+		}
+	}
+}
+
+// WithCapacity reserves storage for capacity nodes when the ring is
+// created, so adding up to that many nodes, one at a time or in batches,
+// does not allocate every node on its own. Every write still publishes a new
+// snapshot of the membership; add known nodes with AddAll or WithNodes to
+// build a ring in a single write.
+func WithCapacity(capacity int) Option {
+	return func(o *options) {
+		if capacity > 0 {
+			o.capacity = capacity
+		}
+	}
+}
+
+// WithLookupCache caches the nodes Lookup returns for up to size recently
+// used keys. The cache is emptied whenever the ring's version or pins
+// change, so it never returns a node the ring would not, and is worth it for
+// workloads looking up a small set of hot keys while the membership is
+// stable. Cached lookups serialize on a mutex; see LookupCacheStats.
+func WithLookupCache(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.cacheSize = size
+		}
+	}
+}
+
+// WithFallback makes Lookup, LookupBytes, LookupHash, LookupKey and
+// LookupExcluding return the named fallback, such as a catch-all shard,
+// instead of "" when the ring is empty or no node may be selected. The
+// fallback need not be a member of the ring; LookupExcluding returns "" if it
+// is excluded, so retry loops with Next still end.
+func WithFallback(name string) Option {
+	return func(o *options) {
+		o.fallback = name
+	}
+}
+
+// WithHealthCheckConcurrency limits CheckHealth and RunHealthChecks to n
+// probes at a time; the default is 64, so that large rings are not probed
+// all at once.
+func WithHealthCheckConcurrency(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.probes = n
+		}
+	}
+}
+
+// WithNodes populates the ring with the named nodes at the default weight.
+func WithNodes(names ...string) Option {
+	return func(o *options) {
+		o.nodes = append(o.nodes, names...)
+	}
+}
+
+// mix64 is the splitmix64 finalizer, a bijective mix of all 64 bits.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
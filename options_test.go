@@ -0,0 +1,116 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func otherHasher(s string) uint64 {
+	return ^xxhash.Sum64String(s)
+}
+
+func TestNew_Options(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		rv := New()
+		if rv.computeHash("foo") != xxhash.Sum64String("foo") {
+			t.Errorf("Expected xxHash to be the default hasher")
+		}
+		if rv.Len() != 0 {
+			t.Errorf("Expected an empty ring but got %v", rv.List())
+		}
+	})
+
+	t.Run("WithHash", func(t *testing.T) {
+		rv := New(WithHash(xxhash.New()))
+		if rv.computeHash("foo") != xxhash.Sum64String("foo") {
+			t.Errorf("Expected WithHash to set the hasher")
+		}
+	})
+
+	t.Run("WithHasher", func(t *testing.T) {
+		rv := New(WithHasher(otherHasher))
+		if rv.computeHash("foo") != otherHasher("foo") {
+			t.Errorf("Expected WithHasher to set the hasher")
+		}
+
+		rv = New(WithHasher(nil))
+		if rv.computeHash("foo") != xxhash.Sum64String("foo") {
+			t.Errorf("Expected WithHasher(nil) to keep the default hasher")
+		}
+	})
+
+	t.Run("WithNodes", func(t *testing.T) {
+		rv := New(WithNodes("c", "a"), WithNodes("b"))
+		expected := []string{"a", "b", "c"}
+		if names := rv.List(); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v but got %v", expected, names)
+		}
+	})
+
+	t.Run("WithNodesUsesFinalHasher", func(t *testing.T) {
+		rv := New(WithNodes("a"), WithHasher(otherHasher))
+		if n, _ := rv.get("a"); n.hash != otherHasher("a") {
+			t.Errorf("Expected node hash to use the configured hasher")
+		}
+	})
+
+	t.Run("WithScoreFunc", func(t *testing.T) {
+		// Ranks purely by weight, ignoring the hashes.
+		byWeight := func(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+			return nodeWeight
+		}
+		rv := New(WithScoreFunc(byWeight))
+		for i := 1; i <= 5; i++ {
+			rv.AddWithWeight("n"+strconv.Itoa(i), float64(i))
+		}
+
+		expected := []string{"n5", "n4", "n3", "n2", "n1"}
+		if names := rv.LookupAll("foo"); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v but got %v", expected, names)
+		}
+		if score, _ := rv.Score("foo", "n3"); score != 3 {
+			t.Errorf("Expected 3 but got %v", score)
+		}
+	})
+}
+
+func TestNew_WithSeed(t *testing.T) {
+	names := make([]string, 10)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+
+	unseeded := New(WithNodes(names...))
+	seeded1 := New(WithNodes(names...), WithSeed(1))
+	seeded1b := New(WithNodes(names...), WithSeed(1))
+	seeded2 := New(WithNodes(names...), WithSeed(2))
+
+	differs := func(a, b *Ring) int {
+		n := 0
+		for i := 0; i < 1000; i++ {
+			key := "k" + strconv.Itoa(i)
+			if a.Lookup(key) != b.Lookup(key) {
+				n++
+			}
+		}
+		return n
+	}
+
+	if n := differs(seeded1, seeded1b); n != 0 {
+		t.Errorf("Expected equal seeds to agree but %d keys differ", n)
+	}
+	// With 10 nodes, independent mappings agree on about 10% of the keys.
+	if n := differs(seeded1, seeded2); n < 800 {
+		t.Errorf("Expected different seeds to be decorrelated but only %d keys differ", n)
+	}
+	if n := differs(unseeded, seeded1); n < 800 {
+		t.Errorf("Expected seeded ring to be decorrelated but only %d keys differ", n)
+	}
+
+	if keyHash := seeded1.Hash("foo"); seeded1.LookupHash(keyHash) != seeded1.Lookup("foo") {
+		t.Errorf("Expected Hash to include the seed")
+	}
+}
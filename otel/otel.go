@@ -0,0 +1,163 @@
+// Package otel instruments rendezvous.Ring lookups and changes with
+// OpenTelemetry traces and metrics.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/mosuka/rendezvous"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scope = "github.com/mosuka/rendezvous/otel"
+
+// Attribute keys recorded on spans and metrics.
+const (
+	NodeKey    = attribute.Key("rendezvous.node")
+	LookupKey  = attribute.Key("rendezvous.key")
+	ChangeKey  = attribute.Key("rendezvous.change")
+	VersionKey = attribute.Key("rendezvous.version")
+)
+
+// An Instrumentation is a rendezvous.LookupTracer recording a span and the
+// latency of every LookupContext call, and counting ring changes.
+type Instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	changes  metric.Int64Counter
+	attrs    []attribute.KeyValue
+	withKey  bool
+	withNode bool
+}
+
+// An Option configures an Instrumentation.
+type Option func(*options)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	attrs          []attribute.KeyValue
+	withKey        bool
+	withNode       bool
+}
+
+// WithTracerProvider records spans with provider instead of the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records metrics with provider instead of the global one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = provider
+	}
+}
+
+// WithAttributes adds attributes to all spans and metrics, for example to
+// tell several rings apart.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(o *options) {
+		o.attrs = append(o.attrs, attrs...)
+	}
+}
+
+// WithKeyAttribute records looked up keys on spans. Keys are not recorded by
+// default, since they may be sensitive.
+func WithKeyAttribute() Option {
+	return func(o *options) {
+		o.withKey = true
+	}
+}
+
+// WithNodeMetricAttribute records the chosen node on the lookup duration
+// metric. Nodes are always recorded on spans, but on metrics every node
+// becomes a time series of its own, which large or churning rings cannot
+// afford.
+func WithNodeMetricAttribute() Option {
+	return func(o *options) {
+		o.withNode = true
+	}
+}
+
+// New returns an Instrumentation. Pass it to rendezvous.WithLookupTracer and
+// call Instrument to count changes of the ring.
+func New(opts ...Option) (*Instrumentation, error) {
+	o := &options{
+		tracerProvider: otelapi.GetTracerProvider(),
+		meterProvider:  otelapi.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	meter := o.meterProvider.Meter(scope)
+	duration, err := meter.Float64Histogram("rendezvous.lookup.duration",
+		metric.WithDescription("Duration of ring lookups."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	changes, err := meter.Int64Counter("rendezvous.ring.changes",
+		metric.WithDescription("Number of nodes added to, removed from or changed on the ring."),
+		metric.WithUnit("{node}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumentation{
+		tracer:   o.tracerProvider.Tracer(scope),
+		duration: duration,
+		changes:  changes,
+		attrs:    o.attrs,
+		withKey:  o.withKey,
+		withNode: o.withNode,
+	}, nil
+}
+
+// StartLookup implements rendezvous.LookupTracer.
+func (i *Instrumentation) StartLookup(ctx context.Context, key string) func(node string) {
+	ctx, span := i.tracer.Start(ctx, "rendezvous.Lookup",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(i.attrs...))
+	if i.withKey {
+		span.SetAttributes(LookupKey.String(key))
+	}
+	start := time.Now()
+
+	return func(node string) {
+		elapsed := time.Since(start)
+		span.SetAttributes(NodeKey.String(node))
+		span.End()
+
+		attrs := i.attrs
+		if i.withNode {
+			attrs = append(attrs[:len(attrs):len(attrs)], NodeKey.String(node))
+		}
+		i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+// Instrument counts the changes of ring and records every change as an event
+// on a span of its own, so that ring churn shows up next to the requests it
+// affects. The returned function stops recording.
+func (i *Instrumentation) Instrument(ring *rendezvous.Ring) (unregister func()) {
+	return ring.OnChange(func(e rendezvous.ChangeEvent) {
+		attrs := append(i.attrs[:len(i.attrs):len(i.attrs)],
+			ChangeKey.String(e.Type.String()))
+		i.changes.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+
+		_, span := i.tracer.Start(context.Background(), "rendezvous.Change",
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(attrs...))
+		span.AddEvent("node "+e.Type.String(), trace.WithAttributes(
+			NodeKey.String(e.Name()),
+			VersionKey.Int64(int64(e.Version))))
+		span.End()
+	})
+}
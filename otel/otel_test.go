@@ -0,0 +1,116 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentation(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	inst, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithAttributes(attribute.String("ring", "test")),
+		WithKeyAttribute(),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	ring := rendezvous.New(rendezvous.WithLookupTracer(inst))
+	unregister := inst.Instrument(ring)
+	ring.AddAll([]string{"a", "b"})
+	ring.Remove("b")
+	unregister()
+	ring.Add("c")
+
+	ctx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	node := ring.LookupContext(ctx, "key")
+	parent.End()
+
+	ended := spans.Ended()
+	if len(ended) != 4 {
+		t.Fatalf("Expected 4 spans but got %d", len(ended))
+	}
+	for _, span := range ended[:3] {
+		if span.Name() != "rendezvous.Change" || len(span.Events()) != 1 {
+			t.Errorf("Expected a change span with an event but got %s", span.Name())
+		}
+	}
+	if event := ended[2].Events()[0]; event.Name != "node removed" {
+		t.Errorf("Expected node removed but got %s", event.Name)
+	}
+
+	lookup := ended[3]
+	if lookup.Name() != "rendezvous.Lookup" {
+		t.Errorf("Expected rendezvous.Lookup but got %s", lookup.Name())
+	}
+	if lookup.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected the lookup span to be a child of the request span")
+	}
+	attrs := attribute.NewSet(lookup.Attributes()...)
+	if v, _ := attrs.Value(NodeKey); v.AsString() != node {
+		t.Errorf("Expected node %s but got %s", node, v.AsString())
+	}
+	if v, _ := attrs.Value(LookupKey); v.AsString() != "key" {
+		t.Errorf("Expected key but got %s", v.AsString())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	var changes int64
+	for _, dp := range metrics["rendezvous.ring.changes"].Data.(metricdata.Sum[int64]).DataPoints {
+		changes += dp.Value
+	}
+	if changes != 3 {
+		t.Errorf("Expected 3 changes but got %d", changes)
+	}
+	durations := metrics["rendezvous.lookup.duration"].Data.(metricdata.Histogram[float64]).DataPoints
+	if len(durations) != 1 || durations[0].Count != 1 {
+		t.Fatalf("Expected a single lookup duration but got %v", durations)
+	}
+	if _, ok := durations[0].Attributes.Value(NodeKey); ok {
+		t.Errorf("Expected no node attribute on the duration metric")
+	}
+}
+
+func TestWithNodeMetricAttribute(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := New(
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithNodeMetricAttribute(),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	ring := rendezvous.New(rendezvous.WithLookupTracer(inst), rendezvous.WithNodes("a", "b"))
+	node := ring.LookupContext(context.Background(), "key")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	durations := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64]).DataPoints
+	if v, _ := durations[0].Attributes.Value(NodeKey); v.AsString() != node {
+		t.Errorf("Expected node %s but got %s", node, v.AsString())
+	}
+}
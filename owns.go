@@ -0,0 +1,13 @@
+package rendezvous
+
+// Owns reports whether the named node owns key, that is whether Lookup
+// returns it, for example to reject misrouted requests. Unless the key is
+// routed by a pin, slot table, backend or skeleton tree, it stops scoring as
+// soon as another node outscores the named one. It never allocates.
+func (r *Ring) Owns(node, key string) bool {
+	keyHash := r.computeHash(key)
+	if n, ok := r.routedOwner(r.load(), keyHash); ok {
+		return n != nil && n.name == node
+	}
+	return r.ranksWithin(node, keyHash, 1)
+}
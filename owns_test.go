@@ -0,0 +1,96 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRing_Owns(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d"))
+	rv.Drain("b")
+	rv.Pin("7", "d")
+
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		owner := rv.Lookup(key)
+		for _, name := range []string{"a", "b", "c", "d", "z"} {
+			if rv.Owns(name, key) != (name == owner) {
+				t.Fatalf("Expected Owns(%s, %s) to be %v", name, key, name == owner)
+			}
+		}
+	}
+	if New().Owns("a", "foo") {
+		t.Errorf("Expected an empty ring to own nothing")
+	}
+}
+
+func BenchmarkRing_Owns(b *testing.B) {
+	rv := New()
+	names := make([]string, 10000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.Owns("n1", "k"+strconv.Itoa(i%1000))
+	}
+}
+
+// testOwnersAgree fails unless every method returning a key's owner agrees
+// with Lookup, as routing by pins, slot tables, backends and skeleton trees
+// must preserve.
+func testOwnersAgree(t *testing.T, rv *Ring) {
+	t.Helper()
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+	batch := rv.LookupBatch(keys)
+	owned := make(map[string]int)
+	for i, key := range keys {
+		owner := rv.Lookup(key)
+		owned[owner]++
+		if !rv.Owns(owner, key) {
+			t.Fatalf("Expected %s to own %s", owner, key)
+		}
+		if batch[i] != owner {
+			t.Fatalf("Expected LookupBatch to return %s for %s but got %s", owner, key, batch[i])
+		}
+		if node := rv.LookupExcluding(key); node != owner {
+			t.Fatalf("Expected LookupExcluding to return %s for %s but got %s", owner, key, node)
+		}
+		if node := rv.Next(key); node != owner {
+			t.Fatalf("Expected Next to return %s for %s but got %s", owner, key, node)
+		}
+		if node := rv.LookupExcluding(key, owner); node == owner {
+			t.Fatalf("Expected LookupExcluding not to return excluded %s", owner)
+		}
+	}
+
+	for _, b := range rv.AnalyzeBalance(keys).Nodes {
+		if b.Keys != owned[b.Name] {
+			t.Fatalf("Expected AnalyzeBalance to assign %d keys to %s but got %d", owned[b.Name], b.Name, b.Keys)
+		}
+	}
+
+	removed := batch[0]
+	after := rv.Clone()
+	after.Remove(removed)
+	planned := make(map[string]string)
+	for _, m := range rv.PlanRemoval(removed, keys) {
+		planned[m.Key] = m.To
+	}
+	for _, key := range keys {
+		to, ok := planned[key]
+		if owner := rv.Lookup(key); ok != (owner == removed) {
+			t.Fatalf("Expected %s owned by %s to be planned: %v", key, owner, owner == removed)
+		}
+		if expected := after.Lookup(key); ok && to != expected {
+			t.Fatalf("Expected %s to be planned to move to %s but got %s", key, expected, to)
+		}
+	}
+}
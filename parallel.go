@@ -0,0 +1,60 @@
+package rendezvous
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelMinShard is the smallest number of nodes worth a goroutine of its
+// own.
+const parallelMinShard = 8192
+
+// topNParallel is like topNInto but selects the best nodes of shards of the
+// snapshot on separate goroutines and merges them.
+func (r *Ring) topNParallel(buf []ScoredNode, nodes []*Node, cols columns, keyHash uint64, n int, accept func(*Node) bool) []ScoredNode {
+	shards := runtime.GOMAXPROCS(0)
+	if limit := len(nodes) / parallelMinShard; shards > limit {
+		shards = limit
+	}
+	if shards <= 1 {
+		return r.topNShard(buf, nodes, cols, keyHash, n, accept)
+	}
+
+	results := make([][]ScoredNode, shards)
+	var wg sync.WaitGroup
+	for i := range results {
+		lo, hi := len(nodes)*i/shards, len(nodes)*(i+1)/shards
+		size := n
+		if size > hi-lo {
+			size = hi - lo
+		}
+		shard := cols
+		if cols.hashes != nil {
+			shard = cols.slice(lo, hi)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.topNShard(make([]ScoredNode, 0, size), nodes[lo:hi], shard, keyHash, n, accept)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every shard is ordered by rank, so merging their heads
+	// yields the best n overall.
+	h := buf[:0]
+	for len(h) < n {
+		best := -1
+		for i, result := range results {
+			if len(result) > 0 && (best < 0 || ranksAbove(result[0], results[best][0])) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		h = append(h, results[best][0])
+		results[best] = results[best][1:]
+	}
+	return h
+}
@@ -0,0 +1,53 @@
+package rendezvous
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestWithParallelScoring(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	names := make([]string, 50000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	serial, parallel := New(), New(WithParallelScoring(1000))
+	serial.AddAll(names)
+	parallel.AddAll(names)
+	serial.Drain("n1")
+	parallel.Drain("n1")
+
+	for i := 0; i < 20; i++ {
+		key := strconv.Itoa(i)
+		if expected, node := serial.Lookup(key), parallel.Lookup(key); node != expected {
+			t.Fatalf("Expected %s but got %s", expected, node)
+		}
+		if expected, nodes := serial.LookupTopN(key, 5), parallel.LookupTopN(key, 5); !reflect.DeepEqual(nodes, expected) {
+			t.Fatalf("Expected %v but got %v", expected, nodes)
+		}
+	}
+	if expected, nodes := serial.LookupAll("foo"), parallel.LookupAll("foo"); !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("Expected the rankings to agree")
+	}
+}
+
+func BenchmarkRing_LookupTopN_Parallel(b *testing.B) {
+	names := make([]string, 500000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	for _, size := range []int{0, 100000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			rv := New(WithParallelScoring(size))
+			rv.AddAll(names)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rv.LookupTopN("k"+strconv.Itoa(i), 3)
+			}
+		})
+	}
+}
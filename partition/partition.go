@@ -0,0 +1,113 @@
+// Package partition assigns a fixed number of partitions to the members of a
+// rendezvous.Ring, for services that shard by partition rather than by key.
+//
+// Partition p is owned by the node the ring looks up for the key
+// strconv.Itoa(p), so partition owners honor weights, drained nodes and pins
+// like any other lookup.
+package partition
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// A Table maps the partitions 0 to Count()-1 to the nodes of a ring. It
+// caches the assignment until the ring's version changes, so Owner is a
+// slice index between membership changes. Pins, which do not change the
+// version, take effect with the next membership change. A Table is safe for
+// concurrent use.
+type Table struct {
+	ring   *rendezvous.Ring
+	keys   []string
+	cached atomic.Pointer[assignment]
+}
+
+type assignment struct {
+	version uint64
+	owners  []string
+}
+
+// A Movement is a partition changing owner.
+type Movement struct {
+	Partition int
+	From, To  string
+}
+
+// New returns a Table of count partitions over ring. It panics if count is
+// not positive.
+func New(ring *rendezvous.Ring, count int) *Table {
+	if count <= 0 {
+		panic("partition: count must be positive")
+	}
+	keys := make([]string, count)
+	for p := range keys {
+		keys[p] = strconv.Itoa(p)
+	}
+	return &Table{ring: ring, keys: keys}
+}
+
+// Count returns the number of partitions.
+func (t *Table) Count() int {
+	return len(t.keys)
+}
+
+// Partition returns the partition of key.
+func (t *Table) Partition(key string) int {
+	return int(t.ring.Hash(key) % uint64(len(t.keys)))
+}
+
+// Owner returns the node owning partition, or "" if the partition is out of
+// range or no node can serve it.
+func (t *Table) Owner(partition int) string {
+	if partition < 0 || partition >= len(t.keys) {
+		return ""
+	}
+	return t.load().owners[partition]
+}
+
+// Assignment returns the owner of every partition, indexed by partition.
+func (t *Table) Assignment() []string {
+	owners := t.load().owners
+	assignment := make([]string, len(owners))
+	copy(assignment, owners)
+	return assignment
+}
+
+// Partitions returns the partitions owned by the named node, in increasing
+// order.
+func (t *Table) Partitions(node string) []int {
+	var partitions []int
+	for p, owner := range t.load().owners {
+		if owner == node {
+			partitions = append(partitions, p)
+		}
+	}
+	return partitions
+}
+
+// load returns the assignment of the ring's current version, computing it
+// against a single membership snapshot if needed.
+func (t *Table) load() *assignment {
+	version := t.ring.Version()
+	if a := t.cached.Load(); a != nil && a.version == version {
+		return a
+	}
+	a := &assignment{version: version, owners: t.ring.LookupBatch(t.keys)}
+	t.cached.Store(a)
+	return a
+}
+
+// Diff returns the partitions whose owner differs between two assignments
+// of the same table, such as the ones returned by Assignment before and
+// after a membership change.
+func Diff(before, after []string) []Movement {
+	var movements []Movement
+	for p := range before {
+		if p < len(after) && before[p] != after[p] {
+			movements = append(movements, Movement{Partition: p, From: before[p], To: after[p]})
+		}
+	}
+	return movements
+}
@@ -0,0 +1,68 @@
+package partition
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestTable(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b", "c"))
+	table := New(ring, 1024)
+
+	before := table.Assignment()
+	for p := 0; p < table.Count(); p++ {
+		if owner := table.Owner(p); owner != ring.Lookup(strconv.Itoa(p)) || owner != before[p] {
+			t.Fatalf("Expected partition %d to be owned by %s but got %s", p, ring.Lookup(strconv.Itoa(p)), owner)
+		}
+	}
+	if table.Owner(-1) != "" || table.Owner(1024) != "" {
+		t.Errorf("Expected no owner of out of range partitions")
+	}
+
+	total := 0
+	for _, name := range []string{"a", "b", "c"} {
+		partitions := table.Partitions(name)
+		if len(partitions) < 250 {
+			t.Errorf("Expected about a third of the partitions for %s but got %d", name, len(partitions))
+		}
+		total += len(partitions)
+	}
+	if total != 1024 {
+		t.Errorf("Expected every partition to be owned once but got %d", total)
+	}
+
+	// Adding a node only moves partitions to it.
+	ring.Add("d")
+	movements := Diff(before, table.Assignment())
+	if len(movements) < 200 || len(movements) > 320 {
+		t.Errorf("Expected about a quarter of the partitions to move but got %d", len(movements))
+	}
+	for _, m := range movements {
+		if m.To != "d" || m.From != before[m.Partition] {
+			t.Fatalf("Expected moves to d but got %+v", m)
+		}
+	}
+	if !reflect.DeepEqual(Diff(before, before), []Movement(nil)) {
+		t.Errorf("Expected no movements")
+	}
+}
+
+func TestTable_Partition(t *testing.T) {
+	table := New(rendezvous.New(), 16)
+	counts := make([]int, 16)
+	for i := 0; i < 1600; i++ {
+		p := table.Partition(strconv.Itoa(i))
+		if p != table.Partition(strconv.Itoa(i)) {
+			t.Fatalf("Expected a stable partition")
+		}
+		counts[p]++
+	}
+	for p, count := range counts {
+		if count < 50 {
+			t.Errorf("Expected about 100 keys in partition %d but got %d", p, count)
+		}
+	}
+}
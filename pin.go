@@ -0,0 +1,83 @@
+package rendezvous
+
+import "sort"
+
+// A pin routes a key to a node regardless of scores.
+type pin struct {
+	key  string
+	node string
+}
+
+// Pin routes key to the named node, overriding its rendezvous ranking, for
+// example to move a hot key to a dedicated node. Lookup, LookupBytes,
+// LookupNode and LookupBatch return the pinned node whenever it is a member
+// that is neither drained nor unhealthy, and fall back to the ranking
+// otherwise. Pins do not change the ring's version.
+func (r *Ring) Pin(key, node string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pins := r.loadPins()
+	updated := make(map[uint64]pin, len(pins)+1)
+	for h, p := range pins {
+		updated[h] = p
+	}
+	updated[r.computeHash(key)] = pin{key: key, node: node}
+	r.pins.Store(&updated)
+}
+
+// Unpin removes the pin of key and reports whether there was one.
+func (r *Ring) Unpin(key string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pins := r.loadPins()
+	keyHash := r.computeHash(key)
+	if _, ok := pins[keyHash]; !ok {
+		return false
+	}
+	updated := make(map[uint64]pin, len(pins)-1)
+	for h, p := range pins {
+		if h != keyHash {
+			updated[h] = p
+		}
+	}
+	r.pins.Store(&updated)
+	return true
+}
+
+// Pins returns the pinned keys and their nodes.
+func (r *Ring) Pins() map[string]string {
+	pins := r.loadPins()
+	m := make(map[string]string, len(pins))
+	for _, p := range pins {
+		m[p.key] = p.node
+	}
+	return m
+}
+
+func (r *Ring) loadPins() map[uint64]pin {
+	if pins := r.pins.Load(); pins != nil {
+		return *pins
+	}
+	return nil
+}
+
+// pinned returns the node of the snapshot keyHash is pinned to, if lookups
+// may return it. Pins are keyed by hash so that lookups of []byte keys need
+// not convert them.
+func (r *Ring) pinned(nodes []*Node, keyHash uint64) (*Node, bool) {
+	pins := r.loadPins()
+	if len(pins) == 0 {
+		return nil, false
+	}
+	p, ok := pins[keyHash]
+	if !ok {
+		return nil, false
+	}
+	ix := sort.Search(len(nodes), cmp(nodes, p.node))
+	if ix == len(nodes) || nodes[ix].name != p.node || !active(nodes[ix]) {
+		return nil, false
+	}
+	return nodes[ix], true
+}
@@ -0,0 +1,49 @@
+package rendezvous
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_Pin(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	all := rv.LookupAll("foo")
+	version := rv.Version()
+
+	rv.Pin("foo", all[2])
+	if node := rv.Lookup("foo"); node != all[2] {
+		t.Errorf("Expected the pinned node %s but got %s", all[2], node)
+	}
+	if node := rv.LookupBytes([]byte("foo")); node != all[2] {
+		t.Errorf("Expected the pinned node %s but got %s", all[2], node)
+	}
+	if node := rv.LookupNode("foo"); node.Name() != all[2] {
+		t.Errorf("Expected the pinned node %s but got %s", all[2], node.Name())
+	}
+	if names := rv.LookupBatch([]string{"foo"}); names[0] != all[2] {
+		t.Errorf("Expected the pinned node %s but got %s", all[2], names[0])
+	}
+	if !reflect.DeepEqual(rv.Pins(), map[string]string{"foo": all[2]}) {
+		t.Errorf("Expected the pin to be listed but got %v", rv.Pins())
+	}
+	if rv.Version() != version {
+		t.Errorf("Expected pins not to change the version")
+	}
+	if node := rv.Clone().Lookup("foo"); node != all[2] {
+		t.Errorf("Expected clones to keep pins")
+	}
+
+	// Pins to nodes that cannot serve fall back to the ranking.
+	rv.Drain(all[2])
+	if node := rv.Lookup("foo"); node != all[0] {
+		t.Errorf("Expected %s but got %s", all[0], node)
+	}
+	rv.Activate(all[2])
+
+	if !rv.Unpin("foo") || rv.Unpin("foo") {
+		t.Errorf("Expected Unpin to report the pin once")
+	}
+	if node := rv.Lookup("foo"); node != all[0] {
+		t.Errorf("Expected %s but got %s", all[0], node)
+	}
+}
@@ -0,0 +1,38 @@
+package rendezvous
+
+import "sync"
+
+// Pools of the scratch slices lookups rank nodes in before returning names,
+// so that sustained lookup traffic reuses them instead of producing garbage.
+var (
+	scoredNodePool = sync.Pool{New: func() any { return new([]ScoredNode) }}
+	batchEntryPool = sync.Pool{New: func() any { return new([]batchEntry) }}
+)
+
+// getScoredNodes returns an empty pooled slice.
+func getScoredNodes() *[]ScoredNode {
+	return scoredNodePool.Get().(*[]ScoredNode)
+}
+
+// putScoredNodes returns the storage of used to the pool p came from,
+// dropping its node pointers so that pooled slices do not keep removed nodes
+// alive.
+func putScoredNodes(p *[]ScoredNode, used []ScoredNode) {
+	clear(used)
+	*p = used[:0]
+	scoredNodePool.Put(p)
+}
+
+// getBatchEntries returns an empty pooled slice with room for n entries.
+func getBatchEntries(n int) *[]batchEntry {
+	p := batchEntryPool.Get().(*[]batchEntry)
+	if cap(*p) < n {
+		*p = make([]batchEntry, 0, n)
+	}
+	return p
+}
+
+func putBatchEntries(p *[]batchEntry, used []batchEntry) {
+	*p = used[:0]
+	batchEntryPool.Put(p)
+}
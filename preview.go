@@ -0,0 +1,160 @@
+package rendezvous
+
+import (
+	"sort"
+)
+
+// A Preview is a what-if view of a ring: hypothetical changes applied to it
+// are kept in a small overlay on top of the ring's membership at the time of
+// Preview, so neither the ring is copied nor its live state modified. A
+// Preview is not safe for concurrent use.
+type Preview struct {
+	ring *Ring
+	base []*Node
+	// overlay holds the changed nodes by name; nil marks a removed node.
+	overlay map[string]*Node
+	// added holds the overlay nodes that are not removed.
+	added []*Node
+
+	// On rings routing keys through a slot table, backend or skeleton tree,
+	// routed is a scratch ring of the hypothetical nodes, built with a copy
+	// of backend, or nil until a lookup needs it.
+	routes  bool
+	backend Backend
+	routed  *Ring
+}
+
+// Preview returns a what-if view of the ring's current membership.
+func (r *Ring) Preview() *Preview {
+	// The backend is copied in the state it built the current nodes in.
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return &Preview{
+		ring:    r,
+		base:    r.load(),
+		overlay: make(map[string]*Node),
+		routes:  r.opts.slots > 0 || r.opts.backend != nil || r.opts.skeleton > 0,
+		backend: copyBackend(r.opts.backend),
+	}
+}
+
+// Add hypothetically adds the named node with the default weight.
+func (p *Preview) Add(name string) {
+	p.AddWithWeight(name, defaultWeight)
+}
+
+// AddWithWeight hypothetically adds the named node or changes its weight.
+func (p *Preview) AddWithWeight(name string, weight float64) {
+	n := p.ring.newNode(name)
+	if current := p.get(name); current != nil {
+		copied := *current
+		n = &copied
+	}
+	n.weight = weight
+	p.set(name, n)
+}
+
+// Remove hypothetically removes the named node.
+func (p *Preview) Remove(name string) {
+	p.set(name, nil)
+}
+
+// Lookup returns the node owning key after the hypothetical changes.
+func (p *Preview) Lookup(key string) string {
+	return p.lookup(p.ring.computeHash(key))
+}
+
+// Movements reports the keys whose owner differs between the ring, as of
+// Preview, and the hypothetical changes.
+func (p *Preview) Movements(keys []string) []Movement {
+	movements := make([]Movement, 0)
+	for _, key := range keys {
+		keyHash := p.ring.computeHash(key)
+
+		var from string
+		if owner := p.ring.owner(p.base, keyHash); owner != nil {
+			from = owner.name
+		}
+		if to := p.lookup(keyHash); from != to {
+			movements = append(movements, Movement{Key: key, From: from, To: to})
+		}
+	}
+	return movements
+}
+
+// lookup returns the owner of keyHash among the hypothetical nodes, routed
+// like the ring's lookups, see Ring.owner.
+func (p *Preview) lookup(keyHash uint64) string {
+	if p.routes {
+		if n := p.routedRing().lookupNode(keyHash); n != nil {
+			return n.name
+		}
+		return ""
+	}
+	if pin, ok := p.ring.loadPins()[keyHash]; ok {
+		if n := p.get(pin.node); n != nil && active(n) {
+			return n.name
+		}
+	}
+
+	var baseBuf, addedBuf [1]ScoredNode
+	base := p.ring.topNInto(baseBuf[:0], p.base, keyHash, 1, func(n *Node) bool {
+		_, changed := p.overlay[n.name]
+		return active(n) && !changed
+	})
+	added := p.ring.topNInto(addedBuf[:0], p.added, keyHash, 1, active)
+
+	switch {
+	case len(added) > 0 && (len(base) == 0 || ranksAbove(added[0], base[0])):
+		return added[0].node.name
+	case len(base) > 0:
+		return base[0].node.name
+	default:
+		return ""
+	}
+}
+
+// get returns the named node after the hypothetical changes, or nil.
+func (p *Preview) get(name string) *Node {
+	if n, ok := p.overlay[name]; ok {
+		return n
+	}
+	if ix := sort.Search(len(p.base), cmp(p.base, name)); ix < len(p.base) && p.base[ix].name == name {
+		return p.base[ix]
+	}
+	return nil
+}
+
+// routedRing returns the scratch ring of the hypothetical nodes.
+func (p *Preview) routedRing() *Ring {
+	if p.routed != nil {
+		return p.routed
+	}
+
+	nodes := make([]*Node, 0, len(p.base)+len(p.added))
+	for _, n := range p.base {
+		if _, changed := p.overlay[n.name]; !changed {
+			nodes = append(nodes, n)
+		}
+	}
+	nodes = append(nodes, p.added...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+
+	// Every build starts from the state of the backend at Preview.
+	p.routed = p.ring.scratch(nodes, copyBackend(p.backend))
+	return p.routed
+}
+
+func (p *Preview) set(name string, n *Node) {
+	p.overlay[name] = n
+	p.routed = nil
+	p.added = p.added[:0]
+	for _, n := range p.overlay {
+		if n != nil {
+			p.added = append(p.added, n)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Preview(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d"))
+	version := rv.Version()
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	p := rv.Preview()
+	p.Remove("b")
+	p.AddWithWeight("c", 2)
+	p.Add("e")
+	p.Add("f")
+	p.Remove("f")
+
+	expected := rv.Clone()
+	expected.Remove("b")
+	expected.AddWithWeight("c", 2)
+	expected.Add("e")
+
+	for _, key := range keys {
+		if owner := p.Lookup(key); owner != expected.Lookup(key) {
+			t.Errorf("Expected %s but got %s", expected.Lookup(key), owner)
+		}
+	}
+	if movements := p.Movements(keys); !reflect.DeepEqual(movements, Diff(rv, expected, keys)) {
+		t.Errorf("Expected movements to match Diff")
+	}
+
+	if rv.Version() != version || rv.Len() != 4 || rv.Weight("c") != 1 {
+		t.Errorf("Expected the ring to be unchanged")
+	}
+
+	// Changes to the ring after Preview do not affect it.
+	rv.Remove("a")
+	if movements := rv.Preview().Movements(keys); len(movements) != 0 {
+		t.Errorf("Expected no movements but got %d", len(movements))
+	}
+	if owner := p.Lookup(keys[0]); owner != expected.Lookup(keys[0]) {
+		t.Errorf("Expected %s but got %s", expected.Lookup(keys[0]), owner)
+	}
+}
+
+func TestRing_Preview_Routed(t *testing.T) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	tests := map[string][]Option{
+		"Pins":     {WithNodes("a", "b", "c", "d")},
+		"Slots":    {WithNodes("a", "b", "c", "d"), WithSlotTable(256)},
+		"Backend":  {WithNodes("a", "b", "c", "d"), WithBackend(&orderBackend{})},
+		"Skeleton": {WithNodes("a", "b", "c", "d"), WithSkeleton(64)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			rv := New(opts...)
+			rv.Pin(keys[0], "b")
+			rv.Pin(keys[1], "e")
+
+			p := rv.Preview()
+			p.Remove("c")
+			p.AddWithWeight("e", 4)
+
+			expected := rv.Clone()
+			expected.Remove("c")
+			expected.AddWithWeight("e", 4)
+
+			for _, key := range keys {
+				if owner := p.Lookup(key); owner != expected.Lookup(key) {
+					t.Fatalf("Expected %s on %s but got %s", key, expected.Lookup(key), owner)
+				}
+			}
+			if p.Lookup(keys[0]) != "b" || p.Lookup(keys[1]) != "e" {
+				t.Errorf("Expected pinned keys to stay pinned")
+			}
+			if movements := p.Movements(keys); !reflect.DeepEqual(movements, Diff(rv, expected, keys)) {
+				t.Errorf("Expected movements to match Diff")
+			}
+
+			// Lookups of the preview do not touch the ring's backend.
+			before := rv.LookupBatch(keys)
+			rv.AddWithWeight("a", defaultWeight)
+			if !reflect.DeepEqual(rv.LookupBatch(keys), before) {
+				t.Errorf("Expected the ring's lookups to be unchanged")
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package prometheus exports metrics of a rendezvous.Ring to Prometheus.
+package prometheus
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mosuka/rendezvous"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// A Collector is a prometheus.Collector exposing the size, total weight,
+// version and membership changes of a ring, and optionally lookup counts per
+// node.
+type Collector struct {
+	ring *rendezvous.Ring
+
+	nodes   *prom.Desc
+	weight  *prom.Desc
+	version *prom.Desc
+	changes *prom.Desc
+	lookups *prom.Desc
+
+	added, removed, changed atomic.Uint64
+
+	sampleRate uint64
+	sampled    atomic.Uint64
+	counts     sync.Map // node name to *atomic.Uint64
+
+	unregister []func()
+}
+
+// An Option configures a Collector.
+type Option func(*options)
+
+type options struct {
+	namespace   string
+	constLabels prom.Labels
+	sampleRate  uint64
+}
+
+// WithNamespace prefixes all metric names with namespace.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithConstLabels adds labels to all metrics, for example to tell several
+// rings apart.
+func WithConstLabels(labels prom.Labels) Option {
+	return func(o *options) {
+		o.constLabels = labels
+	}
+}
+
+// WithLookupCounts counts lookups per node, sampling one of every rate
+// lookups and scaling the counts accordingly. A rate of 1 counts every
+// lookup.
+func WithLookupCounts(rate uint64) Option {
+	return func(o *options) {
+		if rate > 0 {
+			o.sampleRate = rate
+		}
+	}
+}
+
+// NewCollector returns a Collector for ring. Register it with a
+// prometheus.Registerer and call Close when the ring is no longer collected.
+func NewCollector(ring *rendezvous.Ring, opts ...Option) *Collector {
+	o := &options{namespace: "rendezvous"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	desc := func(name, help string, labels ...string) *prom.Desc {
+		return prom.NewDesc(prom.BuildFQName(o.namespace, "ring", name), help, labels, o.constLabels)
+	}
+	c := &Collector{
+		ring:       ring,
+		nodes:      desc("nodes", "Number of nodes in the ring by state: active, or else drained, unhealthy or zero_weight.", "state"),
+		weight:     desc("weight", "Total weight of the nodes in the ring."),
+		version:    desc("version", "Version of the ring, increased by every change."),
+		changes:    desc("membership_changes_total", "Number of nodes added, removed or changed.", "type"),
+		lookups:    desc("lookups_total", "Number of lookups per chosen node.", "node"),
+		sampleRate: o.sampleRate,
+	}
+
+	c.unregister = append(c.unregister, ring.OnChange(c.observeChange))
+	if c.sampleRate > 0 {
+		c.unregister = append(c.unregister, ring.ObserveLookups(c))
+	}
+	return c
+}
+
+// Close stops observing the ring.
+func (c *Collector) Close() {
+	for _, unregister := range c.unregister {
+		unregister()
+	}
+	c.unregister = nil
+}
+
+func (c *Collector) observeChange(e rendezvous.ChangeEvent) {
+	switch e.Type {
+	case rendezvous.NodeAdded:
+		c.added.Add(1)
+	case rendezvous.NodeRemoved:
+		c.removed.Add(1)
+		c.counts.Delete(e.Name())
+	case rendezvous.NodeChanged:
+		c.changed.Add(1)
+	}
+}
+
+// ObserveLookup implements rendezvous.LookupObserver.
+func (c *Collector) ObserveLookup(node string) {
+	if node == "" || c.sampled.Add(1)%c.sampleRate != 0 {
+		return
+	}
+	count, ok := c.counts.Load(node)
+	if !ok {
+		count, _ = c.counts.LoadOrStore(node, new(atomic.Uint64))
+	}
+	count.(*atomic.Uint64).Add(c.sampleRate)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.nodes
+	ch <- c.weight
+	ch <- c.version
+	ch <- c.changes
+	if c.sampleRate > 0 {
+		ch <- c.lookups
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	version := c.ring.Version()
+
+	// Nodes reads a single snapshot, so the counts agree with each other.
+	var active, drained, unhealthy, zeroWeight int
+	var weight float64
+	for _, info := range c.ring.Nodes() {
+		switch {
+		case info.Active():
+			active++
+		case info.State == rendezvous.NodeDrained:
+			drained++
+		case info.State == rendezvous.NodeUnhealthy:
+			unhealthy++
+		default:
+			zeroWeight++
+		}
+		weight += info.Weight
+	}
+
+	ch <- prom.MustNewConstMetric(c.nodes, prom.GaugeValue, float64(active), "active")
+	ch <- prom.MustNewConstMetric(c.nodes, prom.GaugeValue, float64(drained), "drained")
+	ch <- prom.MustNewConstMetric(c.nodes, prom.GaugeValue, float64(unhealthy), "unhealthy")
+	ch <- prom.MustNewConstMetric(c.nodes, prom.GaugeValue, float64(zeroWeight), "zero_weight")
+	ch <- prom.MustNewConstMetric(c.weight, prom.GaugeValue, weight)
+	ch <- prom.MustNewConstMetric(c.version, prom.GaugeValue, float64(version))
+	ch <- prom.MustNewConstMetric(c.changes, prom.CounterValue, float64(c.added.Load()), "added")
+	ch <- prom.MustNewConstMetric(c.changes, prom.CounterValue, float64(c.removed.Load()), "removed")
+	ch <- prom.MustNewConstMetric(c.changes, prom.CounterValue, float64(c.changed.Load()), "changed")
+
+	if c.sampleRate > 0 {
+		c.counts.Range(func(node, count any) bool {
+			ch <- prom.MustNewConstMetric(c.lookups, prom.CounterValue, float64(count.(*atomic.Uint64).Load()), node.(string))
+			return true
+		})
+	}
+}
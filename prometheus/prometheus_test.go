@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a", "b"))
+	c := NewCollector(ring, WithLookupCounts(1), WithConstLabels(prom.Labels{"ring": "test"}))
+	defer c.Close()
+
+	ring.AddWithWeight("c", 2)
+	ring.Drain("b")
+	ring.Remove("a")
+	ring.AddWithWeight("d", 0)
+	ring.AddWithWeight("e", 1)
+	ring.SetHealthy("e", false)
+	for i := 0; i < 10; i++ {
+		ring.Lookup("key")
+	}
+
+	owner := ring.Lookup("key")
+	expected := `
+# HELP rendezvous_ring_lookups_total Number of lookups per chosen node.
+# TYPE rendezvous_ring_lookups_total counter
+rendezvous_ring_lookups_total{node="` + owner + `",ring="test"} 11
+# HELP rendezvous_ring_membership_changes_total Number of nodes added, removed or changed.
+# TYPE rendezvous_ring_membership_changes_total counter
+rendezvous_ring_membership_changes_total{ring="test",type="added"} 3
+rendezvous_ring_membership_changes_total{ring="test",type="changed"} 2
+rendezvous_ring_membership_changes_total{ring="test",type="removed"} 1
+# HELP rendezvous_ring_nodes Number of nodes in the ring by state: active, or else drained, unhealthy or zero_weight.
+# TYPE rendezvous_ring_nodes gauge
+rendezvous_ring_nodes{ring="test",state="active"} 1
+rendezvous_ring_nodes{ring="test",state="drained"} 1
+rendezvous_ring_nodes{ring="test",state="unhealthy"} 1
+rendezvous_ring_nodes{ring="test",state="zero_weight"} 1
+# HELP rendezvous_ring_version Version of the ring, increased by every change.
+# TYPE rendezvous_ring_version gauge
+rendezvous_ring_version{ring="test"} 7
+# HELP rendezvous_ring_weight Total weight of the nodes in the ring.
+# TYPE rendezvous_ring_weight gauge
+rendezvous_ring_weight{ring="test"} 4
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollector_Sampling(t *testing.T) {
+	ring := rendezvous.New(rendezvous.WithNodes("a"))
+	c := NewCollector(ring, WithLookupCounts(4))
+	defer c.Close()
+
+	for i := 0; i < 10; i++ {
+		ring.Lookup("key")
+	}
+	c.Close()
+	ring.Lookup("key")
+
+	count, _ := c.counts.Load("a")
+	if n := count.(*atomic.Uint64).Load(); n != 8 {
+		t.Errorf("Expected 8 but got %d", n)
+	}
+}
@@ -0,0 +1,267 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// Protobuf wire types, see proto/rendezvous.proto for the schema.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MarshalProto encodes the ring as a rendezvous.v1.Ring protobuf message, see
+// proto/rendezvous.proto.
+func (r *Ring) MarshalProto() ([]byte, error) {
+	var buf, node, entry []byte
+
+	if version := r.Version(); version != 0 {
+		buf = appendProtoVarint(buf, 1, version)
+	}
+	if r.opts.hasherName != "" {
+		buf = appendProtoString(buf, 2, r.opts.hasherName)
+	}
+	if r.opts.seeded {
+		buf = appendProtoVarint(buf, 3, r.opts.seed)
+	}
+	for _, n := range r.load() {
+		node = appendProtoString(node[:0], 1, n.name)
+		node = appendProtoTag(node, 2, wireFixed64)
+		node = binary.LittleEndian.AppendUint64(node, math.Float64bits(n.weight))
+		for _, k := range sortedKeys(n.tags) {
+			entry = appendProtoString(entry[:0], 1, k)
+			entry = appendProtoString(entry, 2, n.tags[k])
+			node = appendProtoBytes(node, 3, entry)
+		}
+		if n.drained {
+			node = appendProtoVarint(node, 4, 1)
+		}
+		if n.capacity != 0 {
+			node = appendProtoVarint(node, 5, uint64(n.capacity))
+		}
+		node = appendProtoTag(node, 6, wireFixed64)
+		node = binary.LittleEndian.AppendUint64(node, n.hash)
+
+		buf = appendProtoBytes(buf, 4, node)
+	}
+	return buf, nil
+}
+
+// UnmarshalProto replaces the ring's membership and hashing with an encoded
+// rendezvous.v1.Ring message. Encoded node hashes are trusted; nodes without
+// one, and all nodes of a ring without a hasher name, see UnmarshalBinary,
+// are hashed by name. Like UnmarshalJSON, it may be called on a zero Ring,
+// and returns ErrHashingChanged for a ring hashed differently than one with
+// nodes.
+func (r *Ring) UnmarshalProto(data []byte) error {
+	var (
+		hasherName string
+		seed       *uint64
+		version    uint64
+		nodes      []*Node
+		hashed     []bool
+	)
+	err := rangeProto(data, func(field int, d *decoder) {
+		switch field {
+		case 1:
+			version = d.protoVarint()
+		case 2:
+			hasherName = d.protoString()
+		case 3:
+			s := d.protoVarint()
+			seed = &s
+		case 4:
+			n, ok, err := unmarshalProtoNode(d.protoBytes())
+			if err != nil && d.err == nil {
+				d.err = err
+			}
+			nodes = append(nodes, n)
+			hashed = append(hashed, ok)
+		default:
+			d.skip()
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	opts, err := r.decodedOptions(hasherName, seed)
+	if err != nil {
+		return err
+	}
+	hasher := opts.seededHasher()
+	for i, n := range nodes {
+		if !hashed[i] || hasherName == "" {
+			n.hash = hasher(n.name)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+	if err := checkSorted(nodes); err != nil {
+		return err
+	}
+
+	if err := r.setHashing(opts); err != nil {
+		return err
+	}
+	r.store(nodes)
+	r.restoreVersion(version)
+
+	return nil
+}
+
+// unmarshalProtoNode decodes a rendezvous.v1.Node message and reports whether
+// it carried a hash.
+func unmarshalProtoNode(data []byte) (*Node, bool, error) {
+	n := &Node{load: new(atomic.Int64)}
+	hashed := false
+	err := rangeProto(data, func(field int, d *decoder) {
+		switch field {
+		case 1:
+			n.name = d.protoString()
+		case 2:
+			n.weight = math.Float64frombits(d.protoFixed64())
+		case 3:
+			var k, v string
+			err := rangeProto(d.protoBytes(), func(field int, d *decoder) {
+				switch field {
+				case 1:
+					k = d.protoString()
+				case 2:
+					v = d.protoString()
+				default:
+					d.skip()
+				}
+			})
+			if err != nil && d.err == nil {
+				d.err = err
+			}
+			if n.tags == nil {
+				n.tags = make(map[string]string)
+			}
+			n.tags[k] = v
+		case 4:
+			n.drained = d.protoVarint() != 0
+		case 5:
+			n.capacity = int64(d.protoVarint())
+		case 6:
+			n.hash, hashed = d.protoFixed64(), true
+		default:
+			d.skip()
+		}
+	})
+	return n, hashed, err
+}
+
+// rangeProto calls fn for every field of a protobuf message with the decoder
+// positioned at the field's value. fn must consume the value, see
+// decoder.skip.
+func rangeProto(data []byte, fn func(field int, d *decoder)) error {
+	d := &decoder{buf: data}
+	for len(d.buf) > 0 && d.err == nil {
+		tag := d.uvarint()
+		d.wire = int(tag & 7)
+		if field := tag >> 3; field > 0 && field <= math.MaxInt32 {
+			fn(int(field), d)
+		} else if d.err == nil {
+			d.err = fmt.Errorf("rendezvous: invalid protobuf field %d", field)
+		}
+	}
+	return d.err
+}
+
+// expect records an error unless the current field has the given wire type.
+func (d *decoder) expect(wire int) bool {
+	if d.err == nil && d.wire != wire {
+		d.err = fmt.Errorf("rendezvous: unexpected protobuf wire type %d", d.wire)
+	}
+	return d.err == nil
+}
+
+func (d *decoder) protoVarint() uint64 {
+	if !d.expect(wireVarint) {
+		return 0
+	}
+	return d.uvarint()
+}
+
+func (d *decoder) protoFixed64() uint64 {
+	if !d.expect(wireFixed64) {
+		return 0
+	}
+	return d.uint64()
+}
+
+func (d *decoder) protoBytes() []byte {
+	if !d.expect(wireBytes) {
+		return nil
+	}
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.buf)) {
+		d.err = errShortBuffer
+		return nil
+	}
+	return d.next(int(n))
+}
+
+func (d *decoder) protoString() string {
+	return string(d.protoBytes())
+}
+
+// skip consumes a value of an unknown field.
+func (d *decoder) skip() {
+	switch d.wire {
+	case wireVarint:
+		d.uvarint()
+	case wireFixed64:
+		d.next(8)
+	case wireBytes:
+		d.protoBytes()
+	case wireFixed32:
+		d.next(4)
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("rendezvous: unsupported protobuf wire type %d", d.wire)
+		}
+	}
+}
+
+func appendProtoTag(buf []byte, field int, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(field)<<3|uint64(wire))
+}
+
+func appendProtoVarint(buf []byte, field int, v uint64) []byte {
+	buf = appendProtoTag(buf, field, wireVarint)
+	return binary.AppendUvarint(buf, v)
+}
+
+func appendProtoBytes(buf []byte, field int, b []byte) []byte {
+	buf = appendProtoTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func appendProtoString(buf []byte, field int, s string) []byte {
+	buf = appendProtoTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
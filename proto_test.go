@@ -0,0 +1,138 @@
+package rendezvous
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_MarshalProto(t *testing.T) {
+	rv := New(WithSeed(42), WithNodes("a", "b", "d"))
+	rv.AddWithTags("c", 2.5, map[string]string{"zone": "z1", "rack": "r1"})
+	rv.Drain("b")
+	rv.SetCapacity("a", 10)
+
+	data, err := rv.MarshalProto()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	var decoded Ring
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if expected, actual := rv.LookupAll(key), decoded.LookupAll(key); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+
+	json, _ := rv.MarshalJSON()
+	decodedJSON, _ := decoded.MarshalJSON()
+	if string(json) != string(decodedJSON) {
+		t.Errorf("Expected %s but got %s", json, decodedJSON)
+	}
+}
+
+func TestRing_UnmarshalProto(t *testing.T) {
+	t.Run("WithoutHashes", func(t *testing.T) {
+		// A message as another language would encode it: fields out of
+		// order, an unknown field and no node hashes.
+		var b, node, entry []byte
+		node = appendProtoVarint(node, 4, 1)
+		node = appendProtoString(node, 1, "b")
+		node = appendProtoTag(node, 2, wireFixed64)
+		node = append(node, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f)
+		node = appendProtoVarint(node, 99, 7)
+		entry = appendProtoString(entry, 1, "zone")
+		entry = appendProtoString(entry, 2, "z1")
+		node = appendProtoBytes(node, 3, entry)
+		b = appendProtoBytes(b, 4, node)
+		b = appendProtoVarint(b, 1, 3)
+		node = appendProtoString(nil, 1, "a")
+		node = appendProtoTag(node, 2, wireFixed64)
+		node = append(node, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f)
+		b = appendProtoBytes(b, 4, node)
+		b = appendProtoString(b, 2, "xxhash")
+
+		var rv Ring
+		if err := rv.UnmarshalProto(b); err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+			t.Errorf("Expected [a b] but got %v", names)
+		}
+		if !rv.Drained("b") || rv.Tags("b")["zone"] != "z1" {
+			t.Errorf("Expected node b to be decoded")
+		}
+
+		expected := New(WithNodes("a", "b"))
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			if actual := rv.LookupAllHash(expected.Hash(key)); !reflect.DeepEqual(actual, expected.LookupAll(key)) {
+				t.Errorf("Expected %v but got %v", expected.LookupAll(key), actual)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		data, _ := New(WithNodes("a", "b")).MarshalProto()
+
+		rv := New(WithNodes("x"))
+		for _, data := range [][]byte{
+			data[:len(data)-1],
+			appendProtoVarint(nil, 2, 1),
+			appendProtoString(nil, 2, "nope"),
+			append(data, data...),
+		} {
+			if err := rv.UnmarshalProto(data); err == nil {
+				t.Errorf("Expected an error for %x", data)
+			}
+		}
+		if names := rv.List(); !reflect.DeepEqual(names, []string{"x"}) {
+			t.Errorf("Expected a failed decode to keep the ring but got %v", names)
+		}
+
+		seeded, _ := New(WithSeed(42), WithNodes("c")).MarshalProto()
+		if err := rv.UnmarshalProto(seeded); !errors.Is(err, ErrHashingChanged) {
+			t.Errorf("Expected ErrHashingChanged but got %v", err)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		rv := New(WithNodes("a"))
+		data, _ := New(WithNodes("b", "c")).MarshalProto()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 1000; i++ {
+				rv.Lookup("k" + strconv.Itoa(i))
+			}
+		}()
+		for i := 0; i < 10; i++ {
+			if err := rv.UnmarshalProto(data); err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		}
+		<-done
+	})
+}
+
+func TestRing_UnmarshalProto_UnnamedHasher(t *testing.T) {
+	data, _ := New(WithHasher(otherHasher), WithNodes("a", "b", "c")).MarshalProto()
+
+	rv := New()
+	if err := rv.UnmarshalProto(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	fresh := New(WithNodes("a", "b", "c"))
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		if owner, expected := rv.Lookup(key), fresh.Lookup(key); owner != expected {
+			t.Fatalf("Expected %s for %s but got %s", expected, key, owner)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package rendezvous
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A Member describes a node as reported by service discovery, see Reconcile.
+type Member struct {
+	Name string
+	// Weight is the node's weight. Zero means the default weight unless
+	// HasWeight is set, which makes the node a member that is never
+	// selected, see Ring.AddWithWeight.
+	Weight    float64
+	HasWeight bool
+	Tags      map[string]string
+}
+
+// Reconcile makes members the ring's membership under a single write: missing
+// nodes are added, nodes that are not members are removed and the weights and
+// tags of the others are updated. Remaining nodes keep their states, loads
+// and payloads, and nodes whose weight and tags are unchanged are left
+// untouched, so watchers only see actual changes. If a name occurs more than
+// once, the last member wins.
+//
+// If any member's weight is invalid, see CheckWeight, Reconcile returns
+// ErrInvalidWeight and leaves the ring unchanged.
+func (r *Ring) Reconcile(members []Member) error {
+	for _, m := range members {
+		if err := CheckWeight(m.Weight); err != nil {
+			return fmt.Errorf("%w of node %q", err, m.Name)
+		}
+	}
+
+	sorted := make([]Member, len(members))
+	copy(sorted, members)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nameBytes := 0
+	for _, m := range sorted {
+		nameBytes += len(m.Name)
+	}
+	arena := r.arena(len(sorted), nameBytes)
+
+	nodes := r.load()
+	reconciled := make([]*Node, 0, len(sorted))
+	changed := false
+	i := 0
+	for j, m := range sorted {
+		if j+1 < len(sorted) && sorted[j+1].Name == m.Name {
+			continue
+		}
+		weight := m.Weight
+		if weight == 0 && !m.HasWeight {
+			weight = defaultWeight
+		}
+
+		for i < len(nodes) && nodes[i].name < m.Name {
+			i++
+			changed = true
+		}
+		if i < len(nodes) && nodes[i].name == m.Name {
+			if n := nodes[i]; n.weight == weight && tagsEqual(n.tags, m.Tags) {
+				reconciled = append(reconciled, n)
+			} else {
+				updated := arena.copyNode(n)
+				updated.weight = weight
+				updated.tags = copyTags(m.Tags)
+				reconciled = append(reconciled, updated)
+				changed = true
+			}
+			i++
+			continue
+		}
+
+		n := arena.newNode(r, m.Name)
+		n.weight = weight
+		n.tags = copyTags(m.Tags)
+		reconciled = append(reconciled, n)
+		changed = true
+	}
+
+	if changed || i < len(nodes) {
+		r.store(reconciled)
+	}
+	return nil
+}
+
+func tagsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package rendezvous
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Reconcile(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.Drain("b")
+
+	var events []string
+	rv.OnChange(func(e ChangeEvent) {
+		events = append(events, e.Type.String()+" "+e.Name())
+	})
+
+	rv.Reconcile([]Member{
+		{Name: "d", Weight: 2},
+		{Name: "b", Weight: 3},
+		{Name: "a"},
+		{Name: "d", Weight: 4, Tags: map[string]string{"zone": "z1"}},
+	})
+
+	if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "b", "d"}) {
+		t.Errorf("Expected [a b d] but got %v", names)
+	}
+	if rv.Weight("b") != 3 || !rv.Drained("b") {
+		t.Errorf("Expected b to be reweighted and stay drained")
+	}
+	if rv.Weight("d") != 4 || rv.Tags("d")["zone"] != "z1" {
+		t.Errorf("Expected the last member d to win")
+	}
+	if expected := []string{"changed b", "removed c", "added d"}; !reflect.DeepEqual(expected, events) {
+		t.Errorf("Expected %v but got %v", expected, events)
+	}
+
+	version := rv.Version()
+	rv.Reconcile([]Member{
+		{Name: "a", Weight: 1},
+		{Name: "b", Weight: 3},
+		{Name: "d", Weight: 4, Tags: map[string]string{"zone": "z1"}},
+	})
+	if rv.Version() != version {
+		t.Errorf("Expected an unchanged membership to keep the version")
+	}
+
+	rv.Reconcile(nil)
+	if rv.Len() != 0 {
+		t.Errorf("Expected an empty ring but got %v", rv.List())
+	}
+}
+
+func TestRing_Reconcile_ZeroWeight(t *testing.T) {
+	rv := New()
+	rv.Reconcile([]Member{{Name: "a"}, {Name: "b", Weight: 0, HasWeight: true}})
+	if rv.Weight("a") != 1 || rv.Weight("b") != 0 || !rv.Contains("b") {
+		t.Errorf("Expected weights 1 and 0 but got %v and %v", rv.Weight("a"), rv.Weight("b"))
+	}
+	for i := 0; i < 100; i++ {
+		if node := rv.Lookup(strconv.Itoa(i)); node != "a" {
+			t.Fatalf("Expected a but got %s", node)
+		}
+	}
+}
+
+func TestRing_Reconcile_InvalidWeight(t *testing.T) {
+	for _, weight := range []float64{-1, math.NaN(), math.Inf(1)} {
+		rv := New(WithNodes("a", "b"))
+		version := rv.Version()
+
+		err := rv.Reconcile([]Member{{Name: "a"}, {Name: "c"}, {Name: "d", Weight: weight}})
+		if !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("Expected %v for %v but got %v", ErrInvalidWeight, weight, err)
+		}
+		if rv.Version() != version || !reflect.DeepEqual(rv.List(), []string{"a", "b"}) {
+			t.Errorf("Expected the ring to be unchanged but got %v", rv.List())
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package rendezvous
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const defaultHasherName = "xxhash"
+
+var (
+	hashersMutex sync.RWMutex
+	hashers      = map[string]func(string) uint64{
+		defaultHasherName: xxhash.Sum64String,
+	}
+)
+
+// RegisterHasher makes a stateless hash function available by name to
+// WithNamedHasher and to decoding. The hashers subpackages register their
+// unkeyed hash functions when imported. It panics if name is empty, hasher is
+// nil or name is already registered.
+func RegisterHasher(name string, hasher func(string) uint64) {
+	hashersMutex.Lock()
+	defer hashersMutex.Unlock()
+
+	if name == "" || hasher == nil {
+		panic("rendezvous: invalid hasher registration")
+	}
+	if _, dup := hashers[name]; dup {
+		panic("rendezvous: RegisterHasher called twice for " + name)
+	}
+	hashers[name] = hasher
+}
+
+// Hashers returns the names of the registered hash functions in ascending
+// order.
+func Hashers() []string {
+	hashersMutex.RLock()
+	defer hashersMutex.RUnlock()
+
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func registeredHasher(name string) (func(string) uint64, bool) {
+	hashersMutex.RLock()
+	defer hashersMutex.RUnlock()
+
+	hasher, ok := hashers[name]
+	return hasher, ok
+}
@@ -1,11 +1,15 @@
 package rendezvous
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/cespare/xxhash/v2"
 )
@@ -26,8 +30,81 @@ func TestRing_Remove(t *testing.T) {
 	}
 
 	rv.Remove("d")
-	if len(rv.nodes) != 2 {
-		t.Errorf("Removing a non-existent node unexpectedly altered nodes: %v", rv.nodes)
+	if len(rv.load()) != 2 {
+		t.Errorf("Removing a non-existent node unexpectedly altered nodes: %v", rv.load())
+	}
+}
+
+func TestRing_TryRemove(t *testing.T) {
+	rv := New(WithNodes("a", "c"))
+	rv.AddWithTags("b", 2, map[string]string{"zone": "z1"})
+
+	n, ok := rv.TryRemove("b")
+	if !ok || n.Name() != "b" || n.Weight() != 2 || n.Tags()["zone"] != "z1" {
+		t.Errorf("Expected the removed node b but got %v, %v", n, ok)
+	}
+	if rv.Contains("b") {
+		t.Errorf("Expected b to be removed")
+	}
+
+	version := rv.Version()
+	if n, ok := rv.TryRemove("b"); ok || n != nil {
+		t.Errorf("Expected no node to be removed but got %v", n)
+	}
+	if rv.Version() != version {
+		t.Errorf("Expected no write")
+	}
+}
+
+func TestRing_RemoveWhere(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d"))
+	rv.Drain("b")
+	rv.Drain("d")
+
+	version := rv.Version()
+	removed := rv.RemoveWhere((*Node).Drained)
+	if !reflect.DeepEqual(removed, []string{"b", "d"}) {
+		t.Errorf("Expected b and d to be removed but got %v", removed)
+	}
+	if names := rv.List(); !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("Expected a and c to remain but got %v", names)
+	}
+	if rv.Version() != version+1 {
+		t.Errorf("Expected a single write")
+	}
+
+	if removed := rv.RemoveWhere(func(*Node) bool { return false }); removed != nil || rv.Version() != version+1 {
+		t.Errorf("Expected no write when nothing matches")
+	}
+}
+
+func TestRing_Clear(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := rv.Watch(ctx)
+
+	version := rv.Version()
+	rv.Clear()
+	if rv.Len() != 0 || rv.Lookup("k") != "" {
+		t.Errorf("Expected an empty ring")
+	}
+	if rv.Version() != version+1 {
+		t.Errorf("Expected a single write")
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if e := <-events; e.Type != NodeRemoved || e.Name() != name || e.Version != version+1 {
+			t.Errorf("Expected %s to be removed but got %+v", name, e)
+		}
+	}
+
+	rv.Clear()
+	if rv.Version() != version+1 {
+		t.Errorf("Expected no write for an empty ring")
+	}
+	rv.Add("d")
+	if rv.Lookup("k") != "d" {
+		t.Errorf("Expected the ring to be reusable")
 	}
 }
 
@@ -40,8 +117,8 @@ func TestRing_Add(t *testing.T) {
 		rv.Add("b")
 		rv.Add("a")
 
-		names := make([]string, len(rv.nodes))
-		for i, n := range rv.nodes {
+		names := make([]string, len(rv.load()))
+		for i, n := range rv.load() {
 			names[i] = n.name
 		}
 
@@ -55,7 +132,7 @@ func TestRing_Add(t *testing.T) {
 		rv.Add("a")
 		rv.Add("a")
 
-		if len(rv.nodes) != 1 {
+		if len(rv.load()) != 1 {
 			t.Errorf("Expected Add() to detect and filter duplicate node names")
 		}
 	})
@@ -67,12 +144,12 @@ func TestRing_AddWithWeight(t *testing.T) {
 		rv.AddWithWeight("a", 1.0)
 		rv.AddWithWeight("b", 1.1)
 
-		if rv.nodes[1].weight != 1.1 {
+		if rv.load()[1].weight != 1.1 {
 			t.Fatalf("wtf")
 		}
 
 		rv.AddWithWeight("b", 1.5)
-		if rv.nodes[1].weight != 1.5 {
+		if rv.load()[1].weight != 1.5 {
 			t.Errorf("Expected AddWithWeight on an existing node to update the node's weight")
 		}
 	})
@@ -195,7 +272,7 @@ func TestRing_LookupAll(t *testing.T) {
 		rv.Add("e")
 
 		names := rv.LookupAll("foo")
-		expected := []string{"d", "b", "c", "a", "e"}
+		expected := []string{"d", "b", "c", "e", "a"}
 		if !reflect.DeepEqual(names, expected) {
 			t.Errorf("Expected %v but got %v", expected, names)
 		}
@@ -232,4 +309,483 @@ func TestRing_Weight(t *testing.T) {
 			t.Errorf("Expected %v but got %v", expected, weight)
 		}
 	})
+
+	t.Run("Missing", func(t *testing.T) {
+		rv := New()
+		rv.AddWithWeight("b", 2)
+		rv.AddWithWeight("c", 3)
+
+		for _, name := range []string{"a", "bb", "d"} {
+			if weight := rv.Weight(name); weight != 0 {
+				t.Errorf("Expected 0 for %s but got %v", name, weight)
+			}
+		}
+	})
+}
+
+func BenchmarkRing_Contains(b *testing.B) {
+	rv := New()
+	names := make([]string, 200000)
+	for i := range names {
+		names[i] = "node-" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.Contains(names[i%len(names)])
+	}
+}
+
+// BenchmarkRing_Lookup_Snapshot compares Lookup with ranking a preloaded
+// snapshot, which is what an unsynchronized ring would do per lookup.
+func BenchmarkRing_Lookup_Snapshot(b *testing.B) {
+	for _, count := range []int{1, 10, 100} {
+		rv := New()
+		for i := 0; i < count; i++ {
+			rv.Add("n" + strconv.Itoa(i))
+		}
+		nodes := rv.load()
+
+		b.Run("Lookup/"+strconv.Itoa(count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rv.lookup(uint64(i))
+			}
+		})
+		b.Run("Preloaded/"+strconv.Itoa(count), func(b *testing.B) {
+			var buf [1]ScoredNode
+			for i := 0; i < b.N; i++ {
+				rv.topNInto(buf[:0], nodes, uint64(i), 1, active)
+			}
+		})
+	}
+}
+
+func TestRing_Weights(t *testing.T) {
+	rv := NewFromMap(map[string]float64{"a": 1.5, "b": 2.5})
+	rv.Drain("b")
+
+	expected := map[string]float64{"a": 1.5, "b": 2.5}
+	if weights := rv.Weights(); !reflect.DeepEqual(weights, expected) {
+		t.Errorf("Expected %v but got %v", expected, weights)
+	}
+	if weights := New().Weights(); len(weights) != 0 {
+		t.Errorf("Expected no weights but got %v", weights)
+	}
+}
+
+func TestNewWithHasher(t *testing.T) {
+	t.Run("MatchesNewWithHash", func(t *testing.T) {
+		rv1 := NewWithHash(xxhash.New())
+		rv2 := NewWithHasher(xxhash.Sum64String)
+		rv3 := NewWithHasher(nil)
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			rv1.Add(name)
+			rv2.Add(name)
+			rv3.Add(name)
+		}
+
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			expected := rv1.LookupAll(key)
+			if names := rv2.LookupAll(key); !reflect.DeepEqual(names, expected) {
+				t.Errorf("Expected %v but got %v", expected, names)
+			}
+			if names := rv3.LookupAll(key); !reflect.DeepEqual(names, expected) {
+				t.Errorf("Expected %v but got %v", expected, names)
+			}
+		}
+	})
+
+	t.Run("ConcurrentLookups", func(t *testing.T) {
+		rv := NewWithHasher(nil)
+		for i := 0; i < 100; i++ {
+			rv.Add("n" + strconv.Itoa(i))
+		}
+
+		expected := make([]string, 1000)
+		for i := range expected {
+			expected[i] = rv.Lookup("k" + strconv.Itoa(i))
+		}
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range expected {
+					if node := rv.Lookup("k" + strconv.Itoa(i)); node != expected[i] {
+						t.Errorf("Expected %s but got %s", expected[i], node)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestNewFromMap(t *testing.T) {
+	weights := map[string]float64{"a": 1.5, "b": 2.5, "c": 0.5}
+	rv := NewFromMap(weights, WithNodes("b", "d"))
+
+	expected := []string{"a", "b", "c", "d"}
+	if names := rv.List(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+	for name, weight := range map[string]float64{"a": 1.5, "b": 2.5, "c": 0.5, "d": 1} {
+		if w := rv.Weight(name); w != weight {
+			t.Errorf("Expected %v for %s but got %v", weight, name, w)
+		}
+	}
+
+	if rv := NewFromMap(nil); rv.Len() != 0 || rv.Version() != 0 {
+		t.Errorf("Expected an empty ring without writes")
+	}
+}
+
+func TestRing_CopyOnWrite(t *testing.T) {
+	t.Run("SnapshotsAreImmutable", func(t *testing.T) {
+		rv := New()
+		rv.AddWithWeight("a", 1.0)
+		rv.AddWithWeight("b", 1.0)
+
+		snapshot := rv.load()
+
+		rv.AddWithWeight("b", 2.0)
+		rv.Add("c")
+		rv.Remove("a")
+
+		if len(snapshot) != 2 || snapshot[0].name != "a" || snapshot[1].weight != 1.0 {
+			t.Errorf("Expected snapshot to be unaffected by writes but got %v", snapshot)
+		}
+	})
+
+	t.Run("LookupsDoNotLock", func(t *testing.T) {
+		rv := New(WithNodes("a", "b", "c"))
+		rv.mutex.Lock()
+		defer rv.mutex.Unlock()
+
+		done := make(chan string)
+		go func() {
+			done <- rv.Lookup("foo")
+		}()
+		select {
+		case node := <-done:
+			if node == "" {
+				t.Errorf("Expected a node but got none")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected lookups not to wait for writers")
+		}
+	})
+
+	t.Run("ConcurrentReadsAndWrites", func(t *testing.T) {
+		rv := New()
+		for i := 0; i < 10; i++ {
+			rv.Add("n" + strconv.Itoa(i))
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				name := "m" + strconv.Itoa(i%20)
+				rv.Add(name)
+				rv.Remove(name)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if node := rv.Lookup("k" + strconv.Itoa(i)); node == "" {
+					t.Errorf("Expected a node but got none")
+				}
+			}
+		}()
+		wg.Wait()
+	})
+}
+
+func TestRing_AddWithTags(t *testing.T) {
+	t.Run("StoresTags", func(t *testing.T) {
+		rv := New()
+		tags := map[string]string{"zone": "us-east-1a", "addr": "10.0.0.1:8080"}
+		rv.AddWithTags("a", 2.0, tags)
+		tags["zone"] = "mutated"
+
+		expected := map[string]string{"zone": "us-east-1a", "addr": "10.0.0.1:8080"}
+		if actual := rv.Tags("a"); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+		if w := rv.Weight("a"); w != 2.0 {
+			t.Errorf("Expected 2.0 but got %v", w)
+		}
+	})
+
+	t.Run("AddWithWeightKeepsTags", func(t *testing.T) {
+		rv := New()
+		rv.AddWithTags("a", 1.0, map[string]string{"zone": "z1"})
+		rv.AddWithWeight("a", 3.0)
+
+		if zone, ok := rv.LookupNode("foo").Tag("zone"); !ok || zone != "z1" {
+			t.Errorf("Expected z1 but got %v", zone)
+		}
+	})
+}
+
+func TestRing_LookupNode(t *testing.T) {
+	rv := New()
+	if n := rv.LookupNode("foo"); n != nil {
+		t.Errorf("Expected nil but got %v", n)
+	}
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		rv.AddWithTags(name, 1.0, map[string]string{"zone": "zone-" + name})
+	}
+
+	n := rv.LookupNode("foo")
+	if n.Name() != "d" || n.Weight() != 1.0 {
+		t.Errorf("Expected d with weight 1.0 but got %s with weight %v", n.Name(), n.Weight())
+	}
+	if zone, _ := n.Tag("zone"); zone != "zone-d" {
+		t.Errorf("Expected zone-d but got %s", zone)
+	}
+	if n.Hash() != rv.Hash("d") {
+		t.Errorf("Expected the hash of d")
+	}
+}
+
+func TestRing_LookupTopNNodes(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d", "e"))
+	rv.Drain("b")
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		nodes := rv.LookupTopNNodes(key, 3)
+		names := make([]string, len(nodes))
+		for i, n := range nodes {
+			names[i] = n.Name()
+		}
+		if expected := rv.LookupTopN(key, 3); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v for %s but got %v", expected, key, names)
+		}
+	}
+	if nodes := New().LookupTopNNodes("foo", 3); len(nodes) != 0 {
+		t.Errorf("Expected no nodes but got %v", nodes)
+	}
+}
+
+func TestRing_LookupBytes(t *testing.T) {
+	rv := New()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		rv.Add(name)
+	}
+
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		if expected, actual := rv.Lookup(key), rv.LookupBytes([]byte(key)); actual != expected {
+			t.Errorf("Expected %s but got %s", expected, actual)
+		}
+	}
+
+	names := rv.LookupTopNBytes([]byte("foo"), 3)
+	expected := []string{"d", "b", "c"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+
+	names = rv.LookupAllBytes([]byte("foo"))
+	expected = []string{"d", "b", "c", "e", "a"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+
+	if node := New().LookupBytes(nil); node != "" {
+		t.Errorf("Expected empty string but got %s", node)
+	}
+}
+
+func TestRing_LookupHash(t *testing.T) {
+	rv1 := NewWithHasher(xxhash.Sum64String)
+	rv2 := NewWithHasher(xxhash.Sum64String)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		rv1.Add(name)
+		rv2.AddWithWeight(name, 2.0)
+	}
+	rv2.Add("f")
+
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		keyHash := xxhash.Sum64String(key)
+		if expected, actual := rv1.Lookup(key), rv1.LookupHash(keyHash); actual != expected {
+			t.Errorf("Expected %s but got %s", expected, actual)
+		}
+		if expected, actual := rv2.LookupTopN(key, 2), rv2.LookupTopNHash(keyHash, 2); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+		if expected, actual := rv2.LookupAll(key), rv2.LookupAllHash(keyHash); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestRing_AddAll(t *testing.T) {
+	t.Run("MatchesAdd", func(t *testing.T) {
+		rv1 := New()
+		rv2 := New()
+		rv1.Add("c")
+		rv2.Add("c")
+		for _, name := range []string{"e", "a", "d", "b", "c", "a"} {
+			rv1.Add(name)
+		}
+		rv2.AddAll([]string{"e", "a", "d", "b", "c", "a"})
+
+		if !reflect.DeepEqual(rv1.List(), rv2.List()) {
+			t.Errorf("Expected %v but got %v", rv1.List(), rv2.List())
+		}
+		if !reflect.DeepEqual(rv1.LookupAll("foo"), rv2.LookupAll("foo")) {
+			t.Errorf("Expected %v but got %v", rv1.LookupAll("foo"), rv2.LookupAll("foo"))
+		}
+	})
+
+	t.Run("KeepsTags", func(t *testing.T) {
+		rv := New()
+		rv.AddWithTags("a", 2.0, map[string]string{"zone": "z1"})
+		rv.AddAll([]string{"a", "b"})
+
+		if w := rv.Weight("a"); w != defaultWeight {
+			t.Errorf("Expected %v but got %v", defaultWeight, w)
+		}
+		if zone := rv.Tags("a")["zone"]; zone != "z1" {
+			t.Errorf("Expected z1 but got %s", zone)
+		}
+	})
+}
+
+func TestRing_AddAllWithWeights(t *testing.T) {
+	rv := New()
+	rv.Add("b")
+	rv.AddAllWithWeights(map[string]float64{"a": 1.5, "b": 2.5, "c": 0.5})
+
+	expected := []string{"a", "b", "c"}
+	if names := rv.List(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+	for name, weight := range map[string]float64{"a": 1.5, "b": 2.5, "c": 0.5} {
+		if w := rv.Weight(name); w != weight {
+			t.Errorf("Expected %v for %s but got %v", weight, name, w)
+		}
+	}
+
+	version := rv.Version()
+	if err := rv.AddAllWithWeights(map[string]float64{"d": 1, "m": math.Inf(1)}); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("Expected %v but got %v", ErrInvalidWeight, err)
+	}
+	if rv.Version() != version || rv.Contains("d") {
+		t.Errorf("Expected the ring to be unchanged but got %v", rv.List())
+	}
+}
+
+func TestRing_RemoveAll(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c", "d", "e"})
+	rv.RemoveAll([]string{"b", "d", "z"})
+
+	expected := []string{"a", "c", "e"}
+	if names := rv.List(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+}
+
+func TestRing_LookupBatch(t *testing.T) {
+	rv := New()
+	if names := rv.LookupBatch([]string{"foo"}); !reflect.DeepEqual(names, []string{""}) {
+		t.Errorf("Expected [\"\"] but got %v", names)
+	}
+
+	for i := 0; i < 20; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), float64(i%3+1))
+	}
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+
+	names := rv.LookupBatch(keys)
+	for i, key := range keys {
+		if expected := rv.Lookup(key); names[i] != expected {
+			t.Errorf("Expected %s for %s but got %s", expected, key, names[i])
+		}
+	}
+}
+
+func TestRing_LookupTopNBatch(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c", "d", "e"})
+
+	keys := []string{"foo", "bar", "baz"}
+	results := rv.LookupTopNBatch(keys, 3)
+	for i, key := range keys {
+		if expected := rv.LookupTopN(key, 3); !reflect.DeepEqual(results[i], expected) {
+			t.Errorf("Expected %v for %s but got %v", expected, key, results[i])
+		}
+	}
+
+	results = rv.LookupTopNBatch(keys, 10)
+	for i, key := range keys {
+		if expected := rv.LookupAll(key); !reflect.DeepEqual(results[i], expected) {
+			t.Errorf("Expected %v for %s but got %v", expected, key, results[i])
+		}
+	}
+}
+
+func TestRing_LookupAllScored(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c", "d", "e"})
+	rv.AddWithWeight("c", 2.0)
+
+	scoredNodes := rv.LookupAllScored("foo")
+	names := rv.LookupAll("foo")
+	if len(scoredNodes) != len(names) {
+		t.Fatalf("Expected %d nodes but got %d", len(names), len(scoredNodes))
+	}
+
+	keyHash := rv.computeHash("foo")
+	for i, scoredNode := range scoredNodes {
+		if scoredNode.Name() != names[i] || scoredNode.Node().Name() != names[i] {
+			t.Errorf("Expected %s at %d but got %s", names[i], i, scoredNode.Name())
+		}
+		if i > 0 && scoredNode.Score() > scoredNodes[i-1].Score() {
+			t.Errorf("Expected descending scores but got %v", scoredNodes)
+		}
+		n := scoredNode.Node()
+		if expected := computeScore(keyHash, n.hash, n.weight); scoredNode.Score() != expected {
+			t.Errorf("Expected score %v but got %v", expected, scoredNode.Score())
+		}
+		if scoredNode.Weight() != rv.Weight(scoredNode.Name()) {
+			t.Errorf("Expected weight %v but got %v", rv.Weight(scoredNode.Name()), scoredNode.Weight())
+		}
+	}
+
+	if top := rv.LookupTopNScored("foo", 2); !reflect.DeepEqual(top, scoredNodes[:2]) {
+		t.Errorf("Expected %v but got %v", scoredNodes[:2], top)
+	}
+}
+
+func TestRing_Score(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c", "d", "e"})
+	rv.AddWithWeight("b", 3.0)
+
+	for _, scoredNode := range rv.LookupAllScored("foo") {
+		score, ok := rv.Score("foo", scoredNode.Name())
+		if !ok || score != scoredNode.Score() {
+			t.Errorf("Expected %v for %s but got %v", scoredNode.Score(), scoredNode.Name(), score)
+		}
+	}
+
+	if _, ok := rv.Score("foo", "z"); ok {
+		t.Errorf("Expected no score for a non-existent node")
+	}
 }
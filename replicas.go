@@ -0,0 +1,47 @@
+package rendezvous
+
+import "sort"
+
+// ReplicationFactor returns the number of replicas Replicas places each key
+// on, see WithReplicationFactor.
+func (r *Ring) ReplicationFactor() int {
+	return r.opts.replicas
+}
+
+// Replicas returns the nodes holding the replicas of key, in rank order. It
+// is LookupTopN with the ring's replication factor.
+func (r *Ring) Replicas(key string) []string {
+	return r.LookupTopN(key, r.opts.replicas)
+}
+
+// IsReplica reports whether the named node is one of the Replicas of key,
+// without ranking the ring.
+func (r *Ring) IsReplica(node, key string) bool {
+	return r.ranksWithin(node, r.computeHash(key), r.opts.replicas)
+}
+
+// ranksWithin reports whether the named node is among the n highest ranked
+// nodes lookups may return for keyHash. It only counts the nodes scoring
+// higher, and stops as soon as there are n of them.
+func (r *Ring) ranksWithin(name string, keyHash uint64, n int) bool {
+	nodes := r.load()
+	ix := sort.Search(len(nodes), cmp(nodes, name))
+	if n <= 0 || ix == len(nodes) || nodes[ix].name != name || !active(nodes[ix]) {
+		return false
+	}
+
+	self := ScoredNode{node: nodes[ix], score: r.score(keyHash, nodes[ix])}
+	higher := 0
+	for _, node := range nodes {
+		if !active(node) || node == nodes[ix] {
+			continue
+		}
+		if ranksAbove(ScoredNode{node: node, score: r.score(keyHash, node)}, self) {
+			higher++
+			if higher == n {
+				return false
+			}
+		}
+	}
+	return true
+}
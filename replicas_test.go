@@ -0,0 +1,32 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_Replicas(t *testing.T) {
+	rv := New(WithReplicationFactor(3), WithNodes("a", "b", "c", "d", "e"))
+	if rv.ReplicationFactor() != 3 || New().ReplicationFactor() != 1 {
+		t.Fatalf("Expected replication factors of 3 and 1")
+	}
+	rv.Drain("c")
+
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		replicas := rv.Replicas(key)
+		if !reflect.DeepEqual(replicas, rv.LookupTopN(key, 3)) {
+			t.Fatalf("Expected %v but got %v", rv.LookupTopN(key, 3), replicas)
+		}
+		for _, name := range []string{"a", "b", "c", "d", "e", "z"} {
+			expected := false
+			for _, replica := range replicas {
+				expected = expected || replica == name
+			}
+			if rv.IsReplica(name, key) != expected {
+				t.Fatalf("Expected IsReplica(%s, %s) to be %v", name, key, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package rendezvous
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Reweighter adjusts node weights from load samples so that nodes converge
+// toward equal utilization: nodes loaded above the average lose weight and
+// nodes below it gain weight. The samples of a node between two rebalances
+// are smoothed with an exponentially weighted moving average, so a single
+// spike barely moves the weights.
+//
+// A Reweighter is safe for concurrent use.
+type Reweighter struct {
+	ring *Ring
+	opts reweightOptions
+
+	mutex sync.Mutex
+	loads map[string]float64
+}
+
+// A ReweightOption configures a Reweighter.
+type ReweightOption func(*reweightOptions)
+
+type reweightOptions struct {
+	alpha     float64
+	minWeight float64
+	maxWeight float64
+}
+
+// WithSmoothing sets the weight of a new sample in the moving average, in
+// (0, 1]. Higher values react faster; the default is 0.2.
+func WithSmoothing(alpha float64) ReweightOption {
+	return func(o *reweightOptions) {
+		if alpha > 0 && alpha <= 1 {
+			o.alpha = alpha
+		}
+	}
+}
+
+// WithWeightBounds clamps adjusted weights to [lower, upper]; the default is
+// [0.1, 10].
+func WithWeightBounds(lower, upper float64) ReweightOption {
+	return func(o *reweightOptions) {
+		if lower > 0 && lower <= upper {
+			o.minWeight = lower
+			o.maxWeight = upper
+		}
+	}
+}
+
+// NewReweighter returns a Reweighter adjusting the weights of ring.
+func NewReweighter(ring *Ring, opts ...ReweightOption) *Reweighter {
+	rw := &Reweighter{
+		ring: ring,
+		opts: reweightOptions{
+			alpha:     0.2,
+			minWeight: 0.1,
+			maxWeight: 10,
+		},
+		loads: make(map[string]float64),
+	}
+	for _, opt := range opts {
+		opt(&rw.opts)
+	}
+	return rw
+}
+
+// ReportLoad records a load sample of the named node, such as its CPU
+// utilization or request latency. Samples of a node should be comparable
+// with those of the other nodes. Samples that are negative, infinite or NaN
+// are discarded with ErrInvalidLoad.
+func (rw *Reweighter) ReportLoad(name string, load float64) error {
+	if load < 0 || math.IsInf(load, 0) || math.IsNaN(load) {
+		return ErrInvalidLoad
+	}
+
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if avg, ok := rw.loads[name]; ok {
+		rw.loads[name] = rw.opts.alpha*load + (1-rw.opts.alpha)*avg
+	} else {
+		rw.loads[name] = load
+	}
+	return nil
+}
+
+// Rebalance scales the weight of every node with samples by the ratio of
+// the average load to its own, within the weight bounds, under a single
+// write. Nodes without samples keep their weight, and nodes weighted zero
+// are left out, since they are never selected. Rebalance consumes the
+// samples, since they were measured under the old weights, so calling it
+// again before new samples are reported changes nothing.
+func (rw *Reweighter) Rebalance() {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	defer clear(rw.loads)
+
+	r := rw.ring
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nodes := r.load()
+	var sum float64
+	for name, load := range rw.loads {
+		if ix := sort.Search(len(nodes), cmp(nodes, name)); ix == len(nodes) || nodes[ix].name != name || nodes[ix].weight == 0 {
+			delete(rw.loads, name)
+			continue
+		}
+		sum += load
+	}
+	if sum <= 0 {
+		return
+	}
+	mean := sum / float64(len(rw.loads))
+
+	updated := make([]*Node, len(nodes))
+	copy(updated, nodes)
+	changed := false
+	for i, n := range nodes {
+		load, ok := rw.loads[n.name]
+		if !ok {
+			continue
+		}
+		weight := rw.opts.maxWeight
+		if load > 0 {
+			weight = n.weight * mean / load
+		}
+		if !(weight >= rw.opts.minWeight) {
+			weight = rw.opts.minWeight
+		} else if weight > rw.opts.maxWeight {
+			weight = rw.opts.maxWeight
+		}
+		if weight != n.weight {
+			c := *n
+			c.weight = weight
+			updated[i] = &c
+			changed = true
+		}
+	}
+	if changed {
+		r.store(updated)
+	}
+}
+
+// Run calls Rebalance every interval until ctx is done, and returns ctx's
+// error.
+func (rw *Reweighter) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rw.Rebalance()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
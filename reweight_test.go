@@ -0,0 +1,113 @@
+package rendezvous
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestReweighter_Rebalance(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rw := NewReweighter(rv, WithSmoothing(1), WithWeightBounds(0.5, 2))
+
+	rw.ReportLoad("a", 0.9)
+	rw.ReportLoad("b", 0.3)
+	rw.ReportLoad("z", 0.1)
+	rw.Rebalance()
+
+	// The mean load is 0.6.
+	if w := rv.Weight("a"); math.Abs(w-2.0/3) > 1e-9 {
+		t.Errorf("Expected a to lose weight but got %v", w)
+	}
+	if w := rv.Weight("b"); w != 2 {
+		t.Errorf("Expected b to be clamped at 2 but got %v", w)
+	}
+	if w := rv.Weight("c"); w != 1 {
+		t.Errorf("Expected c without samples to keep its weight but got %v", w)
+	}
+	if rv.Contains("z") {
+		t.Errorf("Expected unknown nodes not to be added")
+	}
+}
+
+func TestReweighter_Rebalance_Idempotent(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rw := NewReweighter(rv, WithSmoothing(1))
+
+	rw.ReportLoad("a", 0.9)
+	rw.ReportLoad("b", 0.3)
+	rw.Rebalance()
+	a, b, version := rv.Weight("a"), rv.Weight("b"), rv.Version()
+
+	// Without new samples, the old ones must not be applied again.
+	rw.Rebalance()
+	rw.Rebalance()
+	if rv.Weight("a") != a || rv.Weight("b") != b || rv.Version() != version {
+		t.Errorf("Expected weights %v and %v but got %v and %v", a, b, rv.Weight("a"), rv.Weight("b"))
+	}
+
+	rw.ReportLoad("a", 0.6)
+	rw.ReportLoad("b", 0.6)
+	rw.Rebalance()
+	if rv.Weight("a") != a || rv.Weight("b") != b {
+		t.Errorf("Expected balanced loads to keep the weights")
+	}
+}
+
+func TestReweighter_ReportLoad(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rw := NewReweighter(rv)
+
+	rw.ReportLoad("a", 1)
+	rw.ReportLoad("b", 1)
+	rw.ReportLoad("a", 6)
+	rw.Rebalance()
+
+	// The spike only moves a's average to 0.2*6 + 0.8*1 = 2.
+	if w := rv.Weight("a"); math.Abs(w-0.75) > 1e-9 {
+		t.Errorf("Expected 0.75 but got %v", w)
+	}
+	if w := rv.Weight("b"); math.Abs(w-1.5) > 1e-9 {
+		t.Errorf("Expected 1.5 but got %v", w)
+	}
+
+	version := rv.Version()
+	rv.Remove("a")
+	rw.Rebalance()
+	if rv.Contains("a") || rv.Version() != version+1 {
+		t.Errorf("Expected the samples of removed nodes to be discarded")
+	}
+}
+
+func TestReweighter_ReportLoad_Invalid(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rw := NewReweighter(rv, WithSmoothing(1))
+
+	for _, load := range []float64{-1, math.Inf(1), math.NaN()} {
+		if err := rw.ReportLoad("a", load); !errors.Is(err, ErrInvalidLoad) {
+			t.Errorf("Expected ErrInvalidLoad for %v but got %v", load, err)
+		}
+	}
+	rw.ReportLoad("b", 1)
+	rw.Rebalance()
+	if w := rv.Weight("a"); w != 1 {
+		t.Errorf("Expected 1 but got %v", w)
+	}
+}
+
+func TestReweighter_Rebalance_ZeroWeight(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rv.AddWithWeight("z", 0)
+	rw := NewReweighter(rv, WithSmoothing(1))
+
+	rw.ReportLoad("a", 1)
+	rw.ReportLoad("b", 1)
+	rw.ReportLoad("z", 0)
+	rw.Rebalance()
+	if w := rv.Weight("z"); w != 0 {
+		t.Errorf("Expected z to stay weighted zero but got %v", w)
+	}
+	if w := rv.Weight("a"); w != 1 {
+		t.Errorf("Expected z to be left out of the mean load but got %v", w)
+	}
+}
@@ -0,0 +1,136 @@
+// Package ringflag configures a rendezvous.Ring from command line flags and
+// environment variables, so small services need not parse node lists
+// themselves. Register defines the flags
+//
+//	-ring.nodes    comma separated names of nodes with the default weight
+//	-ring.weights  comma separated name=weight nodes, e.g. host1=2.0,host2=1.0
+//	-ring.hash     name of a registered hash function, see rendezvous.Hashers
+//	-ring.seed     seed of the ring's hashes, see rendezvous.WithSeed
+//
+// whose defaults are read from the environment variables RING_NODES,
+// RING_WEIGHTS, RING_HASH and RING_SEED. The prefix "ring" is chosen by the
+// caller, so a service may configure several rings.
+package ringflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mosuka/rendezvous"
+)
+
+// Flags holds the unparsed configuration of a ring.
+type Flags struct {
+	Nodes   string
+	Weights string
+	Hash    string
+	Seed    string
+}
+
+// Register defines the flags of the ring configuration named prefix on fs,
+// defaulting to the environment, see FromEnv. The returned Flags are set
+// when fs is parsed.
+func Register(fs *flag.FlagSet, prefix string) *Flags {
+	f := FromEnv(prefix)
+	fs.StringVar(&f.Nodes, prefix+".nodes", f.Nodes, "comma separated `names` of nodes with the default weight")
+	fs.StringVar(&f.Weights, prefix+".weights", f.Weights, "comma separated `name=weight` nodes")
+	fs.StringVar(&f.Hash, prefix+".hash", f.Hash, "`name` of the hash function, one of "+strings.Join(rendezvous.Hashers(), ", "))
+	fs.StringVar(&f.Seed, prefix+".seed", f.Seed, "`seed` of the ring's hashes")
+	return f
+}
+
+// FromEnv returns the ring configuration named prefix from the environment:
+// the variables are named by prefix in upper case, with dots and dashes
+// replaced by underscores, followed by _NODES, _WEIGHTS, _HASH and _SEED.
+func FromEnv(prefix string) *Flags {
+	env := strings.NewReplacer(".", "_", "-", "_").Replace(strings.ToUpper(prefix)) + "_"
+	return &Flags{
+		Nodes:   os.Getenv(env + "NODES"),
+		Weights: os.Getenv(env + "WEIGHTS"),
+		Hash:    os.Getenv(env + "HASH"),
+		Seed:    os.Getenv(env + "SEED"),
+	}
+}
+
+// Ring creates a ring configured by opts followed by the flags, whose hash
+// function and seed thus take precedence. Nodes named by both Nodes and
+// Weights take the weight of Weights.
+func (f *Flags) Ring(opts ...rendezvous.Option) (*rendezvous.Ring, error) {
+	weights, err := ParseWeights(f.Weights)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range Split(f.Nodes) {
+		if _, ok := weights[name]; !ok {
+			weights[name] = 1
+		}
+	}
+
+	opts = opts[:len(opts):len(opts)]
+	if f.Hash != "" {
+		if !registered(f.Hash) {
+			return nil, fmt.Errorf("ringflag: unknown hash %q, expected one of %s", f.Hash, strings.Join(rendezvous.Hashers(), ", "))
+		}
+		opts = append(opts, rendezvous.WithNamedHasher(f.Hash))
+	}
+	if f.Seed != "" {
+		seed, err := strconv.ParseUint(f.Seed, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ringflag: invalid seed %q", f.Seed)
+		}
+		opts = append(opts, rendezvous.WithSeed(seed))
+	}
+	return rendezvous.NewFromMap(weights, opts...), nil
+}
+
+// ParseWeights parses comma separated name=weight nodes such as
+// "host1=2.0,host2=1.0". A name without a weight has the default weight, and
+// a weight of zero registers a node that lookups never select. Weights that
+// are negative, infinite or NaN are rejected with rendezvous.ErrInvalidWeight.
+func ParseWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, entry := range Split(s) {
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("ringflag: node without a name in " + strconv.Quote(s))
+		}
+		weight := 1.0
+		if found {
+			var err error
+			weight, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				err = rendezvous.CheckWeight(weight)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("ringflag: weight %q of node %q: %w", value, name, rendezvous.ErrInvalidWeight)
+			}
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// Split returns the trimmed, non-empty elements of a comma separated list.
+func Split(s string) []string {
+	var elems []string
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+func registered(name string) bool {
+	for _, hasher := range rendezvous.Hashers() {
+		if hasher == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package ringflag
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/mosuka/rendezvous"
+)
+
+func TestRegister(t *testing.T) {
+	t.Setenv("RING_NODES", "a,b")
+	t.Setenv("RING_SEED", "7")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := Register(fs, "ring")
+	if err := fs.Parse([]string{"-ring.weights", "b=2.5, c", "-ring.seed", "0x2a"}); err != nil {
+		t.Fatal(err)
+	}
+	ring, err := f.Ring()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := ring.List(); !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("Expected the nodes of the environment and the flags but got %v", names)
+	}
+	if ring.Weight("a") != 1 || ring.Weight("b") != 2.5 || ring.Weight("c") != 1 {
+		t.Errorf("Expected the weights of the flags")
+	}
+	expected := rendezvous.New(rendezvous.WithSeed(42))
+	if ring.Hash("k") != expected.Hash("k") {
+		t.Errorf("Expected the seed of the flags to override the environment")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("CACHE_RING_WEIGHTS", "x=3")
+	t.Setenv("CACHE_RING_HASH", "xxhash")
+
+	f := FromEnv("cache.ring")
+	if f.Weights != "x=3" || f.Hash != "xxhash" {
+		t.Errorf("Expected the prefixed variables but got %+v", f)
+	}
+	ring, err := f.Ring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ring.Weight("x") != 3 {
+		t.Errorf("Expected x to be weighted 3")
+	}
+}
+
+func TestParseWeights_Zero(t *testing.T) {
+	weights, err := ParseWeights("a=0,b=1.5")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if w, ok := weights["a"]; !ok || w != 0 || weights["b"] != 1.5 {
+		t.Errorf("Expected a to be weighted 0 but got %v", weights)
+	}
+}
+
+func TestFlags_Ring_Invalid(t *testing.T) {
+	if _, err := ParseWeights("a=-1"); !errors.Is(err, rendezvous.ErrInvalidWeight) {
+		t.Errorf("Expected %v but got %v", rendezvous.ErrInvalidWeight, err)
+	}
+	for _, f := range []Flags{
+		{Weights: "a=heavy"},
+		{Weights: "a=-1"},
+		{Weights: "a=NaN"},
+		{Weights: "a=Inf"},
+		{Weights: "a=+Inf"},
+		{Weights: "=2"},
+		{Hash: "unknown"},
+		{Seed: "seed"},
+	} {
+		if _, err := f.Ring(); err == nil {
+			t.Errorf("Expected an error for %+v", f)
+		}
+	}
+}
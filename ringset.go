@@ -0,0 +1,202 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// A RingSet manages named rings, such as one per service or cache tier, and
+// adds or removes nodes across them. Each ring is changed by its own write,
+// so lookups on different rings may briefly disagree about a node that is
+// being added to or removed from several of them.
+//
+// Like a Ring, a RingSet never locks on lookups and is safe for concurrent
+// use.
+type RingSet struct {
+	opts []Option
+
+	// rings is replaced on every change to the set of rings, under mutex.
+	mutex sync.Mutex
+	rings atomic.Pointer[map[string]*Ring]
+}
+
+// RingStats describes a ring, see RingSet.Stats.
+type RingStats struct {
+	// Nodes is the number of nodes and Active the number of those that are
+	// neither drained nor unhealthy.
+	Nodes, Active int
+	// Weight is the total weight of the active nodes.
+	Weight  float64
+	Version uint64
+}
+
+// RingSetStats describes the rings of a RingSet.
+type RingSetStats struct {
+	Rings map[string]RingStats
+	// Nodes is the number of distinct nodes across all rings.
+	Nodes int
+}
+
+// NewRingSet creates an empty RingSet whose rings are configured by opts.
+func NewRingSet(opts ...Option) *RingSet {
+	return &RingSet{opts: opts}
+}
+
+// AddRing returns the named ring, creating it configured by the set's
+// options followed by opts if it does not exist yet.
+func (s *RingSet) AddRing(name string, opts ...Option) *Ring {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rings := s.load()
+	if ring, ok := rings[name]; ok {
+		return ring
+	}
+	ring := New(append(append([]Option(nil), s.opts...), opts...)...)
+	s.replace(name, ring)
+	return ring
+}
+
+// RemoveRing removes the named ring and reports whether it existed.
+func (s *RingSet) RemoveRing(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.load()[name]; !ok {
+		return false
+	}
+	s.replace(name, nil)
+	return true
+}
+
+// Ring returns the named ring, or nil if there is no such ring.
+func (s *RingSet) Ring(name string) *Ring {
+	return s.load()[name]
+}
+
+// Names returns the names of the rings in ascending order.
+func (s *RingSet) Names() []string {
+	rings := s.load()
+	names := make([]string, 0, len(rings))
+	for name := range rings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddNode adds the named node with weight to the named rings, or to every
+// ring if none are named, or reweights it where it exists. Unknown ring
+// names are ignored. If weight is invalid, see CheckWeight, no ring changes
+// and ErrInvalidWeight is returned.
+func (s *RingSet) AddNode(node string, weight float64, rings ...string) error {
+	if err := CheckWeight(weight); err != nil {
+		return err
+	}
+	for _, ring := range s.pick(rings) {
+		ring.AddWithWeight(node, weight)
+	}
+	return nil
+}
+
+// RemoveNode removes the named node from the named rings, or from every ring
+// if none are named.
+func (s *RingSet) RemoveNode(node string, rings ...string) {
+	for _, ring := range s.pick(rings) {
+		ring.Remove(node)
+	}
+}
+
+// Stats returns the statistics of every ring.
+func (s *RingSet) Stats() RingSetStats {
+	rings := s.load()
+	stats := RingSetStats{Rings: make(map[string]RingStats, len(rings))}
+	nodes := make(map[string]struct{})
+	for name, ring := range rings {
+		rs := RingStats{Version: ring.Version()}
+		for _, n := range ring.load() {
+			nodes[n.name] = struct{}{}
+			rs.Nodes++
+			if active(n) {
+				rs.Active++
+				rs.Weight += n.weight
+			}
+		}
+		stats.Rings[name] = rs
+	}
+	stats.Nodes = len(nodes)
+	return stats
+}
+
+// MarshalJSON encodes every ring by name, see Ring.MarshalJSON.
+func (s *RingSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.load())
+}
+
+// UnmarshalJSON replaces the rings with those encoded by MarshalJSON,
+// creating them with the set's options.
+func (s *RingSet) UnmarshalJSON(data []byte) error {
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	rings := make(map[string]*Ring, len(encoded))
+	for name, raw := range encoded {
+		ring := New(s.opts...)
+		if err := ring.UnmarshalJSON(raw); err != nil {
+			return err
+		}
+		rings[name] = ring
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rings.Store(&rings)
+	return nil
+}
+
+// pick returns the named rings that exist, or every ring if names is
+// empty.
+func (s *RingSet) pick(names []string) []*Ring {
+	rings := s.load()
+	selected := make([]*Ring, 0, len(rings))
+	if len(names) == 0 {
+		for _, ring := range rings {
+			selected = append(selected, ring)
+		}
+		return selected
+	}
+	for _, name := range names {
+		if ring, ok := rings[name]; ok {
+			selected = append(selected, ring)
+		}
+	}
+	return selected
+}
+
+// replace publishes the rings with the named ring set to ring, or removed if
+// ring is nil. The caller must hold the mutex.
+func (s *RingSet) replace(name string, ring *Ring) {
+	rings := s.load()
+	updated := make(map[string]*Ring, len(rings)+1)
+	for n, r := range rings {
+		updated[n] = r
+	}
+	if ring != nil {
+		updated[name] = ring
+	} else {
+		delete(updated, name)
+	}
+	s.rings.Store(&updated)
+}
+
+func (s *RingSet) load() map[string]*Ring {
+	if rings := s.rings.Load(); rings != nil {
+		return *rings
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package rendezvous
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRingSet(t *testing.T) {
+	s := NewRingSet(WithSeed(5))
+	cache := s.AddRing("cache")
+	if s.AddRing("cache") != cache || s.Ring("cache") != cache {
+		t.Errorf("Expected AddRing to return the existing ring")
+	}
+	s.AddRing("queue", WithNodes("q"))
+	if names := s.Names(); !reflect.DeepEqual(names, []string{"cache", "queue"}) {
+		t.Errorf("Expected [cache queue] but got %v", names)
+	}
+
+	s.AddNode("a", 1)
+	s.AddNode("b", 2, "cache", "unknown")
+	if err := s.AddNode("c", math.NaN()); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("Expected %v but got %v", ErrInvalidWeight, err)
+	}
+	s.Ring("queue").Drain("a")
+	if names := cache.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected [a b] but got %v", names)
+	}
+
+	stats := s.Stats()
+	if stats.Nodes != 3 || stats.Rings["cache"].Weight != 3 || stats.Rings["queue"].Nodes != 2 || stats.Rings["queue"].Active != 1 {
+		t.Errorf("Expected the statistics of both rings but got %+v", stats)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewRingSet(WithSeed(5))
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Stats().Rings["queue"].Nodes != 2 || restored.Ring("cache").Lookup("foo") != cache.Lookup("foo") {
+		t.Errorf("Expected the restored rings to match")
+	}
+
+	s.RemoveNode("a")
+	if cache.Contains("a") || s.Ring("queue").Contains("a") {
+		t.Errorf("Expected a to be removed from every ring")
+	}
+	if !s.RemoveRing("queue") || s.RemoveRing("queue") || s.Ring("queue") != nil {
+		t.Errorf("Expected queue to be removed once")
+	}
+}
@@ -0,0 +1,73 @@
+package rendezvous
+
+import (
+	"sync"
+	"time"
+)
+
+// scheduleSteps is the number of weight changes of a scheduled ramp.
+const scheduleSteps = 20
+
+// ScheduleWeight sets the weight of the named node at the given time, or
+// right away if it has passed. Nothing changes if the node does not exist by
+// then. Calling cancel before that time prevents the change. If weight is
+// invalid, see CheckWeight, nothing is scheduled and ErrInvalidWeight is
+// returned.
+func (r *Ring) ScheduleWeight(name string, weight float64, at time.Time) (cancel func(), err error) {
+	return r.ScheduleWeightOver(name, weight, at, at)
+}
+
+// ScheduleWeightOver moves the weight of the named node linearly from its
+// weight at start to weight at end, in steps, so that keys move gradually.
+// The ramp stops if the node is removed; calling cancel stops it where it is.
+// Invalid weights are rejected like by ScheduleWeight.
+func (r *Ring) ScheduleWeightOver(name string, weight float64, start, end time.Time) (cancel func(), err error) {
+	if err := CheckWeight(weight); err != nil {
+		return nil, err
+	}
+
+	var (
+		mutex   sync.Mutex
+		timer   *time.Timer
+		stopped bool
+		started bool
+		from    float64
+	)
+
+	step := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if stopped {
+			return
+		}
+
+		progress := 1.0
+		if now := time.Now(); now.Before(end) {
+			progress = float64(now.Sub(start)) / float64(end.Sub(start))
+		}
+		exists := r.update(name, func(n *Node) {
+			if !started {
+				from, started = n.weight, true
+			}
+			if progress < 1 {
+				n.weight = from + (weight-from)*progress
+			} else {
+				n.weight = weight
+			}
+		})
+		if exists && progress < 1 {
+			timer.Reset(end.Sub(start) / scheduleSteps)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	timer = time.AfterFunc(time.Until(start), step)
+
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		stopped = true
+		timer.Stop()
+	}, nil
+}
@@ -0,0 +1,76 @@
+package rendezvous
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRing_ScheduleWeight(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+	rv.ScheduleWeight("a", 0.2, time.Now().Add(10*time.Millisecond))
+	cancel, err := rv.ScheduleWeight("b", 3, time.Now().Add(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	cancel()
+
+	if rv.Weight("a") != 1 {
+		t.Errorf("Expected the weight to change later")
+	}
+	eventually(t, func() bool { return rv.Weight("a") == 0.2 })
+
+	time.Sleep(20 * time.Millisecond)
+	if rv.Weight("b") != 1 {
+		t.Errorf("Expected a canceled change not to apply")
+	}
+
+	rv.ScheduleWeight("a", 0.5, time.Now().Add(-time.Hour))
+	eventually(t, func() bool { return rv.Weight("a") == 0.5 })
+
+	for _, weight := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if _, err := rv.ScheduleWeight("a", weight, time.Now()); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("Expected %v for %v but got %v", ErrInvalidWeight, weight, err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if w := rv.Weight("a"); w != 0.5 {
+		t.Errorf("Expected invalid weights not to be scheduled but got %v", w)
+	}
+}
+
+func TestRing_ScheduleWeightOver(t *testing.T) {
+	rv := New(WithNodes("a"))
+	start := time.Now()
+	end := start.Add(100 * time.Millisecond)
+	rv.ScheduleWeightOver("a", 3, start, end)
+
+	var seen []float64
+	eventually(t, func() bool {
+		w := rv.Weight("a")
+		if len(seen) == 0 || seen[len(seen)-1] != w {
+			seen = append(seen, w)
+		}
+		return w == 3
+	})
+	if time.Now().Before(end) {
+		t.Errorf("Expected the ramp to end at %v", end)
+	}
+	if len(seen) < 3 {
+		t.Errorf("Expected intermediate weights but got %v", seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] < seen[i-1] {
+			t.Errorf("Expected increasing weights but got %v", seen)
+		}
+	}
+
+	rv.Remove("a")
+	cancel, _ := rv.ScheduleWeightOver("a", 1, time.Now(), time.Now().Add(time.Hour))
+	defer cancel()
+	time.Sleep(10 * time.Millisecond)
+	if rv.Contains("a") {
+		t.Errorf("Expected a schedule not to add nodes")
+	}
+}
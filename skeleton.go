@@ -0,0 +1,96 @@
+package rendezvous
+
+// skeletonFanout is the number of child clusters of a skeleton cluster, and
+// skeletonBits the bits of a node's path choosing among them.
+const (
+	skeletonFanout = 16
+	skeletonBits   = 4
+)
+
+// A cluster is a node of the skeleton tree, see WithSkeleton. Leaves hold
+// nodes; the other clusters hold their non-empty children.
+type cluster struct {
+	hash     uint64
+	weight   float64
+	active   int
+	children []*cluster
+	nodes    []*Node
+}
+
+// skeletonLevels returns the number of levels above the leaves that keep
+// leaves of a ring of size nodes at about skeletonFanout nodes.
+func skeletonLevels(size int) int {
+	levels := 0
+	for capacity := skeletonFanout; capacity < size; capacity *= skeletonFanout {
+		levels++
+	}
+	return levels
+}
+
+// buildSkeleton publishes the skeleton tree of nodes. The caller must hold
+// the mutex.
+func (r *Ring) buildSkeleton(nodes []*Node) {
+	r.skeleton.Store(r.buildCluster(nodes, skeletonLevels(r.opts.skeleton), 0, 0))
+}
+
+// buildCluster returns the cluster of nodes at level whose paths start with
+// prefix, with leaves at levels. Inactive nodes keep their weight in the
+// tree, so draining or failing a node only moves its own keys.
+func (r *Ring) buildCluster(nodes []*Node, levels, level int, prefix uint64) *cluster {
+	c := &cluster{hash: mix64(prefix<<8 | uint64(level))}
+	for _, n := range nodes {
+		c.weight += n.warmWeight()
+		if active(n) {
+			c.active++
+		}
+	}
+	if level == levels {
+		c.nodes = nodes
+		return c
+	}
+
+	var groups [skeletonFanout][]*Node
+	for _, n := range nodes {
+		digit := (mix64(n.hash) >> (level * skeletonBits)) % skeletonFanout
+		groups[digit] = append(groups[digit], n)
+	}
+	for digit, group := range groups {
+		if len(group) > 0 {
+			c.children = append(c.children, r.buildCluster(group, levels, level+1, prefix<<skeletonBits|uint64(digit)))
+		}
+	}
+	return c
+}
+
+// skeletonOwner returns the node owning keyHash in the skeleton tree, and
+// false if the ring has none.
+func (r *Ring) skeletonOwner(keyHash uint64) (*Node, bool) {
+	c := r.skeleton.Load()
+	if c == nil {
+		return nil, false
+	}
+	if c.active == 0 {
+		return nil, true
+	}
+
+	for c.nodes == nil {
+		var best *cluster
+		bestScore := 0.0
+		for _, child := range c.children {
+			if child.active == 0 {
+				continue
+			}
+			score := r.opts.score(keyHash, child.hash, child.weight)
+			if best == nil || score > bestScore || (score == bestScore && child.hash < best.hash) {
+				best, bestScore = child, score
+			}
+		}
+		c = best
+	}
+
+	var buf [1]ScoredNode
+	if scoredNodes := r.topNInto(buf[:0], c.nodes, keyHash, 1, active); len(scoredNodes) > 0 {
+		return scoredNodes[0].node, true
+	}
+	return nil, true
+}
@@ -0,0 +1,79 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestWithSkeleton(t *testing.T) {
+	rv := New(WithSkeleton(10000))
+	if node := rv.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+
+	for i := 0; i < 1000; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), float64(1+i%2))
+	}
+	keys := make(map[string]int)
+	for i := 0; i < 150000; i++ {
+		keys[rv.Lookup(strconv.Itoa(i))]++
+	}
+	for name, count := range keys {
+		expected := 100 * rv.Weight(name)
+		if float64(count) < expected*0.5 || float64(count) > expected*1.5 {
+			t.Errorf("Expected about %.0f keys on %s but got %d", expected, name, count)
+		}
+	}
+
+	owners := make(map[string]string)
+	for i := 0; i < 10000; i++ {
+		key := strconv.Itoa(i)
+		owners[key] = rv.Lookup(key)
+	}
+
+	// Draining a node moves only its own keys.
+	rv.Drain("n1")
+	for key, owner := range owners {
+		if moved := rv.Lookup(key); moved != owner && owner != "n1" {
+			t.Fatalf("Expected %s to stay on %s but got %s", key, owner, moved)
+		} else if moved == "n1" {
+			t.Fatalf("Expected %s to leave drained n1", key)
+		}
+	}
+	rv.Activate("n1")
+
+	// Removing a node moves few keys besides its own.
+	rv.Remove("n2")
+	moved := 0
+	for key, owner := range owners {
+		if rv.Lookup(key) != owner {
+			moved++
+		}
+	}
+	if moved > 100 {
+		t.Errorf("Expected few keys to move but %d did", moved)
+	}
+}
+
+func TestWithSkeleton_Owners(t *testing.T) {
+	rv := New(WithSkeleton(1000))
+	for i := 0; i < 300; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), 1+float64(i%3))
+	}
+	rv.Drain("n3")
+	testOwnersAgree(t, rv)
+}
+
+func BenchmarkRing_Lookup_Skeleton(b *testing.B) {
+	names := make([]string, 100000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv := New(WithSkeleton(len(names)))
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.Lookup("foo" + strconv.Itoa(i&1023))
+	}
+}
@@ -0,0 +1,83 @@
+package rendezvous
+
+// A slotTable maps every slot to the node owning it, see WithSlotTable.
+type slotTable struct {
+	owners []*Node
+	scores []float64
+}
+
+// slotOwner returns the node owning the slot of keyHash, and false if the
+// ring has no slot table.
+func (r *Ring) slotOwner(keyHash uint64) (*Node, bool) {
+	t := r.slots.Load()
+	if t == nil {
+		return nil, false
+	}
+	return t.owners[keyHash%uint64(len(t.owners))], true
+}
+
+// updateSlots publishes the slot table of after, derived from the table of
+// before. Only slots owned by changed or removed nodes are ranked again;
+// every other slot just compares its owner with the changed nodes. The
+// caller must hold the mutex.
+func (r *Ring) updateSlots(before, after []*Node) {
+	old := r.slots.Load()
+	t := &slotTable{
+		owners: make([]*Node, r.opts.slots),
+		scores: make([]float64, r.opts.slots),
+	}
+
+	// Nodes are immutable, so a node pointer missing from after is stale and
+	// one missing from before is new or changed.
+	stale := make(map[*Node]bool)
+	for _, n := range before {
+		stale[n] = true
+	}
+	var changed []*Node
+	for _, n := range after {
+		if stale[n] {
+			delete(stale, n)
+		} else {
+			changed = append(changed, n)
+		}
+	}
+
+	if old == nil || len(changed) > len(after)/4 {
+		for s := range t.owners {
+			r.rankSlot(t, after, s)
+		}
+		r.slots.Store(t)
+		return
+	}
+
+	copy(t.owners, old.owners)
+	copy(t.scores, old.scores)
+	for s, owner := range t.owners {
+		if owner == nil || stale[owner] {
+			r.rankSlot(t, after, s)
+			continue
+		}
+		slotHash := mix64(uint64(s))
+		for _, n := range changed {
+			if !active(n) {
+				continue
+			}
+			candidate := ScoredNode{node: n, score: r.score(slotHash, n)}
+			if ranksAbove(candidate, ScoredNode{node: t.owners[s], score: t.scores[s]}) {
+				t.owners[s], t.scores[s] = n, candidate.score
+			}
+		}
+	}
+	r.slots.Store(t)
+}
+
+// rankSlot sets the owner of slot s to its highest ranked node of nodes.
+func (r *Ring) rankSlot(t *slotTable, nodes []*Node, s int) {
+	var buf [1]ScoredNode
+	scoredNodes := r.topNInto(buf[:0], nodes, mix64(uint64(s)), 1, active)
+	if len(scoredNodes) == 0 {
+		t.owners[s], t.scores[s] = nil, 0
+		return
+	}
+	t.owners[s], t.scores[s] = scoredNodes[0].node, scoredNodes[0].score
+}
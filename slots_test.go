@@ -0,0 +1,74 @@
+package rendezvous
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestWithSlotTable(t *testing.T) {
+	rv := New(WithSlotTable(1024))
+	if node := rv.Lookup("foo"); node != "" {
+		t.Errorf("Expected no node but got %s", node)
+	}
+
+	// Every change must leave the table as if it had been built from scratch.
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		name := "n" + strconv.Itoa(random.Intn(40))
+		switch random.Intn(5) {
+		case 0, 1:
+			rv.AddWithWeight(name, 0.5+random.Float64())
+		case 2:
+			rv.Remove(name)
+		case 3:
+			rv.Drain(name)
+		case 4:
+			rv.Activate(name)
+		}
+
+		if rv.slots.Load() == nil {
+			continue
+		}
+		built := &slotTable{owners: make([]*Node, 1024), scores: make([]float64, 1024)}
+		for s := range built.owners {
+			rv.rankSlot(built, rv.load(), s)
+		}
+		for s, owner := range rv.slots.Load().owners {
+			if owner != built.owners[s] {
+				t.Fatalf("Expected slot %d to be owned by %v but got %v after %d changes", s, built.owners[s], owner, i+1)
+			}
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		expected := rv.slots.Load().owners[rv.Hash(key)%1024]
+		if node := rv.Lookup(key); node != expected.name || rv.LookupNode(key) != expected {
+			t.Fatalf("Expected %s but got %s", expected.name, node)
+		}
+	}
+}
+
+func TestWithSlotTable_Owners(t *testing.T) {
+	rv := New(WithSlotTable(64))
+	for i := 0; i < 20; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), 1+float64(i%3))
+	}
+	rv.Drain("n3")
+	testOwnersAgree(t, rv)
+}
+
+func BenchmarkRing_Lookup_SlotTable(b *testing.B) {
+	names := make([]string, 10000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv := New(WithSlotTable(16384))
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.Lookup("k" + strconv.Itoa(i))
+	}
+}
@@ -0,0 +1,92 @@
+package rendezvous
+
+import (
+	"sort"
+	"time"
+)
+
+// slowStartSteps is the number of weight increases over a slow start.
+const slowStartSteps = 20
+
+// warmWeight returns the weight the node is scored with, which is below its
+// weight while it slow starts.
+func (n *Node) warmWeight() float64 {
+	if n.cold > 0 {
+		return n.weight * (1 - n.cold)
+	}
+	return n.weight
+}
+
+// startRamps makes the nodes of nodes missing from before slow start by
+// replacing them with cold copies. Nodes populating an empty ring start warm,
+// as ramping them all alike would not change any placement. The caller must
+// hold the mutex.
+func (r *Ring) startRamps(before, nodes []*Node) {
+	if len(before) == 0 {
+		return
+	}
+
+	now := time.Now()
+	i := 0
+	for j, n := range nodes {
+		for i < len(before) && before[i].name < n.name {
+			i++
+		}
+		if i < len(before) && before[i].name == n.name {
+			continue
+		}
+
+		c := *n
+		c.cold = 1
+		nodes[j] = &c
+		if r.ramps == nil {
+			r.ramps = make(map[string]time.Time)
+		}
+		r.ramps[n.name] = now
+	}
+	r.scheduleRamps()
+}
+
+// scheduleRamps starts the timer advancing slow starts, if any. The caller
+// must hold the mutex.
+func (r *Ring) scheduleRamps() {
+	if len(r.ramps) > 0 && r.rampTimer == nil {
+		r.rampTimer = time.AfterFunc(r.opts.slowStart/slowStartSteps, r.advanceRamps)
+	}
+}
+
+// advanceRamps raises the weights of slow starting nodes in proportion to
+// the time since they joined, under a single write.
+func (r *Ring) advanceRamps() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	nodes := r.load()
+	var updated []*Node
+	for name, start := range r.ramps {
+		ix := sort.Search(len(nodes), cmp(nodes, name))
+		if ix == len(nodes) || nodes[ix].name != name {
+			delete(r.ramps, name)
+			continue
+		}
+
+		cold := 1 - float64(time.Since(start))/float64(r.opts.slowStart)
+		if cold <= 0 {
+			cold = 0
+			delete(r.ramps, name)
+		}
+		if updated == nil {
+			updated = make([]*Node, len(nodes))
+			copy(updated, nodes)
+		}
+		c := *nodes[ix]
+		c.cold = cold
+		updated[ix] = &c
+	}
+	if updated != nil {
+		r.store(updated)
+	}
+
+	r.rampTimer = nil
+	r.scheduleRamps()
+}
@@ -0,0 +1,58 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithSlowStart(t *testing.T) {
+	rv := New(WithSlowStart(100*time.Millisecond), WithNodes("a", "b", "c"))
+	if n, _ := rv.get("a"); n.cold != 0 {
+		t.Fatalf("Expected the initial nodes to start warm")
+	}
+
+	rv.Add("d")
+	if rv.Weight("d") != 1 {
+		t.Errorf("Expected Weight to report the full weight")
+	}
+	owned := func() int {
+		count := 0
+		for i := 0; i < 1000; i++ {
+			if rv.Lookup(strconv.Itoa(i)) == "d" {
+				count++
+			}
+		}
+		return count
+	}
+	if count := owned(); count != 0 {
+		t.Errorf("Expected a cold node to own no keys but got %d", count)
+	}
+
+	clone := rv.Clone()
+	eventually(t, func() bool { n, _ := rv.get("d"); return n.cold == 0 })
+	if count := owned(); count < 150 {
+		t.Errorf("Expected a warm node to own its share of keys but got %d", count)
+	}
+	eventually(t, func() bool { n, _ := clone.get("d"); return n.cold == 0 })
+}
+
+func TestWithSlowStart_Ramp(t *testing.T) {
+	rv := New(WithSlowStart(time.Hour), WithNodes("a"))
+	rv.Add("b")
+
+	rv.mutex.Lock()
+	rv.ramps["b"] = time.Now().Add(-45 * time.Minute)
+	rv.mutex.Unlock()
+	rv.advanceRamps()
+
+	n, _ := rv.get("b")
+	if w := n.warmWeight(); w < 0.74 || w > 0.76 {
+		t.Errorf("Expected three quarters of the weight but got %v", w)
+	}
+	rv.Remove("b")
+	rv.advanceRamps()
+	if len(rv.ramps) != 0 {
+		t.Errorf("Expected the ramps of removed nodes to be discarded")
+	}
+}
@@ -0,0 +1,69 @@
+package rendezvous
+
+import "sync"
+
+// A snapshot is an immutable membership of a Ring. Besides the sorted nodes,
+// it keeps their names, hashes and scoring weights in columns, so scoring
+// loops and name searches walk contiguous memory instead of dereferencing a
+// pointer per node.
+type snapshot struct {
+	nodes []*Node
+	columns
+
+	// index maps names to indexes. It is built by the first name lookup of
+	// the snapshot, so writes do not pay for it.
+	indexOnce sync.Once
+	index     map[string]int32
+}
+
+// columns hold the names, hashes and warm weights of nodes at the same
+// indexes. Names are concatenated into a single string, see nameArena, so
+// the columns hold no pointer per node for the garbage collector to scan.
+type columns struct {
+	names   string
+	offsets []uint32
+	hashes  []uint64
+	weights []float64
+}
+
+func newSnapshot(nodes []*Node) *snapshot {
+	s := &snapshot{
+		nodes: nodes,
+		columns: columns{
+			hashes:  make([]uint64, len(nodes)),
+			weights: make([]float64, len(nodes)),
+		},
+	}
+	s.names, s.offsets = nameArena(nodes)
+	for i, n := range nodes {
+		s.hashes[i], s.weights[i] = n.hash, n.warmWeight()
+	}
+	return s
+}
+
+// indexOf returns the index of the named node, and false if there is none.
+func (s *snapshot) indexOf(name string) (int, bool) {
+	s.indexOnce.Do(func() {
+		s.index = make(map[string]int32, len(s.nodes))
+		for i := range s.nodes {
+			s.index[s.name(i)] = int32(i)
+		}
+	})
+	ix, ok := s.index[name]
+	return int(ix), ok
+}
+
+// slice returns the columns of the nodes at [lo, hi).
+func (c columns) slice(lo, hi int) columns {
+	return columns{names: c.names, offsets: c.offsets[lo : hi+1], hashes: c.hashes[lo:hi], weights: c.weights[lo:hi]}
+}
+
+// columnsOf returns the columns of nodes if nodes is the current snapshot,
+// and empty columns for any other slice of nodes.
+func (r *Ring) columnsOf(nodes []*Node) columns {
+	s := r.nodes.Load()
+	if s == nil || len(nodes) == 0 || len(s.nodes) != len(nodes) || &s.nodes[0] != &nodes[0] {
+		return columns{}
+	}
+	return s.columns
+}
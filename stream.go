@@ -0,0 +1,66 @@
+package rendezvous
+
+// EachCandidate calls fn with the nodes lookups may select for key, from
+// highest to lowest score, until fn returns false, for loops trying nodes
+// until one accepts. It ranks the nodes LookupTopN does, in the same order,
+// but only orders as many as fn consumes: every node is scored once, and each
+// candidate then costs O(log n), so stopping early on a large ring avoids
+// the full sort of LookupAll.
+func (r *Ring) EachCandidate(key string, fn func(n *Node) bool) {
+	r.stream(r.load(), r.computeHash(key), active, func(s ScoredNode) bool {
+		return fn(s.node)
+	})
+}
+
+// stream yields the nodes of the snapshot accepted by accept, a nil accept
+// taking every node, in descending order of their scores for keyHash until
+// yield returns false. The scored nodes are arranged into a max-heap in
+// linear time and popped one by one.
+func (r *Ring) stream(nodes []*Node, keyHash uint64, accept func(*Node) bool, yield func(ScoredNode) bool) {
+	p := getScoredNodes()
+	h := (*p)[:0]
+	cols := r.columnsOf(nodes)
+	for i, node := range nodes {
+		if accept != nil && !accept(node) {
+			continue
+		}
+		var score float64
+		if cols.hashes != nil {
+			score = r.scoreHash(keyHash, cols.hashes[i], cols.weights[i])
+		} else {
+			score = r.score(keyHash, node)
+		}
+		h = append(h, ScoredNode{node: node, score: score})
+	}
+	defer putScoredNodes(p, h)
+
+	for i := len(h)/2 - 1; i >= 0; i-- {
+		siftDownMax(h, i)
+	}
+	for end := len(h); end > 0; end-- {
+		if !yield(h[0]) {
+			return
+		}
+		h[0] = h[end-1]
+		siftDownMax(h[:end-1], 0)
+	}
+}
+
+// siftDownMax maintains h as a max-heap ordered by rank, see ranksAbove.
+func siftDownMax(h []ScoredNode, i int) {
+	for {
+		largest := i
+		left, right := 2*i+1, 2*i+2
+		if left < len(h) && ranksAbove(h[left], h[largest]) {
+			largest = left
+		}
+		if right < len(h) && ranksAbove(h[right], h[largest]) {
+			largest = right
+		}
+		if largest == i {
+			return
+		}
+		h[largest], h[i] = h[i], h[largest]
+		i = largest
+	}
+}
@@ -0,0 +1,59 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_EachCandidate(t *testing.T) {
+	for _, size := range []int{0, 1, 5, 1000} {
+		rv := New()
+		for i := 0; i < size; i++ {
+			rv.Add("n" + strconv.Itoa(i))
+		}
+		if size > 0 {
+			rv.Drain("n0")
+		}
+
+		for i := 0; i < 20; i++ {
+			key := "k" + strconv.Itoa(i)
+			var names []string
+			rv.EachCandidate(key, func(n *Node) bool {
+				names = append(names, n.Name())
+				return true
+			})
+			if expected := rv.LookupTopN(key, size); !reflect.DeepEqual(names, expected) && len(names)+len(expected) > 0 {
+				t.Fatalf("Expected %v for %s but got %v", expected, key, names)
+			}
+		}
+	}
+
+	rv := New(WithNodes("a", "b", "c"))
+	calls := 0
+	rv.EachCandidate("foo", func(n *Node) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Expected iteration to stop after the first candidate but got %d calls", calls)
+	}
+}
+
+func BenchmarkRing_EachCandidate(b *testing.B) {
+	names := make([]string, 20000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv := New()
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tried := 0
+		rv.EachCandidate("k"+strconv.Itoa(i), func(n *Node) bool {
+			tried++
+			return tried < 3
+		})
+	}
+}
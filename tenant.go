@@ -0,0 +1,71 @@
+package rendezvous
+
+// Tenants derives per-tenant views of a shared ring. Each tenant ranks the
+// ring's nodes with its own seed, so the placements of different tenants are
+// decorrelated: a node that owns a hot key of one tenant is no more likely
+// than any other to own the same key of another. Views share the ring's
+// nodes and follow its changes, so tenants cost no node storage of their
+// own.
+type Tenants struct {
+	ring *Ring
+}
+
+// NewTenants returns the tenant views of ring.
+func NewTenants(ring *Ring) *Tenants {
+	return &Tenants{ring: ring}
+}
+
+// Ring returns the shared ring, for example to add or drain nodes.
+func (t *Tenants) Ring() *Ring {
+	return t.ring
+}
+
+// Tenant returns the view of the named tenant, seeded with a hash of its
+// name.
+func (t *Tenants) Tenant(name string) TenantRing {
+	return t.TenantWithSeed(mix64(t.ring.computeHash(name) ^ 0x74656e616e74))
+}
+
+// TenantWithSeed returns the view of a tenant with an explicit seed, for
+// tenants whose placements must survive a rename.
+func (t *Tenants) TenantWithSeed(seed uint64) TenantRing {
+	return TenantRing{ring: t.ring, seed: seed}
+}
+
+// A TenantRing is a tenant's view of a shared ring, see Tenants. Keys are
+// hashed with the tenant's seed before ranking, so pins, which match
+// unseeded key hashes, do not apply to tenant lookups.
+type TenantRing struct {
+	ring *Ring
+	seed uint64
+}
+
+// Seed returns the tenant's seed.
+func (t TenantRing) Seed() uint64 {
+	return t.seed
+}
+
+// Hash returns the tenant's hash of key.
+func (t TenantRing) Hash(key string) uint64 {
+	return mix64(t.ring.computeHash(key) ^ t.seed)
+}
+
+// Lookup is like Ring.Lookup for the tenant.
+func (t TenantRing) Lookup(key string) string {
+	return t.ring.lookup(t.Hash(key))
+}
+
+// LookupBytes is like Ring.LookupBytes for the tenant.
+func (t TenantRing) LookupBytes(key []byte) string {
+	return t.ring.lookup(mix64(t.ring.computeHashBytes(key) ^ t.seed))
+}
+
+// LookupTopN is like Ring.LookupTopN for the tenant.
+func (t TenantRing) LookupTopN(key string, n int) []string {
+	return t.ring.lookupTopN(t.Hash(key), n)
+}
+
+// LookupAll is like Ring.LookupAll for the tenant.
+func (t TenantRing) LookupAll(key string) []string {
+	return t.ring.lookupAll(t.Hash(key))
+}
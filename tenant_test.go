@@ -0,0 +1,43 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTenants(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c", "d"))
+	tenants := NewTenants(rv)
+	acme, globex := tenants.Tenant("acme"), tenants.Tenant("globex")
+	if acme.Seed() == globex.Seed() || tenants.Tenant("acme").Seed() != acme.Seed() {
+		t.Errorf("Expected stable, distinct seeds")
+	}
+
+	// Owners of the same key agree about as often as chance has it.
+	agree := 0
+	for i := 0; i < 4000; i++ {
+		key := strconv.Itoa(i)
+		if acme.Lookup(key) == globex.Lookup(key) {
+			agree++
+		}
+		if acme.LookupBytes([]byte(key)) != acme.Lookup(key) || acme.LookupTopN(key, 1)[0] != acme.Lookup(key) {
+			t.Fatalf("Expected the lookups of %s to agree", key)
+		}
+	}
+	if agree < 800 || agree > 1200 {
+		t.Errorf("Expected about 1000 shared owners but got %d", agree)
+	}
+
+	view := tenants.TenantWithSeed(42)
+	if nodes := view.LookupAll("foo"); len(nodes) != 4 || !reflect.DeepEqual(nodes, rv.LookupAllHash(view.Hash("foo"))) {
+		t.Errorf("Expected the ranking of the seeded hash but got %v", nodes)
+	}
+
+	// Views follow changes to the shared ring.
+	owner := acme.Lookup("foo")
+	tenants.Ring().Drain(owner)
+	if acme.Lookup("foo") == owner {
+		t.Errorf("Expected the drained %s to be skipped", owner)
+	}
+}
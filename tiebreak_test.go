@@ -0,0 +1,79 @@
+package rendezvous
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestRing_TieBreak(t *testing.T) {
+	constant := WithScoreFunc(func(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+		return 1
+	})
+
+	for _, test := range []struct {
+		name  string
+		size  int
+		opts  []Option
+		equal bool
+	}{
+		{"Heap", 20, nil, false},
+		{"Batch", 1000, nil, false},
+		{"Parallel", 1000, []Option{WithParallelScoring(16)}, false},
+		{"EqualHashes", 20, []Option{WithHasher(func(string) uint64 { return 7 })}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+			names := make([]string, test.size)
+			for i := range names {
+				names[i] = "n" + strconv.Itoa(i)
+			}
+			rv := New(append(test.opts, constant)...)
+			rv.AddAll(names)
+
+			expected := append([]string(nil), names...)
+			sort.Slice(expected, func(i, j int) bool {
+				a, b := rv.Hash(expected[i]), rv.Hash(expected[j])
+				if a != b {
+					return a < b
+				}
+				return expected[i] < expected[j]
+			})
+			if test.equal && !sort.StringsAreSorted(expected) {
+				t.Fatalf("Expected equal hashes to order by name")
+			}
+
+			for _, key := range []string{"a", "b"} {
+				if all := rv.LookupAll(key); !reflect.DeepEqual(all, expected) {
+					t.Errorf("Expected LookupAll to break ties by hash and name")
+				}
+				if top := rv.LookupTopN(key, 3); !reflect.DeepEqual(top, expected[:3]) {
+					t.Errorf("Expected %v but got %v", expected[:3], top)
+				}
+				if node := rv.Lookup(key); node != expected[0] {
+					t.Errorf("Expected %s but got %s", expected[0], node)
+				}
+				var streamed []string
+				rv.EachCandidate(key, func(n *Node) bool {
+					streamed = append(streamed, n.Name())
+					return len(streamed) < 3
+				})
+				if !reflect.DeepEqual(streamed, expected[:3]) {
+					t.Errorf("Expected %v but got %v", expected[:3], streamed)
+				}
+			}
+		})
+	}
+
+	t.Run("SlotTable", func(t *testing.T) {
+		rv := New(constant, WithSlotTable(16), WithNodes("b", "c"))
+		rv.Add("a")
+		expected := New(constant, WithNodes("a", "b", "c")).Lookup("k")
+		if node := rv.Lookup("k"); node != expected {
+			t.Errorf("Expected incremental slot updates to break ties like rankings: %s, %s", expected, node)
+		}
+	})
+}
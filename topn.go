@@ -0,0 +1,126 @@
+package rendezvous
+
+// topNInto selects the n highest scoring nodes of the snapshot for keyHash,
+// ordered from highest to lowest score, reusing the storage of buf. Nodes
+// rejected by accept are skipped; a nil accept takes every node. It keeps a
+// bounded min-heap of the best n candidates, so selecting a few nodes out of a
+// large ring costs O(len(nodes) log n) instead of a full sort. Rankings of a
+// large part of a large ring are left to rankBatch, and rings above the
+// WithParallelScoring size are split across goroutines.
+func (r *Ring) topNInto(buf []ScoredNode, nodes []*Node, keyHash uint64, n int, accept func(*Node) bool) []ScoredNode {
+	if n <= 0 {
+		return buf[:0]
+	}
+	cols := r.columnsOf(nodes)
+	if r.opts.parallel > 0 && len(nodes) >= r.opts.parallel {
+		return r.topNParallel(buf, nodes, cols, keyHash, n, accept)
+	}
+	return r.topNShard(buf, nodes, cols, keyHash, n, accept)
+}
+
+// topNShard is topNInto on a single goroutine. When nodes come with their
+// columns, only nodes scoring high enough to enter the heap are dereferenced
+// and passed to accept.
+func (r *Ring) topNShard(buf []ScoredNode, nodes []*Node, cols columns, keyHash uint64, n int, accept func(*Node) bool) []ScoredNode {
+	if len(nodes) >= batchMinNodes && n >= len(nodes)/8 {
+		return r.rankBatch(buf, nodes, cols, keyHash, n, accept)
+	}
+	return r.topNHeap(buf, nodes, cols, keyHash, n, accept)
+}
+
+// topNHeap is topNShard selecting the nodes with a bounded min-heap.
+func (r *Ring) topNHeap(buf []ScoredNode, nodes []*Node, cols columns, keyHash uint64, n int, accept func(*Node) bool) []ScoredNode {
+	h := buf[:0]
+	if cols.hashes != nil {
+		for i, hash := range cols.hashes {
+			score := r.scoreHash(keyHash, hash, cols.weights[i])
+			if len(h) == n && score < h[0].score {
+				continue
+			}
+			if node := nodes[i]; accept == nil || accept(node) {
+				h = pushTopN(h, n, node, score)
+			}
+		}
+	} else {
+		for _, node := range nodes {
+			if accept == nil || accept(node) {
+				h = pushTopN(h, n, node, r.score(keyHash, node))
+			}
+		}
+	}
+
+	// Repeatedly moving the minimum to the back leaves h in descending order
+	// without the allocations of sort.Slice.
+	for end := len(h) - 1; end > 0; end-- {
+		h[0], h[end] = h[end], h[0]
+		siftDown(h[:end], 0)
+	}
+
+	return h
+}
+
+// pushTopN adds node to the min-heap h of at most n best nodes if its score
+// is high enough.
+func pushTopN(h []ScoredNode, n int, node *Node, score float64) []ScoredNode {
+	s := ScoredNode{node: node, score: score}
+	switch {
+	case len(h) < n:
+		h = append(h, s)
+		siftUp(h, len(h)-1)
+	case ranksAbove(s, h[0]):
+		h[0] = s
+		siftDown(h, 0)
+	}
+	return h
+}
+
+// ranksAbove reports whether a ranks before b: by higher score, and for
+// equal scores by lower node hash and then by name. Ties are thus broken the
+// same way whatever order nodes are scored in, on every run and machine.
+func ranksAbove(a, b ScoredNode) bool {
+	switch {
+	case a.score != b.score:
+		return a.score > b.score
+	case a.node.hash != b.node.hash:
+		return a.node.hash < b.node.hash
+	default:
+		return a.node.name < b.node.name
+	}
+}
+
+// active accepts the nodes that lookups may return. Nodes weighted zero are
+// members that are never selected, see AddWithWeight.
+func active(n *Node) bool {
+	return !n.drained && !n.unhealthy && n.weight > 0
+}
+
+// siftUp and siftDown maintain h as a min-heap ordered by rank, see
+// ranksAbove, with the lowest ranked node at the root.
+func siftUp(h []ScoredNode, i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !ranksAbove(h[parent], h[i]) {
+			return
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func siftDown(h []ScoredNode, i int) {
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < len(h) && ranksAbove(h[smallest], h[left]) {
+			smallest = left
+		}
+		if right < len(h) && ranksAbove(h[smallest], h[right]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h[smallest], h[i] = h[i], h[smallest]
+		i = smallest
+	}
+}
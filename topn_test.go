@@ -0,0 +1,127 @@
+package rendezvous
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTopNInto(t *testing.T) {
+	rv := New()
+	for i := 0; i < 500; i++ {
+		rv.AddWithWeight("n"+strconv.Itoa(i), float64(i%5+1))
+	}
+	nodes := rv.load()
+
+	for i := 0; i < 50; i++ {
+		keyHash := rv.computeHash("k" + strconv.Itoa(i))
+		ranked := rv.rankInto(nil, nodes, keyHash)
+		for _, n := range []int{0, 1, 3, 17, 499, 500, 501} {
+			top := rv.topNInto(nil, nodes, keyHash, n, nil)
+
+			expected := ranked
+			if n < len(ranked) {
+				expected = ranked[:n]
+			}
+			if len(top) != len(expected) {
+				t.Fatalf("Expected %d nodes but got %d", len(expected), len(top))
+			}
+			if len(top) > 0 && !reflect.DeepEqual(top, expected) {
+				t.Errorf("Expected %v but got %v", expected, top)
+			}
+		}
+	}
+}
+
+func TestRing_LookupTopN_OutOfRange(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c"})
+
+	if names := rv.LookupTopN("foo", -1); len(names) != 0 {
+		t.Errorf("Expected no nodes but got %v", names)
+	}
+	if names := rv.LookupTopN("foo", 10); !reflect.DeepEqual(names, rv.LookupAll("foo")) {
+		t.Errorf("Expected %v but got %v", rv.LookupAll("foo"), names)
+	}
+}
+
+func BenchmarkRing_LookupTopN(b *testing.B) {
+	rv := New()
+	names := make([]string, 100000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.LookupTopN("k"+strconv.Itoa(i), 3)
+	}
+}
+
+func TestRing_AppendTopN(t *testing.T) {
+	rv := New()
+	rv.AddAll([]string{"a", "b", "c", "d", "e"})
+
+	dst := []string{"x"}
+	dst = rv.AppendTopN(dst, "foo", 3)
+	expected := []string{"x", "d", "b", "c"}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("Expected %v but got %v", expected, dst)
+	}
+
+	dst = rv.AppendTopN(dst[:0], "foo", 100)
+	if expected := rv.LookupAll("foo"); !reflect.DeepEqual(dst, expected) {
+		t.Errorf("Expected %v but got %v", expected, dst)
+	}
+
+	// n is clamped to the ring size before allocating.
+	dst = rv.AppendTopN(dst[:0], "foo", math.MaxInt)
+	if expected := rv.LookupAll("foo"); !reflect.DeepEqual(dst, expected) {
+		t.Errorf("Expected %v but got %v", expected, dst)
+	}
+
+	t.Run("DoesNotAllocate", func(t *testing.T) {
+		dst := make([]string, 0, 3)
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = rv.AppendTopN(dst[:0], "foo", 3)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations but got %v", allocs)
+		}
+	})
+}
+
+func BenchmarkRing_LookupAll(b *testing.B) {
+	rv := New()
+	names := make([]string, 20000)
+	for i := range names {
+		names[i] = "n" + strconv.Itoa(i)
+	}
+	rv.AddAll(names)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.LookupAll("k" + strconv.Itoa(i))
+	}
+}
+
+func TestRing_LookupTopN_Pooled(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+
+	rv := New()
+	for i := 0; i < 1000; i++ {
+		rv.Add("n" + strconv.Itoa(i))
+	}
+
+	// Only the returned names should be allocated once the pools are warm.
+	if allocs := testing.AllocsPerRun(100, func() { rv.LookupTopN("foo", 3) }); allocs > 1 {
+		t.Errorf("Expected one allocation but got %.0f", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { rv.LookupAll("foo") }); allocs > 1 {
+		t.Errorf("Expected one allocation but got %.0f", allocs)
+	}
+}
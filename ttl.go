@@ -0,0 +1,111 @@
+package rendezvous
+
+import (
+	"sort"
+	"time"
+)
+
+// A lease tracks the TTL of a node added with AddWithTTL.
+type lease struct {
+	ttl      time.Duration
+	deadline time.Time
+	timer    *time.Timer
+	expired  bool
+}
+
+// AddWithTTL adds the named node, or updates its weight, and expires it
+// unless Heartbeat is called at least every ttl. An expired node is removed,
+// or drained with WithTTLDrain. The TTL is cleared when the node is removed.
+// If weight is invalid, see CheckWeight, nothing changes and
+// ErrInvalidWeight is returned.
+func (r *Ring) AddWithTTL(name string, weight float64, ttl time.Duration) error {
+	if err := CheckWeight(weight); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	l, ok := r.leases[name]
+	if !ok {
+		l = &lease{}
+		l.timer = time.AfterFunc(ttl, func() { r.expire(name, l) })
+		if r.leases == nil {
+			r.leases = make(map[string]*lease)
+		}
+		r.leases[name] = l
+	}
+	renewed := l.renew(ttl)
+
+	r.upsertLocked(name, func(n *Node) {
+		n.weight = weight
+		if renewed {
+			n.drained = false
+		}
+	})
+	return nil
+}
+
+// Heartbeat renews the TTL of the named node and puts it back into rotation
+// if it was drained on expiry. It reports whether the node has a TTL.
+func (r *Ring) Heartbeat(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	l, ok := r.leases[name]
+	if !ok {
+		return false
+	}
+	if l.renew(l.ttl) {
+		r.updateLocked(name, func(n *Node) {
+			n.drained = false
+		})
+	}
+	return true
+}
+
+// renew extends the lease by ttl and reports whether it had expired.
+func (l *lease) renew(ttl time.Duration) bool {
+	l.ttl = ttl
+	l.deadline = time.Now().Add(ttl)
+	l.timer.Reset(ttl)
+	expired := l.expired
+	l.expired = false
+	return expired
+}
+
+// expire removes or drains the named node once its lease has passed its
+// deadline.
+func (r *Ring) expire(name string, l *lease) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.leases[name] != l || l.expired {
+		return
+	}
+	// A renewal may race with the timer firing.
+	if d := time.Until(l.deadline); d > 0 {
+		l.timer.Reset(d)
+		return
+	}
+
+	if r.opts.ttlDrain {
+		l.expired = true
+		r.updateLocked(name, func(n *Node) {
+			n.drained = true
+		})
+	} else {
+		r.removeLocked(name)
+	}
+}
+
+// pruneLeases stops the leases of nodes missing from nodes. The caller must
+// hold the mutex.
+func (r *Ring) pruneLeases(nodes []*Node) {
+	for name, l := range r.leases {
+		if ix := sort.Search(len(nodes), cmp(nodes, name)); ix == len(nodes) || nodes[ix].name != name {
+			l.timer.Stop()
+			delete(r.leases, name)
+		}
+	}
+}
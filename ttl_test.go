@@ -0,0 +1,76 @@
+package rendezvous
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatalf("Expected the condition to hold eventually")
+}
+
+func TestRing_AddWithTTL(t *testing.T) {
+	rv := New(WithNodes("a"))
+	rv.AddWithTTL("b", 2, 50*time.Millisecond)
+	if !rv.Contains("b") || rv.Weight("b") != 2 {
+		t.Fatalf("Expected b to be added with weight 2")
+	}
+	if err := rv.AddWithTTL("c", -3, time.Hour); !errors.Is(err, ErrInvalidWeight) || rv.Contains("c") {
+		t.Errorf("Expected %v for a negative weight but got %v", ErrInvalidWeight, err)
+	}
+
+	// Heartbeats keep the node alive well past its TTL.
+	for i := 0; i < 10; i++ {
+		time.Sleep(5 * time.Millisecond)
+		if !rv.Heartbeat("b") {
+			t.Fatalf("Expected b to have a TTL")
+		}
+	}
+	if !rv.Contains("b") {
+		t.Fatalf("Expected b to be kept alive by heartbeats")
+	}
+
+	eventually(t, func() bool { return !rv.Contains("b") })
+	if rv.Heartbeat("b") || rv.Heartbeat("a") {
+		t.Errorf("Expected no TTL for removed or permanent nodes")
+	}
+	if !rv.Contains("a") {
+		t.Errorf("Expected a to be kept")
+	}
+}
+
+func TestRing_AddWithTTL_Removed(t *testing.T) {
+	rv := New()
+	rv.AddWithTTL("a", 1, 10*time.Millisecond)
+	rv.Remove("a")
+	rv.Add("a")
+
+	// The TTL was cleared with the removal and does not expire the re-added
+	// node.
+	time.Sleep(30 * time.Millisecond)
+	if !rv.Contains("a") {
+		t.Errorf("Expected a to be kept")
+	}
+}
+
+func TestWithTTLDrain(t *testing.T) {
+	rv := New(WithTTLDrain())
+	rv.AddWithTTL("a", 1, 10*time.Millisecond)
+
+	eventually(t, func() bool { return rv.Drained("a") })
+	if !rv.Contains("a") {
+		t.Fatalf("Expected a to stay a member")
+	}
+
+	if !rv.Heartbeat("a") || rv.Drained("a") {
+		t.Errorf("Expected a heartbeat to activate a")
+	}
+	eventually(t, func() bool { return rv.Drained("a") })
+}
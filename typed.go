@@ -0,0 +1,115 @@
+package rendezvous
+
+// A TypedRing is a Ring whose nodes each carry a payload of type T, such as
+// a client connection or an address. The payload lives in the same node
+// snapshot as the name and weight, so lookups can never observe a node
+// without its payload.
+type TypedRing[T any] struct {
+	ring *Ring
+}
+
+// NewTyped creates a TypedRing configured by opts, see New. Nodes added with
+// WithNodes carry the zero value of T.
+func NewTyped[T any](opts ...Option) *TypedRing[T] {
+	return &TypedRing[T]{ring: New(opts...)}
+}
+
+// NewTypedWithHasher creates a TypedRing hashing with the given stateless
+// hash function, see NewWithHasher.
+//
+// Deprecated: Use NewTyped[T](WithHasher(hasher)) instead.
+func NewTypedWithHasher[T any](hasher func(string) uint64) *TypedRing[T] {
+	return &TypedRing[T]{ring: NewWithHasher(hasher)}
+}
+
+// Ring returns the underlying Ring. Nodes added through it carry the zero
+// value of T.
+func (r *TypedRing[T]) Ring() *Ring {
+	return r.ring
+}
+
+func (r *TypedRing[T]) Add(name string, value T) {
+	r.AddWithWeight(name, defaultWeight, value)
+}
+
+// AddWithWeight adds the named node, or replaces the weight and payload of an
+// existing one.
+func (r *TypedRing[T]) AddWithWeight(name string, weight float64, value T) {
+	r.ring.upsert(name, func(n *Node) {
+		n.weight = weight
+		n.value = value
+	})
+}
+
+func (r *TypedRing[T]) Remove(name string) {
+	r.ring.Remove(name)
+}
+
+// Get returns the payload of the named node.
+func (r *TypedRing[T]) Get(name string) (T, bool) {
+	n, ok := r.ring.get(name)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return valueOf[T](n), true
+}
+
+func (r *TypedRing[T]) LookupAll(key string) []T {
+	scoredNodes := r.ring.rank(r.ring.computeHash(key))
+
+	values := make([]T, 0, len(scoredNodes))
+	for _, scoredNode := range scoredNodes {
+		values = append(values, valueOf[T](scoredNode.node))
+	}
+
+	return values
+}
+
+func (r *TypedRing[T]) LookupTopN(key string, n int) []T {
+	scoredNodes := r.ring.topN(r.ring.computeHash(key), n)
+
+	values := make([]T, len(scoredNodes))
+	for i, scoredNode := range scoredNodes {
+		values[i] = valueOf[T](scoredNode.node)
+	}
+
+	return values
+}
+
+// Lookup returns the payload of the node owning key, the node Ring.Lookup
+// returns, including a member fallback of WithFallback. It returns false if
+// there is no such node.
+func (r *TypedRing[T]) Lookup(key string) (T, bool) {
+	n := r.ring.lookupNode(r.ring.computeHash(key))
+	if n == nil && r.ring.opts.fallback != "" {
+		n, _ = r.ring.get(r.ring.opts.fallback)
+	}
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	r.ring.observeLookup(n.name)
+	return valueOf[T](n), true
+}
+
+func (r *TypedRing[T]) Contains(name string) bool {
+	return r.ring.Contains(name)
+}
+
+func (r *TypedRing[T]) Weight(name string) float64 {
+	return r.ring.Weight(name)
+}
+
+func (r *TypedRing[T]) List() []string {
+	return r.ring.List()
+}
+
+func (r *TypedRing[T]) Len() int {
+	return r.ring.Len()
+}
+
+func valueOf[T any](n *Node) T {
+	value, _ := n.value.(T)
+	return value
+}
@@ -0,0 +1,124 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type backend struct {
+	addr string
+	port int
+}
+
+func TestTypedRing_Lookup(t *testing.T) {
+	t.Run("ReturnsPayload", func(t *testing.T) {
+		rv := New()
+		tr := NewTyped[backend]()
+		for i, name := range []string{"a", "b", "c", "d", "e"} {
+			rv.Add(name)
+			tr.Add(name, backend{addr: name, port: 8000 + i})
+		}
+
+		for i := 0; i < 100; i++ {
+			key := "k" + strconv.Itoa(i)
+			b, ok := tr.Lookup(key)
+			if !ok {
+				t.Fatalf("Expected a payload for %s", key)
+			}
+			if expected := rv.Lookup(key); b.addr != expected {
+				t.Errorf("Expected %s but got %s", expected, b.addr)
+			}
+		}
+	})
+
+	t.Run("EmptyRing", func(t *testing.T) {
+		tr := NewTyped[*backend]()
+		if b, ok := tr.Lookup("foo"); ok || b != nil {
+			t.Errorf("Expected no payload but got %v", b)
+		}
+	})
+
+	t.Run("MatchesRingLookup", func(t *testing.T) {
+		for _, opt := range []Option{WithSlotTable(64), WithSkeleton(100), WithBackend(modBackend{})} {
+			tr := NewTyped[string](opt)
+			for i := 0; i < 20; i++ {
+				name := "n" + strconv.Itoa(i)
+				tr.Add(name, name)
+			}
+			tr.Ring().Pin("k0", "n7")
+
+			for i := 0; i < 200; i++ {
+				key := "k" + strconv.Itoa(i)
+				if value, _ := tr.Lookup(key); value != tr.Ring().Lookup(key) {
+					t.Fatalf("Expected %s for %s but got %s", tr.Ring().Lookup(key), key, value)
+				}
+			}
+		}
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		tr := NewTyped[string](WithFallback("spare"))
+		if _, ok := tr.Lookup("foo"); ok {
+			t.Errorf("Expected no payload for a fallback that is not a member")
+		}
+
+		tr.Add("spare", "spare payload")
+		tr.Ring().Drain("spare")
+		if value, ok := tr.Lookup("foo"); !ok || value != "spare payload" {
+			t.Errorf("Expected the fallback's payload but got %q", value)
+		}
+	})
+}
+
+func TestTypedRing_LookupTopN(t *testing.T) {
+	tr := NewTyped[string]()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		tr.Add(name, "payload-"+name)
+	}
+
+	values := tr.LookupTopN("foo", 3)
+	expected := []string{"payload-d", "payload-b", "payload-c"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v but got %v", expected, values)
+	}
+}
+
+func TestTypedRing_AddWithWeight(t *testing.T) {
+	t.Run("ReplacesPayload", func(t *testing.T) {
+		tr := NewTyped[int]()
+		tr.AddWithWeight("a", 1.0, 1)
+		tr.AddWithWeight("a", 2.0, 2)
+
+		if v, ok := tr.Get("a"); !ok || v != 2 {
+			t.Errorf("Expected 2 but got %v", v)
+		}
+		if w := tr.Weight("a"); w != 2.0 {
+			t.Errorf("Expected 2.0 but got %v", w)
+		}
+	})
+
+	t.Run("PlainAddKeepsPayload", func(t *testing.T) {
+		tr := NewTyped[int]()
+		tr.Add("a", 1)
+		tr.Ring().AddWithWeight("a", 3.0)
+
+		if v, ok := tr.Get("a"); !ok || v != 1 {
+			t.Errorf("Expected 1 but got %v", v)
+		}
+	})
+}
+
+func TestTypedRing_Remove(t *testing.T) {
+	tr := NewTyped[int]()
+	tr.Add("a", 1)
+	tr.Add("b", 2)
+	tr.Remove("a")
+
+	if _, ok := tr.Get("a"); ok {
+		t.Errorf("Expected a to be removed")
+	}
+	if names := tr.List(); !reflect.DeepEqual(names, []string{"b"}) {
+		t.Errorf("Expected [b] but got %v", names)
+	}
+}
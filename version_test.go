@@ -0,0 +1,69 @@
+package rendezvous
+
+import (
+	"testing"
+)
+
+func TestRing_Version(t *testing.T) {
+	rv := New()
+	if v := rv.Version(); v != 0 {
+		t.Errorf("Expected 0 but got %d", v)
+	}
+
+	last := rv.Version()
+	changes := map[string]func(){
+		"Add":           func() { rv.Add("a") },
+		"AddAll":        func() { rv.AddAll([]string{"b", "c"}) },
+		"AddWithWeight": func() { rv.AddWithWeight("a", 2) },
+		"Drain":         func() { rv.Drain("a") },
+		"Remove":        func() { rv.Remove("a") },
+		"RemoveAll":     func() { rv.RemoveAll([]string{"b"}) },
+	}
+	for _, name := range []string{"Add", "AddAll", "AddWithWeight", "Drain", "Remove", "RemoveAll"} {
+		changes[name]()
+		if v := rv.Version(); v <= last {
+			t.Errorf("Expected %s to increase the version past %d but got %d", name, last, v)
+		}
+		last = rv.Version()
+	}
+
+	rv.Remove("missing")
+	rv.RemoveAll([]string{"missing"})
+	rv.AddAll(nil)
+	rv.Drain("missing")
+	if v := rv.Version(); v != last {
+		t.Errorf("Expected no-ops to keep version %d but got %d", last, v)
+	}
+
+	if v := rv.Clone().Version(); v != last {
+		t.Errorf("Expected clone to keep version %d but got %d", last, v)
+	}
+}
+
+func TestRing_Version_Decode(t *testing.T) {
+	rv := New(WithNodes("a", "b", "c"))
+	rv.Remove("c")
+
+	data, _ := rv.MarshalProto()
+	var decoded Ring
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if v := decoded.Version(); v != rv.Version() {
+		t.Errorf("Expected %d but got %d", rv.Version(), v)
+	}
+
+	// Decoding an older ring still increases the version.
+	newer := New(WithNodes("x"))
+	for i := 0; i < 5; i++ {
+		newer.Add("y")
+	}
+	last := newer.Version()
+	data, _ = rv.MarshalBinary()
+	if err := newer.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if v := newer.Version(); v <= last {
+		t.Errorf("Expected version past %d but got %d", last, v)
+	}
+}
@@ -0,0 +1,174 @@
+package rendezvous
+
+import (
+	"context"
+	"sync"
+)
+
+// A ChangeType identifies the kind of a ChangeEvent.
+type ChangeType int
+
+const (
+	// NodeAdded reports a node joining the ring.
+	NodeAdded ChangeType = iota + 1
+	// NodeRemoved reports a node leaving the ring.
+	NodeRemoved
+	// NodeChanged reports a change to a node's weight, tags or state.
+	NodeChanged
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case NodeAdded:
+		return "added"
+	case NodeRemoved:
+		return "removed"
+	case NodeChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// A ChangeEvent describes a change to a single node of a ring.
+type ChangeEvent struct {
+	Type ChangeType
+	// Old is the node before the change; nil for NodeAdded.
+	Old *Node
+	// New is the node after the change; nil for NodeRemoved.
+	New *Node
+	// Version is the ring version the change was published in.
+	Version uint64
+}
+
+// Name returns the name of the changed node.
+func (e ChangeEvent) Name() string {
+	if e.New != nil {
+		return e.New.name
+	}
+	return e.Old.name
+}
+
+// A listener receives the events of every write, in order, while the ring's
+// mutex is held.
+type listener struct {
+	fn func(ChangeEvent)
+}
+
+// Watch returns a channel receiving an event for every node added to, removed
+// from or changed on the ring, in order, until ctx is done, after which the
+// channel is closed. Events are queued rather than dropped, so writers never
+// block on a slow receiver.
+func (r *Ring) Watch(ctx context.Context) <-chan ChangeEvent {
+	w := &watcher{
+		ch:     make(chan ChangeEvent),
+		notify: make(chan struct{}, 1),
+	}
+	l := r.listen(w.push)
+
+	go func() {
+		defer close(w.ch)
+		defer r.unlisten(l)
+		w.run(ctx)
+	}()
+	return w.ch
+}
+
+// listen registers fn to receive every change to the ring.
+func (r *Ring) listen(fn func(ChangeEvent)) *listener {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	l := &listener{fn: fn}
+	r.listeners = append(r.listeners, l)
+	return l
+}
+
+func (r *Ring) unlisten(l *listener) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	listeners := make([]*listener, 0, len(r.listeners))
+	for _, other := range r.listeners {
+		if other != l {
+			listeners = append(listeners, other)
+		}
+	}
+	r.listeners = listeners
+}
+
+// notifyChanges sends listeners the differences between two node snapshots.
+// Nodes are immutable, so a node that is not the same pointer in both
+// snapshots has changed.
+func (r *Ring) notifyChanges(before, after []*Node, version uint64) {
+	if len(r.listeners) == 0 {
+		return
+	}
+
+	emit := func(e ChangeEvent) {
+		e.Version = version
+		for _, l := range r.listeners {
+			l.fn(e)
+		}
+	}
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case j == len(after) || (i < len(before) && before[i].name < after[j].name):
+			emit(ChangeEvent{Type: NodeRemoved, Old: before[i]})
+			i++
+		case i == len(before) || after[j].name < before[i].name:
+			emit(ChangeEvent{Type: NodeAdded, New: after[j]})
+			j++
+		default:
+			if before[i] != after[j] {
+				emit(ChangeEvent{Type: NodeChanged, Old: before[i], New: after[j]})
+			}
+			i++
+			j++
+		}
+	}
+}
+
+// A watcher queues events for a Watch channel without blocking writers.
+type watcher struct {
+	ch     chan ChangeEvent
+	notify chan struct{}
+
+	mutex  sync.Mutex
+	queued []ChangeEvent
+}
+
+func (w *watcher) push(e ChangeEvent) {
+	w.mutex.Lock()
+	w.queued = append(w.queued, e)
+	w.mutex.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) run(ctx context.Context) {
+	for {
+		w.mutex.Lock()
+		events := w.queued
+		w.queued = nil
+		w.mutex.Unlock()
+
+		for _, e := range events {
+			select {
+			case w.ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-w.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package rendezvous
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, ch <-chan ChangeEvent) ChangeEvent {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an event")
+		return ChangeEvent{}
+	}
+}
+
+func TestRing_Watch(t *testing.T) {
+	rv := New(WithNodes("a", "b"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := rv.Watch(ctx)
+
+	rv.AddAll([]string{"c", "d"})
+	rv.AddWithWeight("a", 2)
+	rv.RemoveAll([]string{"b", "c"})
+	rv.Drain("d")
+
+	type event struct {
+		Type   ChangeType
+		Name   string
+		Weight float64
+	}
+	expected := []event{
+		{NodeAdded, "c", 1},
+		{NodeAdded, "d", 1},
+		{NodeChanged, "a", 2},
+		{NodeRemoved, "b", 1},
+		{NodeRemoved, "c", 1},
+		{NodeChanged, "d", 1},
+	}
+	var actual []event
+	var versions []uint64
+	for range expected {
+		e := receive(t, ch)
+		n := e.New
+		if n == nil {
+			n = e.Old
+		}
+		actual = append(actual, event{e.Type, e.Name(), n.Weight()})
+		versions = append(versions, e.Version)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	if expected := []uint64{2, 2, 3, 4, 4, 5}; !reflect.DeepEqual(expected, versions) {
+		t.Errorf("Expected versions %v but got %v", expected, versions)
+	}
+
+	cancel()
+	for range ch {
+	}
+	rv.Add("e")
+	if n := len(rv.listeners); n != 0 {
+		t.Errorf("Expected the watcher to be removed but got %d listeners", n)
+	}
+}
+
+func TestRing_Watch_SlowReceiver(t *testing.T) {
+	rv := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := rv.Watch(ctx)
+
+	for i := 0; i < 1000; i++ {
+		rv.Add("a")
+		rv.Remove("a")
+	}
+	for i := 0; i < 2000; i++ {
+		e := receive(t, ch)
+		if expected := ChangeType(NodeAdded + ChangeType(i%2)); e.Type != expected {
+			t.Fatalf("Expected %v but got %v", expected, e.Type)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Weighting is a method of turning a node's hash and weight into its score
+// for a key, see WithWeighting.
+type Weighting int
+
+const (
+	// DefaultWeighting is the score formula of rings created without
+	// WithWeighting. It divides the weight by the logarithm of the combined
+	// hash scaled to [0, 1], which rounds hashes close to 1 and so skews the
+	// shares of nodes only when their weights differ by many orders of
+	// magnitude.
+	DefaultWeighting Weighting = iota
+
+	// LogWeighting is the logarithmic method of Schindelhauer and Schomaker
+	// evaluated with full precision: every node gets exactly its weighted
+	// share of keys, whatever the weight ratios. It costs about as much as
+	// DefaultWeighting but places keys differently.
+	LogWeighting
+
+	// IntervalWeighting rounds weights to integers, at least 1 for positive
+	// weights, and scores a node of weight k as the highest of k integer
+	// hashes, as if it were k nodes of weight 1. It needs no floating point
+	// math and is exact for integer weights, but costs time proportional to
+	// the weight, so it suits small integer weights.
+	IntervalWeighting
+
+	// FixedPointWeighting evaluates the formula of DefaultWeighting with
+	// integer arithmetic instead of math.Log and float64 division, so scores
+	// are the same on every platform. It is accurate to about one part in a
+	// million, which places almost every key as DefaultWeighting does, and
+	// costs slightly more on platforms with fast floating point math.
+	FixedPointWeighting
+)
+
+// String returns the name of the method.
+func (w Weighting) String() string {
+	switch w {
+	case DefaultWeighting:
+		return "default"
+	case LogWeighting:
+		return "log"
+	case IntervalWeighting:
+		return "interval"
+	case FixedPointWeighting:
+		return "fixed"
+	}
+	return fmt.Sprintf("Weighting(%d)", int(w))
+}
+
+// WithWeighting scores nodes with the given weighting method instead of the
+// default formula. It panics if w is unknown.
+func WithWeighting(w Weighting) Option {
+	var score ScoreFunc
+	switch w {
+	case DefaultWeighting:
+		score = computeScore
+	case LogWeighting:
+		score = logScore
+	case IntervalWeighting:
+		score = intervalScore
+	case FixedPointWeighting:
+		fixedLog2Once.Do(initFixedLog2)
+		score = fixedScore
+	default:
+		panic("rendezvous: unknown weighting " + w.String())
+	}
+	return func(o *options) {
+		o.score = score
+		o.defaultScore = w == DefaultWeighting
+	}
+}
+
+// score returns the score of n for keyHash.
+func (r *Ring) score(keyHash uint64, n *Node) float64 {
+	return r.scoreHash(keyHash, n.hash, n.warmWeight())
+}
+
+// scoreHash is score for a node with the given hash and warm weight.
+func (r *Ring) scoreHash(keyHash, nodeHash uint64, weight float64) float64 {
+	return r.opts.score(keyHash, nodeHash, weight)
+}
+
+// logScore divides the weight by the exponentially distributed -ln(1-u) for
+// the combined hash u scaled to (0, 1). Small values of u, which decide the
+// highest scores, keep their full precision through log1p.
+func logScore(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+	u := (float64(combineHashes(keyHash, nodeHash)) + 0.5) / (1 << 64)
+	return -nodeWeight / math.Log1p(-u)
+}
+
+func intervalScore(keyHash, nodeHash uint64, nodeWeight float64) float64 {
+	if nodeWeight <= 0 {
+		return 0
+	}
+	k := uint64(math.Max(1, math.Round(nodeWeight)))
+	best := combineHashes(keyHash, nodeHash)
+	for i := uint64(1); i < k; i++ {
+		if h := combineHashes(keyHash, mix64(nodeHash+i)); h > best {
+			best = h
+		}
+	}
+	return float64(best)
+}
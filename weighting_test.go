@@ -0,0 +1,71 @@
+package rendezvous
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestWithWeighting(t *testing.T) {
+	for _, w := range []Weighting{DefaultWeighting, LogWeighting, IntervalWeighting, FixedPointWeighting} {
+		rv := New(WithWeighting(w))
+		rv.AddWithWeight("a", 1)
+		rv.AddWithWeight("b", 2)
+		rv.AddWithWeight("c", 5)
+
+		counts := make(map[string]int)
+		for i := 0; i < 80000; i++ {
+			counts[rv.Lookup(strconv.Itoa(i))]++
+		}
+		for name, count := range counts {
+			expected := 10000 * rv.Weight(name)
+			if math.Abs(float64(count)-expected) > expected*0.05 {
+				t.Errorf("Expected about %.0f keys on %s with %v weighting but got %d", expected, name, w, count)
+			}
+		}
+	}
+
+	if plain, def := New(WithNodes("a", "b", "c")), New(WithNodes("a", "b", "c"), WithWeighting(DefaultWeighting)); plain.Lookup("foo") != def.Lookup("foo") {
+		t.Errorf("Expected the default weighting to keep placements")
+	}
+}
+
+func TestLogWeighting_ExtremeRatios(t *testing.T) {
+	rv := New(WithWeighting(LogWeighting))
+	rv.AddWithWeight("light", 1)
+	rv.AddWithWeight("heavy", 1e4)
+
+	light := 0
+	for i := 0; i < 1000000; i++ {
+		if rv.LookupHash(mix64(uint64(i))) == "light" {
+			light++
+		}
+	}
+	if expected := 1e6 / (1 + 1e4); math.Abs(float64(light)-expected) > expected*0.3 {
+		t.Errorf("Expected about %.0f keys on light but got %d", expected, light)
+	}
+}
+
+func TestWithWeighting_Unknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+	WithWeighting(Weighting(42))
+}
+
+func BenchmarkRing_Lookup_Weighting(b *testing.B) {
+	for _, w := range []Weighting{DefaultWeighting, LogWeighting, IntervalWeighting, FixedPointWeighting} {
+		b.Run(w.String(), func(b *testing.B) {
+			rv := New(WithWeighting(w))
+			for i := 0; i < 100; i++ {
+				rv.AddWithWeight("n"+strconv.Itoa(i), float64(1+i%3))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rv.Lookup("foo")
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package rendezvous
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRing_ZeroWeight(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Default", nil},
+		{"SlotTable", []Option{WithSlotTable(64)}},
+		{"Skeleton", []Option{WithSkeleton(4)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rv := New(append(test.opts, WithNodes("a", "b", "c"))...)
+			rv.AddWithWeight("z", 0)
+
+			if !rv.Contains("z") || len(rv.List()) != 4 || len(rv.LookupAll("k")) != 4 {
+				t.Errorf("Expected z to be a listed member")
+			}
+			for i := 0; i < 1000; i++ {
+				key := "k" + strconv.Itoa(i)
+				if node := rv.Lookup(key); node == "z" || node == "" {
+					t.Fatalf("Expected %s not to map to z but got %q", key, node)
+				}
+				for _, node := range rv.LookupTopN(key, 4) {
+					if node == "z" {
+						t.Fatalf("Expected the top nodes for %s to skip z", key)
+					}
+				}
+			}
+
+			rv.AddWithWeight("a", 0)
+			rv.AddWithWeight("b", 0)
+			rv.AddWithWeight("c", 0)
+			if node := rv.Lookup("k"); node != "" {
+				t.Errorf("Expected no node when all are weighted zero but got %s", node)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rendezvous
+
+// Tags naming the failure domains a node runs in, from the largest to the
+// smallest.
+const (
+	RegionTag = "region"
+	ZoneTag   = "zone"
+	RackTag   = "rack"
+)
+
+// A SpreadConstraint limits how many nodes of a top-N lookup may share a
+// failure domain, see LookupTopNSpread.
+type SpreadConstraint struct {
+	tag   string
+	limit int
+}
+
+// SpreadBy places every node in a different domain of tag, such as RackTag.
+func SpreadBy(tag string) SpreadConstraint {
+	return SpreadByAtMost(tag, 1)
+}
+
+// SpreadByAtMost places at most limit nodes in any domain of tag.
+func SpreadByAtMost(tag string, limit int) SpreadConstraint {
+	return SpreadConstraint{tag: tag, limit: limit}
+}
+
+// LookupTopNSpread is like LookupTopN but satisfies every constraint, so that
+// replicas placed on the result survive the outage of a failure domain:
+//
+//	ring.LookupTopNSpread(key, 3, SpreadBy(ZoneTag), SpreadBy(RackTag))
+//
+// Nodes are taken in rank order, skipping those that would break a
+// constraint, and are only ranked until n are found, see EachCandidate.
+// Nodes without a constrained tag are not limited by it. When the domains
+// run out, it returns fewer than n nodes rather than break a constraint.
+func (r *Ring) LookupTopNSpread(key string, n int, constraints ...SpreadConstraint) []string {
+	if n <= 0 {
+		return []string{}
+	}
+	for _, c := range constraints {
+		if c.limit <= 0 {
+			return []string{}
+		}
+	}
+
+	names := make([]string, 0, n)
+	counts := make([]map[string]int, len(constraints))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	r.stream(r.load(), r.computeHash(key), active, func(s ScoredNode) bool {
+		if !spreads(s.node, constraints, counts) {
+			return true
+		}
+		for i, c := range constraints {
+			if domain, ok := s.node.tags[c.tag]; ok {
+				counts[i][domain]++
+			}
+		}
+		names = append(names, s.node.name)
+		return len(names) < n
+	})
+	return names
+}
+
+// spreads reports whether n can be added without breaking a constraint.
+func spreads(n *Node, constraints []SpreadConstraint, counts []map[string]int) bool {
+	for i, c := range constraints {
+		if domain, ok := n.tags[c.tag]; ok && counts[i][domain] == c.limit {
+			return false
+		}
+	}
+	return true
+}
+
+// LookupTopNPerZone is like LookupTopN but returns at most perZone nodes of
+// any zone, as named by the nodes' ZoneTag. It is short for LookupTopNSpread
+// with SpreadByAtMost(ZoneTag, perZone).
+func (r *Ring) LookupTopNPerZone(key string, n, perZone int) []string {
+	return r.LookupTopNSpread(key, n, SpreadByAtMost(ZoneTag, perZone))
+}
@@ -0,0 +1,99 @@
+package rendezvous
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRing_LookupTopNPerZone(t *testing.T) {
+	rv := New()
+	for i := 0; i < 9; i++ {
+		rv.AddWithTags("n"+strconv.Itoa(i), 1, map[string]string{ZoneTag: "z" + strconv.Itoa(i%3)})
+	}
+	rv.Add("untagged")
+
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		names := rv.LookupTopNPerZone(key, 3, 1)
+		if len(names) != 3 {
+			t.Fatalf("Expected 3 nodes but got %v", names)
+		}
+		zones := make(map[string]bool)
+		for _, name := range names {
+			zone, ok := rv.Tags(name)[ZoneTag]
+			if ok && zones[zone] {
+				t.Fatalf("Expected distinct zones but got %v", names)
+			}
+			zones[zone] = ok
+		}
+
+		// The result is the ranking with the nodes of full zones left out.
+		if names[0] != rv.Lookup(key) {
+			t.Errorf("Expected the owner %s first but got %v", rv.Lookup(key), names)
+		}
+	}
+
+	if names := rv.LookupTopNPerZone("foo", 10, 1); len(names) != 4 {
+		t.Errorf("Expected one node per zone and the untagged node but got %v", names)
+	}
+	if names := rv.LookupTopNPerZone("foo", 10, 2); len(names) != 7 {
+		t.Errorf("Expected two nodes per zone and the untagged node but got %v", names)
+	}
+	if names := rv.LookupTopNPerZone("foo", 10, 3); !reflect.DeepEqual(names, rv.LookupAll("foo")) {
+		t.Errorf("Expected the full ranking but got %v", names)
+	}
+}
+
+func TestRing_LookupTopNSpread(t *testing.T) {
+	rv := New()
+	for i := 0; i < 12; i++ {
+		rv.AddWithTags("n"+strconv.Itoa(i), 1, map[string]string{
+			RegionTag: "r" + strconv.Itoa(i%2),
+			ZoneTag:   "z" + strconv.Itoa(i%4),
+			RackTag:   "k" + strconv.Itoa(i%6),
+		})
+	}
+
+	for i := 0; i < 100; i++ {
+		names := rv.LookupTopNSpread(strconv.Itoa(i), 4, SpreadBy(ZoneTag), SpreadByAtMost(RegionTag, 2))
+		if len(names) != 4 {
+			t.Fatalf("Expected 4 nodes but got %v", names)
+		}
+		zones := make(map[string]bool)
+		regions := make(map[string]int)
+		for _, name := range names {
+			tags := rv.Tags(name)
+			if zones[tags[ZoneTag]] {
+				t.Fatalf("Expected distinct zones but got %v", names)
+			}
+			zones[tags[ZoneTag]] = true
+			regions[tags[RegionTag]]++
+		}
+		if regions["r0"] != 2 || regions["r1"] != 2 {
+			t.Fatalf("Expected two nodes per region but got %v", names)
+		}
+	}
+
+	if names := rv.LookupTopNSpread("foo", 10, SpreadBy(RackTag)); len(names) != 6 {
+		t.Errorf("Expected one node per rack but got %v", names)
+	}
+	if names := rv.LookupTopNSpread("foo", 3); !reflect.DeepEqual(names, rv.LookupTopN("foo", 3)) {
+		t.Errorf("Expected LookupTopN without constraints but got %v", names)
+	}
+	if names := rv.LookupTopNSpread("foo", 3, SpreadByAtMost(RackTag, 0)); len(names) != 0 {
+		t.Errorf("Expected no nodes but got %v", names)
+	}
+}
+
+func BenchmarkRing_LookupTopNSpread(b *testing.B) {
+	rv := New()
+	for i := 0; i < 10000; i++ {
+		rv.AddWithTags("n"+strconv.Itoa(i), 1, map[string]string{ZoneTag: "z" + strconv.Itoa(i%8)})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rv.LookupTopNSpread(strconv.Itoa(i), 3, SpreadBy(ZoneTag))
+	}
+}